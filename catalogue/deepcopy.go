@@ -0,0 +1,57 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import "reflect"
+
+// The DeepCopy and DeepCopyInto methods of the catalogue types are generated: run go generate after changing them.
+// The copies assume the values are trees, as they are when decoded from JSON: a value reachable twice
+// is copied twice, and a cycle never ends.
+//go:generate go run ./internal/deepcopy-gen
+
+// deepCopyInterface copies the value of an interface field: a catalogue type, through its DeepCopy method,
+// or a value decoded from JSON. Any other value is shared.
+func deepCopyInterface(v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for key, value := range t {
+			out[key] = deepCopyInterface(value)
+		}
+		return out
+
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, value := range t {
+			out[i] = deepCopyInterface(value)
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return v
+	}
+	if m := rv.MethodByName("DeepCopy"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+		return m.Call(nil)[0].Interface()
+	}
+
+	return v
+}
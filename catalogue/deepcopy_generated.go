@@ -0,0 +1,2323 @@
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package catalogue
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *AutoScalePolicy) DeepCopyInto(out *AutoScalePolicy) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.Alarms != nil {
+		out.Alarms = make([]*ScalingAlarm, len(in.Alarms))
+		copy(out.Alarms, in.Alarms)
+		for i := range in.Alarms {
+			out.Alarms[i] = in.Alarms[i].DeepCopy()
+		}
+	}
+	if in.Actions != nil {
+		out.Actions = make([]*ScalingAction, len(in.Actions))
+		copy(out.Actions, in.Actions)
+		for i := range in.Actions {
+			out.Actions[i] = in.Actions[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *AutoScalePolicy) DeepCopy() *AutoScalePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScalePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *BaseEntity) DeepCopyInto(out *BaseEntity) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *BaseEntity) DeepCopy() *BaseEntity {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseEntity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *BaseNetwork) DeepCopyInto(out *BaseNetwork) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *BaseNetwork) DeepCopy() *BaseNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *BaseNfvImage) DeepCopyInto(out *BaseNfvImage) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *BaseNfvImage) DeepCopy() *BaseNfvImage {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseNfvImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *BaseVimInstance) DeepCopyInto(out *BaseVimInstance) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	out.Location = in.Location.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *BaseVimInstance) DeepCopy() *BaseVimInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseVimInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Change) DeepCopyInto(out *Change) {
+	*out = *in
+	out.Old = deepCopyInterface(in.Old)
+	out.New = deepCopyInterface(in.New)
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Change) DeepCopy() *Change {
+	if in == nil {
+		return nil
+	}
+	out := new(Change)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in Changes) DeepCopy() Changes {
+	if in == nil {
+		return nil
+	}
+	out := make(Changes, len(in))
+	copy(out, in)
+	for i := range in {
+		out[i] = in[i].DeepCopy()
+	}
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Configuration) DeepCopyInto(out *Configuration) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.ConfigurationParameters != nil {
+		out.ConfigurationParameters = make([]*ConfigurationParameter, len(in.ConfigurationParameters))
+		copy(out.ConfigurationParameters, in.ConfigurationParameters)
+		for i := range in.ConfigurationParameters {
+			out.ConfigurationParameters[i] = in.ConfigurationParameters[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Configuration) DeepCopy() *Configuration {
+	if in == nil {
+		return nil
+	}
+	out := new(Configuration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ConfigurationParameter) DeepCopyInto(out *ConfigurationParameter) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ConfigurationParameter) DeepCopy() *ConfigurationParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ConnectionPoint) DeepCopyInto(out *ConnectionPoint) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ConnectionPoint) DeepCopy() *ConnectionPoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionPoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ConstituentVDU) DeepCopyInto(out *ConstituentVDU) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ConstituentVDU) DeepCopy() *ConstituentVDU {
+	if in == nil {
+		return nil
+	}
+	out := new(ConstituentVDU)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ConstituentVNF) DeepCopyInto(out *ConstituentVNF) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ConstituentVNF) DeepCopy() *ConstituentVNF {
+	if in == nil {
+		return nil
+	}
+	out := new(ConstituentVNF)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Criteria) DeepCopyInto(out *Criteria) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Criteria) DeepCopy() *Criteria {
+	if in == nil {
+		return nil
+	}
+	out := new(Criteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DependencyParameters) DeepCopyInto(out *DependencyParameters) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.Parameters != nil {
+		out.Parameters = make(map[string]string, len(in.Parameters))
+		for key, value := range in.Parameters {
+			out.Parameters[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *DependencyParameters) DeepCopy() *DependencyParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DependencySource) DeepCopyInto(out *DependencySource) {
+	*out = *in
+	if in.VNFRIDs != nil {
+		out.VNFRIDs = make([]string, len(in.VNFRIDs))
+		copy(out.VNFRIDs, in.VNFRIDs)
+	}
+	if in.Parameters != nil {
+		out.Parameters = make(map[string]string, len(in.Parameters))
+		for key, value := range in.Parameters {
+			out.Parameters[key] = value
+		}
+	}
+	if in.VNFCs != nil {
+		out.VNFCs = make([]*VNFCParameters, len(in.VNFCs))
+		copy(out.VNFCs, in.VNFCs)
+		for i := range in.VNFCs {
+			out.VNFCs[i] = in.VNFCs[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *DependencySource) DeepCopy() *DependencySource {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DeploymentFlavour) DeepCopyInto(out *DeploymentFlavour) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *DeploymentFlavour) DeepCopy() *DeploymentFlavour {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentFlavour)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DockerImage) DeepCopyInto(out *DockerImage) {
+	*out = *in
+	in.BaseNfvImage.DeepCopyInto(&out.BaseNfvImage)
+	if in.Tags != nil {
+		out.Tags = make([]string, len(in.Tags))
+		copy(out.Tags, in.Tags)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *DockerImage) DeepCopy() *DockerImage {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DockerNetwork) DeepCopyInto(out *DockerNetwork) {
+	*out = *in
+	in.BaseNetwork.DeepCopyInto(&out.BaseNetwork)
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *DockerNetwork) DeepCopy() *DockerNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *DockerVimInstance) DeepCopyInto(out *DockerVimInstance) {
+	*out = *in
+	in.BaseVimInstance.DeepCopyInto(&out.BaseVimInstance)
+	if in.Images != nil {
+		out.Images = make([]DockerImage, len(in.Images))
+		copy(out.Images, in.Images)
+		for i := range in.Images {
+			in.Images[i].DeepCopyInto(&out.Images[i])
+		}
+	}
+	if in.Networks != nil {
+		out.Networks = make([]DockerNetwork, len(in.Networks))
+		copy(out.Networks, in.Networks)
+		for i := range in.Networks {
+			in.Networks[i].DeepCopyInto(&out.Networks[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *DockerVimInstance) DeepCopy() *DockerVimInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerVimInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Endpoint) DeepCopyInto(out *Endpoint) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Endpoint) DeepCopy() *Endpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(Endpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *FaultManagementPolicy) DeepCopyInto(out *FaultManagementPolicy) {
+	*out = *in
+	if in.Criteria != nil {
+		out.Criteria = make([]*Criteria, len(in.Criteria))
+		copy(out.Criteria, in.Criteria)
+		for i := range in.Criteria {
+			out.Criteria[i] = in.Criteria[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *FaultManagementPolicy) DeepCopy() *FaultManagementPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FaultManagementPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Finding) DeepCopyInto(out *Finding) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Finding) DeepCopy() *Finding {
+	if in == nil {
+		return nil
+	}
+	out := new(Finding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in Findings) DeepCopy() Findings {
+	if in == nil {
+		return nil
+	}
+	out := make(Findings, len(in))
+	copy(out, in)
+	for i := range in {
+		out[i] = in[i].DeepCopy()
+	}
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *HighAvailability) DeepCopyInto(out *HighAvailability) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *HighAvailability) DeepCopy() *HighAvailability {
+	if in == nil {
+		return nil
+	}
+	out := new(HighAvailability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *HistoryLifecycleEvent) DeepCopyInto(out *HistoryLifecycleEvent) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *HistoryLifecycleEvent) DeepCopy() *HistoryLifecycleEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryLifecycleEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *IP) DeepCopyInto(out *IP) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *IP) DeepCopy() *IP {
+	if in == nil {
+		return nil
+	}
+	out := new(IP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *InternalVirtualLink) DeepCopyInto(out *InternalVirtualLink) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.QoS != nil {
+		out.QoS = make([]string, len(in.QoS))
+		copy(out.QoS, in.QoS)
+	}
+	if in.TestAccess != nil {
+		out.TestAccess = make([]string, len(in.TestAccess))
+		copy(out.TestAccess, in.TestAccess)
+	}
+	if in.ConnectivityType != nil {
+		out.ConnectivityType = make([]string, len(in.ConnectivityType))
+		copy(out.ConnectivityType, in.ConnectivityType)
+	}
+	if in.ConnectionPointsReferences != nil {
+		out.ConnectionPointsReferences = make([]string, len(in.ConnectionPointsReferences))
+		copy(out.ConnectionPointsReferences, in.ConnectionPointsReferences)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *InternalVirtualLink) DeepCopy() *InternalVirtualLink {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalVirtualLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Key) DeepCopyInto(out *Key) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Key) DeepCopy() *Key {
+	if in == nil {
+		return nil
+	}
+	out := new(Key)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *KubernatesNamespeces) DeepCopyInto(out *KubernatesNamespeces) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *KubernatesNamespeces) DeepCopy() *KubernatesNamespeces {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernatesNamespeces)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *KubernetesVimInstance) DeepCopyInto(out *KubernetesVimInstance) {
+	*out = *in
+	in.BaseVimInstance.DeepCopyInto(&out.BaseVimInstance)
+	if in.Namespaces != nil {
+		out.Namespaces = make([]*KubernatesNamespeces, len(in.Namespaces))
+		copy(out.Namespaces, in.Namespaces)
+		for i := range in.Namespaces {
+			out.Namespaces[i] = in.Namespaces[i].DeepCopy()
+		}
+	}
+	if in.Images != nil {
+		out.Images = make([]string, len(in.Images))
+		copy(out.Images, in.Images)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *KubernetesVimInstance) DeepCopy() *KubernetesVimInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesVimInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *LifecycleEvent) DeepCopyInto(out *LifecycleEvent) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.LifecycleEvents != nil {
+		out.LifecycleEvents = make([]string, len(in.LifecycleEvents))
+		copy(out.LifecycleEvents, in.LifecycleEvents)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *LifecycleEvent) DeepCopy() *LifecycleEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in LifecycleEvents) DeepCopy() LifecycleEvents {
+	if in == nil {
+		return nil
+	}
+	out := make(LifecycleEvents, len(in))
+	copy(out, in)
+	for i := range in {
+		out[i] = in[i].DeepCopy()
+	}
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Location) DeepCopyInto(out *Location) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Location) DeepCopy() *Location {
+	if in == nil {
+		return nil
+	}
+	out := new(Location)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ManagerCredentials) DeepCopyInto(out *ManagerCredentials) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ManagerCredentials) DeepCopy() *ManagerCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ManagerUnregisterMessage) DeepCopyInto(out *ManagerUnregisterMessage) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ManagerUnregisterMessage) DeepCopy() *ManagerUnregisterMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerUnregisterMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *NFVEntityDescriptor) DeepCopyInto(out *NFVEntityDescriptor) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.VNFFGDs != nil {
+		out.VNFFGDs = make([]*VNFForwardingGraphDescriptor, len(in.VNFFGDs))
+		copy(out.VNFFGDs, in.VNFFGDs)
+		for i := range in.VNFFGDs {
+			out.VNFFGDs[i] = in.VNFFGDs[i].DeepCopy()
+		}
+	}
+	if in.VLDs != nil {
+		out.VLDs = make([]*VirtualLinkDescriptor, len(in.VLDs))
+		copy(out.VLDs, in.VLDs)
+		for i := range in.VLDs {
+			out.VLDs[i] = in.VLDs[i].DeepCopy()
+		}
+	}
+	if in.MonitoringParameters != nil {
+		out.MonitoringParameters = make([]string, len(in.MonitoringParameters))
+		copy(out.MonitoringParameters, in.MonitoringParameters)
+	}
+	if in.ServiceDeploymentFlavours != nil {
+		out.ServiceDeploymentFlavours = make([]*DeploymentFlavour, len(in.ServiceDeploymentFlavours))
+		copy(out.ServiceDeploymentFlavours, in.ServiceDeploymentFlavours)
+		for i := range in.ServiceDeploymentFlavours {
+			out.ServiceDeploymentFlavours[i] = in.ServiceDeploymentFlavours[i].DeepCopy()
+		}
+	}
+	if in.AutoScalePolicies != nil {
+		out.AutoScalePolicies = make([]*AutoScalePolicy, len(in.AutoScalePolicies))
+		copy(out.AutoScalePolicies, in.AutoScalePolicies)
+		for i := range in.AutoScalePolicies {
+			out.AutoScalePolicies[i] = in.AutoScalePolicies[i].DeepCopy()
+		}
+	}
+	if in.ConnectionPoints != nil {
+		out.ConnectionPoints = make([]*ConnectionPoint, len(in.ConnectionPoints))
+		copy(out.ConnectionPoints, in.ConnectionPoints)
+		for i := range in.ConnectionPoints {
+			out.ConnectionPoints[i] = in.ConnectionPoints[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *NFVEntityDescriptor) DeepCopy() *NFVEntityDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(NFVEntityDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *NetworkForwardingPath) DeepCopyInto(out *NetworkForwardingPath) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	out.Policy = in.Policy.DeepCopy()
+	if in.Connection != nil {
+		out.Connection = make(map[string]string, len(in.Connection))
+		for key, value := range in.Connection {
+			out.Connection[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *NetworkForwardingPath) DeepCopy() *NetworkForwardingPath {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkForwardingPath)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *NetworkServiceDeploymentFlavour) DeepCopyInto(out *NetworkServiceDeploymentFlavour) {
+	*out = *in
+	in.DeploymentFlavour.DeepCopyInto(&out.DeploymentFlavour)
+	if in.VNFFGRReference != nil {
+		out.VNFFGRReference = make([]*VNFForwardingGraphRecord, len(in.VNFFGRReference))
+		copy(out.VNFFGRReference, in.VNFFGRReference)
+		for i := range in.VNFFGRReference {
+			out.VNFFGRReference[i] = in.VNFFGRReference[i].DeepCopy()
+		}
+	}
+	if in.AllocatedCapacity != nil {
+		out.AllocatedCapacity = make([]string, len(in.AllocatedCapacity))
+		copy(out.AllocatedCapacity, in.AllocatedCapacity)
+	}
+	if in.Notification != nil {
+		out.Notification = make([]string, len(in.Notification))
+		copy(out.Notification, in.Notification)
+	}
+	out.LifecycleEventHistory = in.LifecycleEventHistory.DeepCopy()
+	if in.AuditLog != nil {
+		out.AuditLog = make([]string, len(in.AuditLog))
+		copy(out.AuditLog, in.AuditLog)
+	}
+	if in.Connection != nil {
+		out.Connection = make([]string, len(in.Connection))
+		copy(out.Connection, in.Connection)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *NetworkServiceDeploymentFlavour) DeepCopy() *NetworkServiceDeploymentFlavour {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkServiceDeploymentFlavour)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *NetworkServiceDescriptor) DeepCopyInto(out *NetworkServiceDescriptor) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.VNFFGDs != nil {
+		out.VNFFGDs = make([]*VNFForwardingGraphDescriptor, len(in.VNFFGDs))
+		copy(out.VNFFGDs, in.VNFFGDs)
+		for i := range in.VNFFGDs {
+			out.VNFFGDs[i] = in.VNFFGDs[i].DeepCopy()
+		}
+	}
+	if in.VLDs != nil {
+		out.VLDs = make([]*VirtualLinkDescriptor, len(in.VLDs))
+		copy(out.VLDs, in.VLDs)
+		for i := range in.VLDs {
+			out.VLDs[i] = in.VLDs[i].DeepCopy()
+		}
+	}
+	if in.MonitoringParameters != nil {
+		out.MonitoringParameters = make([]string, len(in.MonitoringParameters))
+		copy(out.MonitoringParameters, in.MonitoringParameters)
+	}
+	if in.ServiceDeploymentFlavours != nil {
+		out.ServiceDeploymentFlavours = make([]*DeploymentFlavour, len(in.ServiceDeploymentFlavours))
+		copy(out.ServiceDeploymentFlavours, in.ServiceDeploymentFlavours)
+		for i := range in.ServiceDeploymentFlavours {
+			out.ServiceDeploymentFlavours[i] = in.ServiceDeploymentFlavours[i].DeepCopy()
+		}
+	}
+	if in.AutoScalePolicies != nil {
+		out.AutoScalePolicies = make([]*AutoScalePolicy, len(in.AutoScalePolicies))
+		copy(out.AutoScalePolicies, in.AutoScalePolicies)
+		for i := range in.AutoScalePolicies {
+			out.AutoScalePolicies[i] = in.AutoScalePolicies[i].DeepCopy()
+		}
+	}
+	if in.ConnectionPoints != nil {
+		out.ConnectionPoints = make([]*ConnectionPoint, len(in.ConnectionPoints))
+		copy(out.ConnectionPoints, in.ConnectionPoints)
+		for i := range in.ConnectionPoints {
+			out.ConnectionPoints[i] = in.ConnectionPoints[i].DeepCopy()
+		}
+	}
+	if in.VNFDs != nil {
+		out.VNFDs = make([]*VirtualNetworkFunctionDescriptor, len(in.VNFDs))
+		copy(out.VNFDs, in.VNFDs)
+		for i := range in.VNFDs {
+			out.VNFDs[i] = in.VNFDs[i].DeepCopy()
+		}
+	}
+	if in.PNFDs != nil {
+		out.PNFDs = make([]*PhysicalNetworkFunctionDescriptor, len(in.PNFDs))
+		copy(out.PNFDs, in.PNFDs)
+		for i := range in.PNFDs {
+			out.PNFDs[i] = in.PNFDs[i].DeepCopy()
+		}
+	}
+	if in.VNFDependencies != nil {
+		out.VNFDependencies = make([]*VNFDependency, len(in.VNFDependencies))
+		copy(out.VNFDependencies, in.VNFDependencies)
+		for i := range in.VNFDependencies {
+			out.VNFDependencies[i] = in.VNFDependencies[i].DeepCopy()
+		}
+	}
+	out.NSDSecurity = in.NSDSecurity.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *NetworkServiceDescriptor) DeepCopy() *NetworkServiceDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkServiceDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *NetworkServiceRecord) DeepCopyInto(out *NetworkServiceRecord) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.AutoScalePolicy != nil {
+		out.AutoScalePolicy = make([]*AutoScalePolicy, len(in.AutoScalePolicy))
+		copy(out.AutoScalePolicy, in.AutoScalePolicy)
+		for i := range in.AutoScalePolicy {
+			out.AutoScalePolicy[i] = in.AutoScalePolicy[i].DeepCopy()
+		}
+	}
+	if in.ConnectionPoint != nil {
+		out.ConnectionPoint = make([]*ConnectionPoint, len(in.ConnectionPoint))
+		copy(out.ConnectionPoint, in.ConnectionPoint)
+		for i := range in.ConnectionPoint {
+			out.ConnectionPoint[i] = in.ConnectionPoint[i].DeepCopy()
+		}
+	}
+	if in.MonitoringParameter != nil {
+		out.MonitoringParameter = make([]string, len(in.MonitoringParameter))
+		copy(out.MonitoringParameter, in.MonitoringParameter)
+	}
+	in.ServiceDeploymentFlavour.DeepCopyInto(&out.ServiceDeploymentFlavour)
+	if in.VLR != nil {
+		out.VLR = make([]*VirtualLinkRecord, len(in.VLR))
+		copy(out.VLR, in.VLR)
+		for i := range in.VLR {
+			out.VLR[i] = in.VLR[i].DeepCopy()
+		}
+	}
+	if in.VNFR != nil {
+		out.VNFR = make([]*VirtualNetworkFunctionRecord, len(in.VNFR))
+		copy(out.VNFR, in.VNFR)
+		for i := range in.VNFR {
+			out.VNFR[i] = in.VNFR[i].DeepCopy()
+		}
+	}
+	if in.VNFDependency != nil {
+		out.VNFDependency = make([]*VNFRecordDependency, len(in.VNFDependency))
+		copy(out.VNFDependency, in.VNFDependency)
+		for i := range in.VNFDependency {
+			out.VNFDependency[i] = in.VNFDependency[i].DeepCopy()
+		}
+	}
+	out.LifecycleEvents = in.LifecycleEvents.DeepCopy()
+	if in.VNFFGR != nil {
+		out.VNFFGR = make([]*VNFForwardingGraphRecord, len(in.VNFFGR))
+		copy(out.VNFFGR, in.VNFFGR)
+		for i := range in.VNFFGR {
+			out.VNFFGR[i] = in.VNFFGR[i].DeepCopy()
+		}
+	}
+	if in.PNFR != nil {
+		out.PNFR = make([]*PhysicalNetworkFunctionRecord, len(in.PNFR))
+		copy(out.PNFR, in.PNFR)
+		for i := range in.PNFR {
+			out.PNFR[i] = in.PNFR[i].DeepCopy()
+		}
+	}
+	if in.FaultManagementPolicy != nil {
+		out.FaultManagementPolicy = make([]*FaultManagementPolicy, len(in.FaultManagementPolicy))
+		copy(out.FaultManagementPolicy, in.FaultManagementPolicy)
+		for i := range in.FaultManagementPolicy {
+			out.FaultManagementPolicy[i] = in.FaultManagementPolicy[i].DeepCopy()
+		}
+	}
+	out.LifecycleEventHistory = in.LifecycleEventHistory.DeepCopy()
+	if in.KeyNames != nil {
+		out.KeyNames = make([]string, len(in.KeyNames))
+		copy(out.KeyNames, in.KeyNames)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *NetworkServiceRecord) DeepCopy() *NetworkServiceRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkServiceRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *OpenstackVimInstance) DeepCopyInto(out *OpenstackVimInstance) {
+	*out = *in
+	in.BaseVimInstance.DeepCopyInto(&out.BaseVimInstance)
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *OpenstackVimInstance) DeepCopy() *OpenstackVimInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenstackVimInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *PhysicalNetworkFunctionDescriptor) DeepCopyInto(out *PhysicalNetworkFunctionDescriptor) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.ConnectionPoints != nil {
+		out.ConnectionPoints = make([]*ConnectionPoint, len(in.ConnectionPoints))
+		copy(out.ConnectionPoints, in.ConnectionPoints)
+		for i := range in.ConnectionPoints {
+			out.ConnectionPoints[i] = in.ConnectionPoints[i].DeepCopy()
+		}
+	}
+	if in.VirtualLinks != nil {
+		out.VirtualLinks = make([]*VirtualLinkDescriptor, len(in.VirtualLinks))
+		copy(out.VirtualLinks, in.VirtualLinks)
+		for i := range in.VirtualLinks {
+			out.VirtualLinks[i] = in.VirtualLinks[i].DeepCopy()
+		}
+	}
+	if in.DeploymentFlavours != nil {
+		out.DeploymentFlavours = make([]*DeploymentFlavour, len(in.DeploymentFlavours))
+		copy(out.DeploymentFlavours, in.DeploymentFlavours)
+		for i := range in.DeploymentFlavours {
+			out.DeploymentFlavours[i] = in.DeploymentFlavours[i].DeepCopy()
+		}
+	}
+	out.PNFDSecurity = in.PNFDSecurity.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *PhysicalNetworkFunctionDescriptor) DeepCopy() *PhysicalNetworkFunctionDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(PhysicalNetworkFunctionDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *PhysicalNetworkFunctionRecord) DeepCopyInto(out *PhysicalNetworkFunctionRecord) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.ConnectionPoint != nil {
+		out.ConnectionPoint = make([]*ConnectionPoint, len(in.ConnectionPoint))
+		copy(out.ConnectionPoint, in.ConnectionPoint)
+		for i := range in.ConnectionPoint {
+			out.ConnectionPoint[i] = in.ConnectionPoint[i].DeepCopy()
+		}
+	}
+	if in.VNFFGR != nil {
+		out.VNFFGR = make([]*VNFForwardingGraphRecord, len(in.VNFFGR))
+		copy(out.VNFFGR, in.VNFFGR)
+		for i := range in.VNFFGR {
+			out.VNFFGR[i] = in.VNFFGR[i].DeepCopy()
+		}
+	}
+	if in.ConnectedVirtualLink != nil {
+		out.ConnectedVirtualLink = make([]*VirtualLinkRecord, len(in.ConnectedVirtualLink))
+		copy(out.ConnectedVirtualLink, in.ConnectedVirtualLink)
+		for i := range in.ConnectedVirtualLink {
+			out.ConnectedVirtualLink[i] = in.ConnectedVirtualLink[i].DeepCopy()
+		}
+	}
+	if in.PNFAddress != nil {
+		out.PNFAddress = make([]string, len(in.PNFAddress))
+		copy(out.PNFAddress, in.PNFAddress)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *PhysicalNetworkFunctionRecord) DeepCopy() *PhysicalNetworkFunctionRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(PhysicalNetworkFunctionRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *PluginRegisterMessage) DeepCopyInto(out *PluginRegisterMessage) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *PluginRegisterMessage) DeepCopy() *PluginRegisterMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginRegisterMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Policy) DeepCopyInto(out *Policy) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Policy) DeepCopy() *Policy {
+	if in == nil {
+		return nil
+	}
+	out := new(Policy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Quota) DeepCopyInto(out *Quota) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Quota) DeepCopy() *Quota {
+	if in == nil {
+		return nil
+	}
+	out := new(Quota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *RequiresParameters) DeepCopyInto(out *RequiresParameters) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.Parameters != nil {
+		out.Parameters = make([]string, len(in.Parameters))
+		copy(out.Parameters, in.Parameters)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *RequiresParameters) DeepCopy() *RequiresParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiresParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ResolvedDependency) DeepCopyInto(out *ResolvedDependency) {
+	*out = *in
+	if in.Sources != nil {
+		out.Sources = make([]*DependencySource, len(in.Sources))
+		copy(out.Sources, in.Sources)
+		for i := range in.Sources {
+			out.Sources[i] = in.Sources[i].DeepCopy()
+		}
+	}
+	if in.Missing != nil {
+		out.Missing = make([]string, len(in.Missing))
+		copy(out.Missing, in.Missing)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ResolvedDependency) DeepCopy() *ResolvedDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ScalingAction) DeepCopyInto(out *ScalingAction) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ScalingAction) DeepCopy() *ScalingAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *ScalingAlarm) DeepCopyInto(out *ScalingAlarm) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *ScalingAlarm) DeepCopy() *ScalingAlarm {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingAlarm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Script) DeepCopyInto(out *Script) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.Payload != nil {
+		out.Payload = make([]byte, len(in.Payload))
+		copy(out.Payload, in.Payload)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Script) DeepCopy() *Script {
+	if in == nil {
+		return nil
+	}
+	out := new(Script)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Security) DeepCopyInto(out *Security) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Security) DeepCopy() *Security {
+	if in == nil {
+		return nil
+	}
+	out := new(Security)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Server) DeepCopyInto(out *Server) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	out.Image = deepCopyInterface(in.Image)
+	out.Flavour = in.Flavour.DeepCopy()
+	if in.IPs != nil {
+		out.IPs = make(map[string][]string, len(in.IPs))
+		for key, value := range in.IPs {
+			c := value
+			if value != nil {
+				c = make([]string, len(value))
+				copy(c, value)
+			}
+			out.IPs[key] = c
+		}
+	}
+	if in.FloatingIPs != nil {
+		out.FloatingIPs = make(map[string]string, len(in.FloatingIPs))
+		for key, value := range in.FloatingIPs {
+			out.FloatingIPs[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Server) DeepCopy() *Server {
+	if in == nil {
+		return nil
+	}
+	out := new(Server)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *StateMachine) DeepCopyInto(out *StateMachine) {
+	*out = *in
+	if in.transitions != nil {
+		out.transitions = make(map[Status][]Status, len(in.transitions))
+		for key, value := range in.transitions {
+			c := value
+			if value != nil {
+				c = make([]Status, len(value))
+				copy(c, value)
+			}
+			out.transitions[key] = c
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *StateMachine) DeepCopy() *StateMachine {
+	if in == nil {
+		return nil
+	}
+	out := new(StateMachine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *Subnet) DeepCopyInto(out *Subnet) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *Subnet) DeepCopy() *Subnet {
+	if in == nil {
+		return nil
+	}
+	out := new(Subnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *TransitionError) DeepCopyInto(out *TransitionError) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *TransitionError) DeepCopy() *TransitionError {
+	if in == nil {
+		return nil
+	}
+	out := new(TransitionError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in TransitionErrors) DeepCopy() TransitionErrors {
+	if in == nil {
+		return nil
+	}
+	out := make(TransitionErrors, len(in))
+	copy(out, in)
+	for i := range in {
+		out[i] = in[i].DeepCopy()
+	}
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VDUDependency) DeepCopyInto(out *VDUDependency) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	out.Source = in.Source.DeepCopy()
+	out.Target = in.Target.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VDUDependency) DeepCopy() *VDUDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(VDUDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFCDependencyParameters) DeepCopyInto(out *VNFCDependencyParameters) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.Parameters != nil {
+		out.Parameters = make(map[string]*DependencyParameters, len(in.Parameters))
+		for key, value := range in.Parameters {
+			out.Parameters[key] = value.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFCDependencyParameters) DeepCopy() *VNFCDependencyParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFCDependencyParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFCInstance) DeepCopyInto(out *VNFCInstance) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.ConnectionPoints != nil {
+		out.ConnectionPoints = make([]*VNFDConnectionPoint, len(in.ConnectionPoints))
+		copy(out.ConnectionPoints, in.ConnectionPoints)
+		for i := range in.ConnectionPoints {
+			out.ConnectionPoints[i] = in.ConnectionPoints[i].DeepCopy()
+		}
+	}
+	out.VNFComponent = in.VNFComponent.DeepCopy()
+	if in.FloatingIPs != nil {
+		out.FloatingIPs = make([]*IP, len(in.FloatingIPs))
+		copy(out.FloatingIPs, in.FloatingIPs)
+		for i := range in.FloatingIPs {
+			out.FloatingIPs[i] = in.FloatingIPs[i].DeepCopy()
+		}
+	}
+	if in.IPs != nil {
+		out.IPs = make([]*IP, len(in.IPs))
+		copy(out.IPs, in.IPs)
+		for i := range in.IPs {
+			out.IPs[i] = in.IPs[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFCInstance) DeepCopy() *VNFCInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFCInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFCParameters) DeepCopyInto(out *VNFCParameters) {
+	*out = *in
+	if in.Parameters != nil {
+		out.Parameters = make(map[string]string, len(in.Parameters))
+		for key, value := range in.Parameters {
+			out.Parameters[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFCParameters) DeepCopy() *VNFCParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFCParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFComponent) DeepCopyInto(out *VNFComponent) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.ConnectionPoints != nil {
+		out.ConnectionPoints = make([]*VNFDConnectionPoint, len(in.ConnectionPoints))
+		copy(out.ConnectionPoints, in.ConnectionPoints)
+		for i := range in.ConnectionPoints {
+			out.ConnectionPoints[i] = in.ConnectionPoints[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFComponent) DeepCopy() *VNFComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFDConnectionPoint) DeepCopyInto(out *VNFDConnectionPoint) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFDConnectionPoint) DeepCopy() *VNFDConnectionPoint {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFDConnectionPoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFDependency) DeepCopyInto(out *VNFDependency) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	out.Source = in.Source.DeepCopy()
+	out.Target = in.Target.DeepCopy()
+	if in.Parameters != nil {
+		out.Parameters = make([]string, len(in.Parameters))
+		copy(out.Parameters, in.Parameters)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFDependency) DeepCopy() *VNFDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFDeploymentFlavour) DeepCopyInto(out *VNFDeploymentFlavour) {
+	*out = *in
+	in.DeploymentFlavour.DeepCopyInto(&out.DeploymentFlavour)
+	if in.DfConstraint != nil {
+		out.DfConstraint = make([]string, len(in.DfConstraint))
+		copy(out.DfConstraint, in.DfConstraint)
+	}
+	if in.ConstituentVDU != nil {
+		out.ConstituentVDU = make([]*ConstituentVDU, len(in.ConstituentVDU))
+		copy(out.ConstituentVDU, in.ConstituentVDU)
+		for i := range in.ConstituentVDU {
+			out.ConstituentVDU[i] = in.ConstituentVDU[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFDeploymentFlavour) DeepCopy() *VNFDeploymentFlavour {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFDeploymentFlavour)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFForwardingGraphDescriptor) DeepCopyInto(out *VNFForwardingGraphDescriptor) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.DependentVirtualLinks != nil {
+		out.DependentVirtualLinks = make([]*VirtualLinkDescriptor, len(in.DependentVirtualLinks))
+		copy(out.DependentVirtualLinks, in.DependentVirtualLinks)
+		for i := range in.DependentVirtualLinks {
+			out.DependentVirtualLinks[i] = in.DependentVirtualLinks[i].DeepCopy()
+		}
+	}
+	if in.NetworkForwardingPaths != nil {
+		out.NetworkForwardingPaths = make([]*NetworkForwardingPath, len(in.NetworkForwardingPaths))
+		copy(out.NetworkForwardingPaths, in.NetworkForwardingPaths)
+		for i := range in.NetworkForwardingPaths {
+			out.NetworkForwardingPaths[i] = in.NetworkForwardingPaths[i].DeepCopy()
+		}
+	}
+	if in.ConnectionPoints != nil {
+		out.ConnectionPoints = make([]*ConnectionPoint, len(in.ConnectionPoints))
+		copy(out.ConnectionPoints, in.ConnectionPoints)
+		for i := range in.ConnectionPoints {
+			out.ConnectionPoints[i] = in.ConnectionPoints[i].DeepCopy()
+		}
+	}
+	if in.ConstituentVnfs != nil {
+		out.ConstituentVnfs = make([]*ConstituentVNF, len(in.ConstituentVnfs))
+		copy(out.ConstituentVnfs, in.ConstituentVnfs)
+		for i := range in.ConstituentVnfs {
+			out.ConstituentVnfs[i] = in.ConstituentVnfs[i].DeepCopy()
+		}
+	}
+	out.VnffgdSecurity = in.VnffgdSecurity.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFForwardingGraphDescriptor) DeepCopy() *VNFForwardingGraphDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFForwardingGraphDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFForwardingGraphRecord) DeepCopyInto(out *VNFForwardingGraphRecord) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	out.DescriptorReference = in.DescriptorReference.DeepCopy()
+	out.ParentNS = in.ParentNS.DeepCopy()
+	if in.DependentVirtualLink != nil {
+		out.DependentVirtualLink = make([]*VirtualLinkRecord, len(in.DependentVirtualLink))
+		copy(out.DependentVirtualLink, in.DependentVirtualLink)
+		for i := range in.DependentVirtualLink {
+			out.DependentVirtualLink[i] = in.DependentVirtualLink[i].DeepCopy()
+		}
+	}
+	if in.Status != nil {
+		out.Status = new(Status)
+		*out.Status = *in.Status
+	}
+	if in.Notification != nil {
+		out.Notification = make([]string, len(in.Notification))
+		copy(out.Notification, in.Notification)
+	}
+	out.LifecycleEventHistory = in.LifecycleEventHistory.DeepCopy()
+	out.NetworkForwardingPath = in.NetworkForwardingPath.DeepCopy()
+	if in.ConnectionPoint != nil {
+		out.ConnectionPoint = make([]*VNFDConnectionPoint, len(in.ConnectionPoint))
+		copy(out.ConnectionPoint, in.ConnectionPoint)
+		for i := range in.ConnectionPoint {
+			out.ConnectionPoint[i] = in.ConnectionPoint[i].DeepCopy()
+		}
+	}
+	if in.MemberVNFs != nil {
+		out.MemberVNFs = make([]*VirtualNetworkFunctionRecord, len(in.MemberVNFs))
+		copy(out.MemberVNFs, in.MemberVNFs)
+		for i := range in.MemberVNFs {
+			out.MemberVNFs[i] = in.MemberVNFs[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFForwardingGraphRecord) DeepCopy() *VNFForwardingGraphRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFForwardingGraphRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFPackage) DeepCopyInto(out *VNFPackage) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.VIMTypes != nil {
+		out.VIMTypes = make([]string, len(in.VIMTypes))
+		copy(out.VIMTypes, in.VIMTypes)
+	}
+	out.Image = in.Image.DeepCopy()
+	if in.Scripts != nil {
+		out.Scripts = make([]*Script, len(in.Scripts))
+		copy(out.Scripts, in.Scripts)
+		for i := range in.Scripts {
+			out.Scripts[i] = in.Scripts[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFPackage) DeepCopy() *VNFPackage {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFPackage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VNFRecordDependency) DeepCopyInto(out *VNFRecordDependency) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.Parameters != nil {
+		out.Parameters = make(map[string]*DependencyParameters, len(in.Parameters))
+		for key, value := range in.Parameters {
+			out.Parameters[key] = value.DeepCopy()
+		}
+	}
+	if in.VNFCParameters != nil {
+		out.VNFCParameters = make(map[string]*VNFCDependencyParameters, len(in.VNFCParameters))
+		for key, value := range in.VNFCParameters {
+			out.VNFCParameters[key] = value.DeepCopy()
+		}
+	}
+	if in.IDType != nil {
+		out.IDType = make(map[string]string, len(in.IDType))
+		for key, value := range in.IDType {
+			out.IDType[key] = value
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VNFRecordDependency) DeepCopy() *VNFRecordDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(VNFRecordDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VRFaultManagementPolicy) DeepCopyInto(out *VRFaultManagementPolicy) {
+	*out = *in
+	in.FaultManagementPolicy.DeepCopyInto(&out.FaultManagementPolicy)
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VRFaultManagementPolicy) DeepCopy() *VRFaultManagementPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VRFaultManagementPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VirtualDeploymentUnit) DeepCopyInto(out *VirtualDeploymentUnit) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.VMImages != nil {
+		out.VMImages = make([]string, len(in.VMImages))
+		copy(out.VMImages, in.VMImages)
+	}
+	out.LifecycleEvents = in.LifecycleEvents.DeepCopy()
+	out.HighAvailability = in.HighAvailability.DeepCopy()
+	if in.FaultManagementPolicies != nil {
+		out.FaultManagementPolicies = make([]*VRFaultManagementPolicy, len(in.FaultManagementPolicies))
+		copy(out.FaultManagementPolicies, in.FaultManagementPolicies)
+		for i := range in.FaultManagementPolicies {
+			out.FaultManagementPolicies[i] = in.FaultManagementPolicies[i].DeepCopy()
+		}
+	}
+	if in.VNFCs != nil {
+		out.VNFCs = make([]*VNFComponent, len(in.VNFCs))
+		copy(out.VNFCs, in.VNFCs)
+		for i := range in.VNFCs {
+			out.VNFCs[i] = in.VNFCs[i].DeepCopy()
+		}
+	}
+	if in.VNFCInstances != nil {
+		out.VNFCInstances = make([]*VNFCInstance, len(in.VNFCInstances))
+		copy(out.VNFCInstances, in.VNFCInstances)
+		for i := range in.VNFCInstances {
+			out.VNFCInstances[i] = in.VNFCInstances[i].DeepCopy()
+		}
+	}
+	if in.MonitoringParameters != nil {
+		out.MonitoringParameters = make([]string, len(in.MonitoringParameters))
+		copy(out.MonitoringParameters, in.MonitoringParameters)
+	}
+	if in.VIMInstanceNames != nil {
+		out.VIMInstanceNames = make([]string, len(in.VIMInstanceNames))
+		copy(out.VIMInstanceNames, in.VIMInstanceNames)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VirtualDeploymentUnit) DeepCopy() *VirtualDeploymentUnit {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualDeploymentUnit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VirtualLink) DeepCopyInto(out *VirtualLink) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.QoS != nil {
+		out.QoS = make([]string, len(in.QoS))
+		copy(out.QoS, in.QoS)
+	}
+	if in.TestAccess != nil {
+		out.TestAccess = make([]string, len(in.TestAccess))
+		copy(out.TestAccess, in.TestAccess)
+	}
+	if in.ConnectivityType != nil {
+		out.ConnectivityType = make([]string, len(in.ConnectivityType))
+		copy(out.ConnectivityType, in.ConnectivityType)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VirtualLink) DeepCopy() *VirtualLink {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VirtualLinkDescriptor) DeepCopyInto(out *VirtualLinkDescriptor) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.QoS != nil {
+		out.QoS = make([]string, len(in.QoS))
+		copy(out.QoS, in.QoS)
+	}
+	if in.TestAccess != nil {
+		out.TestAccess = make([]string, len(in.TestAccess))
+		copy(out.TestAccess, in.TestAccess)
+	}
+	if in.ConnectivityType != nil {
+		out.ConnectivityType = make([]string, len(in.ConnectivityType))
+		copy(out.ConnectivityType, in.ConnectivityType)
+	}
+	if in.Connections != nil {
+		out.Connections = make([]string, len(in.Connections))
+		copy(out.Connections, in.Connections)
+	}
+	out.VLDSecurity = in.VLDSecurity.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VirtualLinkDescriptor) DeepCopy() *VirtualLinkDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualLinkDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VirtualLinkRecord) DeepCopyInto(out *VirtualLinkRecord) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.QoS != nil {
+		out.QoS = make([]string, len(in.QoS))
+		copy(out.QoS, in.QoS)
+	}
+	if in.TestAccess != nil {
+		out.TestAccess = make([]string, len(in.TestAccess))
+		copy(out.TestAccess, in.TestAccess)
+	}
+	if in.ConnectivityType != nil {
+		out.ConnectivityType = make([]string, len(in.ConnectivityType))
+		copy(out.ConnectivityType, in.ConnectivityType)
+	}
+	if in.VNFFGRReference != nil {
+		out.VNFFGRReference = make([]*VNFForwardingGraphRecord, len(in.VNFFGRReference))
+		copy(out.VNFFGRReference, in.VNFFGRReference)
+		for i := range in.VNFFGRReference {
+			out.VNFFGRReference[i] = in.VNFFGRReference[i].DeepCopy()
+		}
+	}
+	if in.AllocatedCapacity != nil {
+		out.AllocatedCapacity = make([]string, len(in.AllocatedCapacity))
+		copy(out.AllocatedCapacity, in.AllocatedCapacity)
+	}
+	if in.Notification != nil {
+		out.Notification = make([]string, len(in.Notification))
+		copy(out.Notification, in.Notification)
+	}
+	out.LifecycleEventHistory = in.LifecycleEventHistory.DeepCopy()
+	if in.AuditLog != nil {
+		out.AuditLog = make([]string, len(in.AuditLog))
+		copy(out.AuditLog, in.AuditLog)
+	}
+	if in.Connection != nil {
+		out.Connection = make([]string, len(in.Connection))
+		copy(out.Connection, in.Connection)
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VirtualLinkRecord) DeepCopy() *VirtualLinkRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualLinkRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VirtualNetworkFunctionDescriptor) DeepCopyInto(out *VirtualNetworkFunctionDescriptor) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.VNFFGDs != nil {
+		out.VNFFGDs = make([]*VNFForwardingGraphDescriptor, len(in.VNFFGDs))
+		copy(out.VNFFGDs, in.VNFFGDs)
+		for i := range in.VNFFGDs {
+			out.VNFFGDs[i] = in.VNFFGDs[i].DeepCopy()
+		}
+	}
+	if in.VLDs != nil {
+		out.VLDs = make([]*VirtualLinkDescriptor, len(in.VLDs))
+		copy(out.VLDs, in.VLDs)
+		for i := range in.VLDs {
+			out.VLDs[i] = in.VLDs[i].DeepCopy()
+		}
+	}
+	if in.MonitoringParameters != nil {
+		out.MonitoringParameters = make([]string, len(in.MonitoringParameters))
+		copy(out.MonitoringParameters, in.MonitoringParameters)
+	}
+	if in.ServiceDeploymentFlavours != nil {
+		out.ServiceDeploymentFlavours = make([]*DeploymentFlavour, len(in.ServiceDeploymentFlavours))
+		copy(out.ServiceDeploymentFlavours, in.ServiceDeploymentFlavours)
+		for i := range in.ServiceDeploymentFlavours {
+			out.ServiceDeploymentFlavours[i] = in.ServiceDeploymentFlavours[i].DeepCopy()
+		}
+	}
+	if in.AutoScalePolicies != nil {
+		out.AutoScalePolicies = make([]*AutoScalePolicy, len(in.AutoScalePolicies))
+		copy(out.AutoScalePolicies, in.AutoScalePolicies)
+		for i := range in.AutoScalePolicies {
+			out.AutoScalePolicies[i] = in.AutoScalePolicies[i].DeepCopy()
+		}
+	}
+	if in.ConnectionPoints != nil {
+		out.ConnectionPoints = make([]*ConnectionPoint, len(in.ConnectionPoints))
+		copy(out.ConnectionPoints, in.ConnectionPoints)
+		for i := range in.ConnectionPoints {
+			out.ConnectionPoints[i] = in.ConnectionPoints[i].DeepCopy()
+		}
+	}
+	out.LifecycleEvents = in.LifecycleEvents.DeepCopy()
+	out.Configurations = in.Configurations.DeepCopy()
+	if in.VDUs != nil {
+		out.VDUs = make([]*VirtualDeploymentUnit, len(in.VDUs))
+		copy(out.VDUs, in.VDUs)
+		for i := range in.VDUs {
+			out.VDUs[i] = in.VDUs[i].DeepCopy()
+		}
+	}
+	if in.VirtualLinks != nil {
+		out.VirtualLinks = make([]*InternalVirtualLink, len(in.VirtualLinks))
+		copy(out.VirtualLinks, in.VirtualLinks)
+		for i := range in.VirtualLinks {
+			out.VirtualLinks[i] = in.VirtualLinks[i].DeepCopy()
+		}
+	}
+	if in.VDUDependencies != nil {
+		out.VDUDependencies = make([]*VDUDependency, len(in.VDUDependencies))
+		copy(out.VDUDependencies, in.VDUDependencies)
+		for i := range in.VDUDependencies {
+			out.VDUDependencies[i] = in.VDUDependencies[i].DeepCopy()
+		}
+	}
+	if in.DeploymentFlavours != nil {
+		out.DeploymentFlavours = make([]*VNFDeploymentFlavour, len(in.DeploymentFlavours))
+		copy(out.DeploymentFlavours, in.DeploymentFlavours)
+		for i := range in.DeploymentFlavours {
+			out.DeploymentFlavours[i] = in.DeploymentFlavours[i].DeepCopy()
+		}
+	}
+	if in.ManifestFileSecurity != nil {
+		out.ManifestFileSecurity = make([]*Security, len(in.ManifestFileSecurity))
+		copy(out.ManifestFileSecurity, in.ManifestFileSecurity)
+		for i := range in.ManifestFileSecurity {
+			out.ManifestFileSecurity[i] = in.ManifestFileSecurity[i].DeepCopy()
+		}
+	}
+	if in.Requires != nil {
+		out.Requires = make(map[string]*RequiresParameters, len(in.Requires))
+		for key, value := range in.Requires {
+			out.Requires[key] = value.DeepCopy()
+		}
+	}
+	if in.Provides != nil {
+		out.Provides = make([]string, len(in.Provides))
+		copy(out.Provides, in.Provides)
+	}
+	if in.VNFDConnectionPoints != nil {
+		out.VNFDConnectionPoints = make([]*VNFDConnectionPoint, len(in.VNFDConnectionPoints))
+		copy(out.VNFDConnectionPoints, in.VNFDConnectionPoints)
+		for i := range in.VNFDConnectionPoints {
+			out.VNFDConnectionPoints[i] = in.VNFDConnectionPoints[i].DeepCopy()
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VirtualNetworkFunctionDescriptor) DeepCopy() *VirtualNetworkFunctionDescriptor {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNetworkFunctionDescriptor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VirtualNetworkFunctionRecord) DeepCopyInto(out *VirtualNetworkFunctionRecord) {
+	*out = *in
+	if in.Metadata != nil {
+		out.Metadata = make(map[string]string, len(in.Metadata))
+		for key, value := range in.Metadata {
+			out.Metadata[key] = value
+		}
+	}
+	if in.AutoScalePolicies != nil {
+		out.AutoScalePolicies = make([]*AutoScalePolicy, len(in.AutoScalePolicies))
+		copy(out.AutoScalePolicies, in.AutoScalePolicies)
+		for i := range in.AutoScalePolicies {
+			out.AutoScalePolicies[i] = in.AutoScalePolicies[i].DeepCopy()
+		}
+	}
+	if in.ConnectionPoints != nil {
+		out.ConnectionPoints = make([]*ConnectionPoint, len(in.ConnectionPoints))
+		copy(out.ConnectionPoints, in.ConnectionPoints)
+		for i := range in.ConnectionPoints {
+			out.ConnectionPoints[i] = in.ConnectionPoints[i].DeepCopy()
+		}
+	}
+	out.Configurations = in.Configurations.DeepCopy()
+	out.LifecycleEvents = in.LifecycleEvents.DeepCopy()
+	if in.LifecycleEventHistory != nil {
+		out.LifecycleEventHistory = make([]*HistoryLifecycleEvent, len(in.LifecycleEventHistory))
+		copy(out.LifecycleEventHistory, in.LifecycleEventHistory)
+		for i := range in.LifecycleEventHistory {
+			out.LifecycleEventHistory[i] = in.LifecycleEventHistory[i].DeepCopy()
+		}
+	}
+	if in.MonitoringParameters != nil {
+		out.MonitoringParameters = make([]string, len(in.MonitoringParameters))
+		copy(out.MonitoringParameters, in.MonitoringParameters)
+	}
+	if in.VDUs != nil {
+		out.VDUs = make([]*VirtualDeploymentUnit, len(in.VDUs))
+		copy(out.VDUs, in.VDUs)
+		for i := range in.VDUs {
+			out.VDUs[i] = in.VDUs[i].DeepCopy()
+		}
+	}
+	if in.VirtualLinks != nil {
+		out.VirtualLinks = make([]*InternalVirtualLink, len(in.VirtualLinks))
+		copy(out.VirtualLinks, in.VirtualLinks)
+		for i := range in.VirtualLinks {
+			out.VirtualLinks[i] = in.VirtualLinks[i].DeepCopy()
+		}
+	}
+	if in.ConnectedExternalVirtualLinks != nil {
+		out.ConnectedExternalVirtualLinks = make([]*VirtualLinkRecord, len(in.ConnectedExternalVirtualLinks))
+		copy(out.ConnectedExternalVirtualLinks, in.ConnectedExternalVirtualLinks)
+		for i := range in.ConnectedExternalVirtualLinks {
+			out.ConnectedExternalVirtualLinks[i] = in.ConnectedExternalVirtualLinks[i].DeepCopy()
+		}
+	}
+	if in.VNFAddresses != nil {
+		out.VNFAddresses = make([]string, len(in.VNFAddresses))
+		copy(out.VNFAddresses, in.VNFAddresses)
+	}
+	if in.Notifications != nil {
+		out.Notifications = make([]string, len(in.Notifications))
+		copy(out.Notifications, in.Notifications)
+	}
+	if in.RuntimePolicyInfos != nil {
+		out.RuntimePolicyInfos = make([]string, len(in.RuntimePolicyInfos))
+		copy(out.RuntimePolicyInfos, in.RuntimePolicyInfos)
+	}
+	out.Requires = in.Requires.DeepCopy()
+	out.Provides = in.Provides.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VirtualNetworkFunctionRecord) DeepCopy() *VirtualNetworkFunctionRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNetworkFunctionRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VnfmManagerUnregisterMessage) DeepCopyInto(out *VnfmManagerUnregisterMessage) {
+	*out = *in
+	out.Endpoint = in.Endpoint.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VnfmManagerUnregisterMessage) DeepCopy() *VnfmManagerUnregisterMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(VnfmManagerUnregisterMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *VnfmRegisterMessage) DeepCopyInto(out *VnfmRegisterMessage) {
+	*out = *in
+	out.Endpoint = in.Endpoint.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *VnfmRegisterMessage) DeepCopy() *VnfmRegisterMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(VnfmRegisterMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *differ) DeepCopyInto(out *differ) {
+	*out = *in
+	out.changes = in.changes.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *differ) DeepCopy() *differ {
+	if in == nil {
+		return nil
+	}
+	out := new(differ)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, which must be non-nil.
+func (in *validator) DeepCopyInto(out *validator) {
+	*out = *in
+	out.findings = in.findings.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the receiver, nil if it is nil.
+func (in *validator) DeepCopy() *validator {
+	if in == nil {
+		return nil
+	}
+	out := new(validator)
+	in.DeepCopyInto(out)
+	return out
+}
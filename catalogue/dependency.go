@@ -0,0 +1,284 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ResolvedDependency is a VNFRecordDependency flattened by source VNF type, as the scripts configuring
+// the target VNF in MODIFY need it.
+type ResolvedDependency struct {
+	Target string
+	// The sources, sorted by type
+	Sources []*DependencySource
+	// The required parameters without a value for the VNFs or for one of their VNFC instances, as
+	// "type.key", or as "key" for the ones required by the target VNFR and provided by no source
+	Missing []string
+}
+
+// DependencySource holds the parameters the target depends on from the VNFs of one type.
+type DependencySource struct {
+	Type string
+	// The IDs of the VNFRs of this type, sorted
+	VNFRIDs []string
+	// The parameters of the VNFs, e.g. the ones they provide
+	Parameters map[string]string
+	// The parameters of each of their VNFC instances, e.g. their hostname and IPs, sorted by ID
+	VNFCs []*VNFCParameters
+}
+
+// VNFCParameters are the parameters of one VNFC instance of a source.
+type VNFCParameters struct {
+	VNFCID     string
+	Parameters map[string]string
+}
+
+// ResolveDependency flattens the dependency of the VNFR vnfr, which may be nil, and reports the parameters
+// it requires that have no value: the ones the dependency lists for a source without a value for the
+// source or one of its VNFC instances, and the keys of the requires of the VNFR no source has.
+func ResolveDependency(vnfr *VirtualNetworkFunctionRecord, dep *VNFRecordDependency) *ResolvedDependency {
+	r := &ResolvedDependency{Target: dep.Target, Sources: []*DependencySource{}, Missing: []string{}}
+
+	sources := make(map[string]*DependencySource)
+	source := func(typ string) *DependencySource {
+		s, ok := sources[typ]
+		if !ok {
+			s = &DependencySource{
+				Type:       typ,
+				VNFRIDs:    []string{},
+				Parameters: make(map[string]string),
+				VNFCs:      []*VNFCParameters{},
+			}
+			sources[typ] = s
+			r.Sources = append(r.Sources, s)
+		}
+		return s
+	}
+
+	for typ, params := range dep.Parameters {
+		s := source(typ)
+		if params != nil {
+			for key, value := range params.Parameters {
+				s.Parameters[key] = value
+			}
+		}
+	}
+	for typ, vnfcParams := range dep.VNFCParameters {
+		s := source(typ)
+		if vnfcParams == nil {
+			continue
+		}
+		for vnfcID, params := range vnfcParams.Parameters {
+			vnfc := &VNFCParameters{VNFCID: vnfcID, Parameters: make(map[string]string)}
+			if params != nil {
+				for key, value := range params.Parameters {
+					vnfc.Parameters[key] = value
+				}
+			}
+			s.VNFCs = append(s.VNFCs, vnfc)
+		}
+	}
+	for vnfrID, typ := range dep.IDType {
+		s := source(typ)
+		s.VNFRIDs = append(s.VNFRIDs, vnfrID)
+	}
+
+	sort.Slice(r.Sources, func(i, j int) bool { return r.Sources[i].Type < r.Sources[j].Type })
+	for _, s := range r.Sources {
+		sort.Strings(s.VNFRIDs)
+		sort.Slice(s.VNFCs, func(i, j int) bool { return s.VNFCs[i].VNFCID < s.VNFCs[j].VNFCID })
+
+		for _, key := range s.keys() {
+			if !s.Has(key) {
+				r.Missing = append(r.Missing, s.Type+"."+key)
+			}
+		}
+	}
+
+	if vnfr != nil && vnfr.Requires != nil {
+		for _, p := range vnfr.Requires.ConfigurationParameters {
+			if r.provided(p.ConfKey) {
+				continue
+			}
+			// already reported for the source the dependency lists it for
+			if r.listed(p.ConfKey) {
+				continue
+			}
+			r.Missing = append(r.Missing, p.ConfKey)
+		}
+	}
+
+	return r
+}
+
+// Source returns the source of type typ, nil if the target does not depend on it.
+func (r *ResolvedDependency) Source(typ string) *DependencySource {
+	for _, s := range r.Sources {
+		if s.Type == typ {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// Err returns an error listing the missing parameters, nil if none is.
+func (r *ResolvedDependency) Err() error {
+	if len(r.Missing) == 0 {
+		return nil
+	}
+
+	return errors.Errorf("the dependency of %s misses the parameters %s", r.Target, strings.Join(r.Missing, ", "))
+}
+
+func (r *ResolvedDependency) provided(key string) bool {
+	for _, s := range r.Sources {
+		if s.Has(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *ResolvedDependency) listed(key string) bool {
+	for _, s := range r.Sources {
+		for _, k := range s.keys() {
+			if k == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// keys returns the keys of the parameters listed for the VNFs of the source or for any of their VNFC
+// instances, sorted: the ones listed only for some instances are required from all of them.
+func (s *DependencySource) keys() []string {
+	keys := make(map[string]string)
+	for key := range s.Parameters {
+		keys[key] = ""
+	}
+	for _, vnfc := range s.VNFCs {
+		for key := range vnfc.Parameters {
+			keys[key] = ""
+		}
+	}
+
+	return sortedParameterKeys(keys)
+}
+
+// Has reports whether the parameter key has a value for the source: for the VNFs, or else for each
+// of their VNFC instances.
+func (s *DependencySource) Has(key string) bool {
+	if s.Parameters[key] != "" {
+		return true
+	}
+	if len(s.VNFCs) == 0 {
+		return false
+	}
+
+	for _, vnfc := range s.VNFCs {
+		if vnfc.Parameters[key] == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Flat returns the parameters of the VNFs of the source, and of the VNFC instance vnfc if not nil,
+// named "type_key", e.g. "server_hostname". The parameters of the VNFC instance take precedence.
+func (s *DependencySource) Flat(vnfc *VNFCParameters) map[string]string {
+	ret := make(map[string]string)
+	for key, value := range s.Parameters {
+		if value != "" {
+			ret[s.Type+"_"+key] = value
+		}
+	}
+	if vnfc != nil {
+		for key, value := range vnfc.Parameters {
+			if value != "" {
+				ret[s.Type+"_"+key] = value
+			}
+		}
+	}
+
+	return ret
+}
+
+// Env returns the parameters of the VNFs of all the sources, named as by Flat, as environment variables
+// ("NAME=value") for the scripts, sorted. The scripts run once per VNFC instance of a source add its
+// parameters with VNFCEnv.
+func (r *ResolvedDependency) Env() []string {
+	return r.VNFCEnv(nil, nil)
+}
+
+// VNFCEnv returns the environment variables of Env with the ones of the VNFC instance vnfc of the source s.
+func (r *ResolvedDependency) VNFCEnv(s *DependencySource, vnfc *VNFCParameters) []string {
+	params := make(map[string]string)
+	for _, other := range r.Sources {
+		for key, value := range other.Flat(nil) {
+			params[key] = value
+		}
+	}
+	if s != nil {
+		for key, value := range s.Flat(vnfc) {
+			params[key] = value
+		}
+	}
+
+	return Env(params)
+}
+
+// Env renders params as environment variables ("NAME=value"), sorted by name. The characters of the
+// names that are not letters, digits or underscores are replaced by underscores.
+func Env(params map[string]string) []string {
+	env := make([]string, 0, len(params))
+	for _, key := range sortedParameterKeys(params) {
+		env = append(env, fmt.Sprintf("%s=%s", envName(key), params[key]))
+	}
+
+	return env
+}
+
+func envName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+func sortedParameterKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,144 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestResolveDependency(t *testing.T) {
+	tests := []struct {
+		name     string
+		dep      string
+		requires []string
+		missing  []string
+		env      []string
+		vnfcEnv  map[string][]string
+	}{
+		{
+			name: "resolved",
+			dep: `{
+				"target": "client",
+				"parameters": {"server": {"parameters": {"port": "8080", "hostname": ""}}},
+				"vnfcParameters": {"server": {"parameters": {
+					"vnfc-1": {"parameters": {"hostname": "srv-1", "private": "10.0.0.1"}},
+					"vnfc-2": {"parameters": {"hostname": "srv-2", "private": "10.0.0.2"}}
+				}}},
+				"idType": {"vnfr-1": "server"}
+			}`,
+			requires: []string{"port", "hostname"},
+			missing:  []string{},
+			env:      []string{"server_port=8080"},
+			vnfcEnv: map[string][]string{
+				"vnfc-1": {"server_hostname=srv-1", "server_port=8080", "server_private=10.0.0.1"},
+				"vnfc-2": {"server_hostname=srv-2", "server_port=8080", "server_private=10.0.0.2"},
+			},
+		},
+		{
+			name: "missing for the VNFs",
+			dep: `{
+				"target": "client",
+				"parameters": {"server": {"parameters": {"port": ""}}}
+			}`,
+			requires: []string{"port", "user"},
+			missing:  []string{"server.port", "user"},
+			env:      []string{},
+		},
+		{
+			name: "missing for a VNFC instance",
+			dep: `{
+				"target": "client",
+				"parameters": {"server": {"parameters": {}}},
+				"vnfcParameters": {"server": {"parameters": {
+					"vnfc-1": {"parameters": {"hostname": "srv-1", "private": "10.0.0.1"}},
+					"vnfc-2": {"parameters": {"hostname": ""}}
+				}}}
+			}`,
+			requires: []string{"hostname", "private"},
+			missing:  []string{"server.hostname", "server.private"},
+			env:      []string{},
+			vnfcEnv: map[string][]string{
+				"vnfc-1": {"server_hostname=srv-1", "server_private=10.0.0.1"},
+				"vnfc-2": {},
+			},
+		},
+		{
+			name: "VNFC value overriding an empty one",
+			dep: `{
+				"target": "client",
+				"parameters": {"server": {"parameters": {"private": "10.0.0.254"}}},
+				"vnfcParameters": {"server": {"parameters": {
+					"vnfc-1": {"parameters": {"private": ""}}
+				}}}
+			}`,
+			missing: []string{},
+			env:     []string{"server_private=10.0.0.254"},
+			vnfcEnv: map[string][]string{
+				"vnfc-1": {"server_private=10.0.0.254"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dep := &VNFRecordDependency{}
+			if err := json.Unmarshal([]byte(tt.dep), dep); err != nil {
+				t.Fatal(err)
+			}
+			vnfr := &VirtualNetworkFunctionRecord{Name: "client", Requires: &Configuration{}}
+			for _, key := range tt.requires {
+				vnfr.Requires.Append(&ConfigurationParameter{ConfKey: key})
+			}
+
+			r := ResolveDependency(vnfr, dep)
+			if !reflect.DeepEqual(r.Missing, tt.missing) {
+				t.Errorf("Missing = %q, want %q", r.Missing, tt.missing)
+			}
+			if (r.Err() == nil) != (len(tt.missing) == 0) {
+				t.Errorf("Err() = %v with the missing parameters %q", r.Err(), tt.missing)
+			}
+			if got := r.Env(); !reflect.DeepEqual(got, tt.env) {
+				t.Errorf("Env() = %q, want %q", got, tt.env)
+			}
+			for vnfcID, want := range tt.vnfcEnv {
+				s := r.Source("server")
+				var vnfc *VNFCParameters
+				for _, p := range s.VNFCs {
+					if p.VNFCID == vnfcID {
+						vnfc = p
+					}
+				}
+				if vnfc == nil {
+					t.Fatalf("no VNFC instance %s", vnfcID)
+				}
+				if got := r.VNFCEnv(s, vnfc); !reflect.DeepEqual(got, want) {
+					t.Errorf("VNFCEnv(%s) = %q, want %q", vnfcID, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEnv(t *testing.T) {
+	got := Env(map[string]string{"server-1_private.ip": "10.0.0.1", "server_port": "8080"})
+	want := []string{"server_1_private_ip=10.0.0.1", "server_port=8080"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Env() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,368 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type ChangeKind string
+
+const (
+	// The status of the VNFR changed
+	ChangeStatus = ChangeKind("STATUS")
+
+	// A field of the VNFR holding a single value changed, e.g. the task
+	ChangeField = ChangeKind("FIELD")
+
+	// A parameter of the configurations, provides or requires was added, removed or changed
+	ChangeConfigurationParameter = ChangeKind("CONFIGURATION_PARAMETER")
+
+	// The addresses of the VNF changed
+	ChangeVNFAddresses = ChangeKind("VNF_ADDRESSES")
+
+	ChangeVDUAdded   = ChangeKind("VDU_ADDED")
+	ChangeVDURemoved = ChangeKind("VDU_REMOVED")
+
+	ChangeVNFCAdded   = ChangeKind("VNFC_ADDED")
+	ChangeVNFCRemoved = ChangeKind("VNFC_REMOVED")
+
+	// The state of a VNFC instance changed
+	ChangeVNFCState = ChangeKind("VNFC_STATE")
+
+	// The IPs or the floating IPs of a VNFC instance changed
+	ChangeIPs         = ChangeKind("IPS")
+	ChangeFloatingIPs = ChangeKind("FLOATING_IPS")
+)
+
+// Change is a difference between two VNFRs found by Diff. Path locates the changed element with the JSON names
+// of the fields and the ID (or else the name) of the elements of the lists, e.g. "vdu[vdu1].vnfc_instance[abc]".
+// Old and New are copies of the values, nil if the element was added or removed.
+type Change struct {
+	Kind ChangeKind  `json:"kind"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+func (c *Change) String() string {
+	return fmt.Sprintf("%s %s: %v -> %v", c.Kind, c.Path, describe(c.Old), describe(c.New))
+}
+
+// describe formats the values of a change, the added or removed elements by their key.
+func describe(v interface{}) interface{} {
+	switch t := v.(type) {
+	case *VirtualDeploymentUnit:
+		return vduKey(t)
+	case *VNFCInstance:
+		return vnfcKey(t)
+	case []*IP:
+		return ipStrings(t)
+	default:
+		return v
+	}
+}
+
+// Changes is the result of Diff, empty if the VNFRs are the same.
+type Changes []*Change
+
+// Filter returns only the changes of the given kinds.
+func (cs Changes) Filter(kinds ...ChangeKind) Changes {
+	ret := Changes{}
+
+	for _, c := range cs {
+		for _, kind := range kinds {
+			if c.Kind == kind {
+				ret = append(ret, c)
+				break
+			}
+		}
+	}
+
+	return ret
+}
+
+// Diff returns the changes turning the VNFR a into b, e.g. to audit what a handler did to a VNFR:
+//
+//	before := vnfr.DeepCopy()
+//	... the handler modifies vnfr
+//	changes := catalogue.Diff(before, vnfr)
+//
+// The lifecycle event history, the descriptors and the lists not listed by ChangeKind are not compared.
+// The VDUs and VNFC instances are matched by ID, or else by index; the nil ones are ignored.
+func Diff(a, b *VirtualNetworkFunctionRecord) Changes {
+	d := &differ{changes: Changes{}}
+
+	if a.Status != b.Status {
+		d.add(ChangeStatus, "status", a.Status, b.Status)
+	}
+	d.diffFields(reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem())
+
+	d.diffConfiguration("configurations", a.Configurations, b.Configurations)
+	d.diffConfiguration("provides", a.Provides, b.Provides)
+	d.diffConfiguration("requires", a.Requires, b.Requires)
+
+	if !equalStrings(a.VNFAddresses, b.VNFAddresses) {
+		d.add(ChangeVNFAddresses, "vnf_address", copyStrings(a.VNFAddresses), copyStrings(b.VNFAddresses))
+	}
+
+	aKeys, bKeys := vduKeys(a.VDUs), vduKeys(b.VDUs)
+	bVDUs := make(map[string]*VirtualDeploymentUnit)
+	for i, vdu := range b.VDUs {
+		if vdu != nil {
+			bVDUs[bKeys[i]] = vdu
+		}
+	}
+	aVDUs := make(map[string]bool)
+	for i, vdu := range a.VDUs {
+		if vdu == nil {
+			continue
+		}
+		key := aKeys[i]
+		aVDUs[key] = true
+
+		if other, ok := bVDUs[key]; ok {
+			d.diffVDU(fmt.Sprintf("vdu[%s]", key), vdu, other)
+		} else {
+			d.add(ChangeVDURemoved, fmt.Sprintf("vdu[%s]", key), vdu.DeepCopy(), nil)
+		}
+	}
+	for i, vdu := range b.VDUs {
+		if vdu != nil && !aVDUs[bKeys[i]] {
+			d.add(ChangeVDUAdded, fmt.Sprintf("vdu[%s]", bKeys[i]), nil, vdu.DeepCopy())
+		}
+	}
+
+	return d.changes
+}
+
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) add(kind ChangeKind, path string, from, to interface{}) {
+	d.changes = append(d.changes, &Change{Kind: kind, Path: path, Old: from, New: to})
+}
+
+// diffFields compares the fields of two VNFRs holding a single value, but the status.
+func (d *differ) diffFields(a, b reflect.Value) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		switch field.Type.Kind() {
+		case reflect.String, reflect.Int, reflect.Bool:
+		default:
+			continue
+		}
+		if field.Name == "Status" {
+			continue
+		}
+
+		if av, bv := a.Field(i).Interface(), b.Field(i).Interface(); av != bv {
+			d.add(ChangeField, jsonName(field), av, bv)
+		}
+	}
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
+
+func (d *differ) diffConfiguration(path string, a, b *Configuration) {
+	params := func(c *Configuration) map[string]string {
+		ret := make(map[string]string)
+		if c != nil {
+			for _, p := range c.ConfigurationParameters {
+				ret[p.ConfKey] = p.Value
+			}
+		}
+		return ret
+	}
+	aParams, bParams := params(a), params(b)
+
+	keys := make([]string, 0, len(aParams)+len(bParams))
+	for key := range aParams {
+		keys = append(keys, key)
+	}
+	for key := range bParams {
+		if _, ok := aParams[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		av, inA := aParams[key]
+		bv, inB := bParams[key]
+		switch {
+		case !inA:
+			d.add(ChangeConfigurationParameter, path+"."+key, nil, bv)
+		case !inB:
+			d.add(ChangeConfigurationParameter, path+"."+key, av, nil)
+		case av != bv:
+			d.add(ChangeConfigurationParameter, path+"."+key, av, bv)
+		}
+	}
+}
+
+func (d *differ) diffVDU(path string, a, b *VirtualDeploymentUnit) {
+	aKeys, bKeys := vnfcKeys(a.VNFCInstances), vnfcKeys(b.VNFCInstances)
+	bInstances := make(map[string]*VNFCInstance)
+	for i, vnfc := range b.VNFCInstances {
+		if vnfc != nil {
+			bInstances[bKeys[i]] = vnfc
+		}
+	}
+	aInstances := make(map[string]bool)
+	for i, vnfc := range a.VNFCInstances {
+		if vnfc == nil {
+			continue
+		}
+		key := aKeys[i]
+		aInstances[key] = true
+		vnfcPath := fmt.Sprintf("%s.vnfc_instance[%s]", path, key)
+
+		other, ok := bInstances[key]
+		if !ok {
+			d.add(ChangeVNFCRemoved, vnfcPath, vnfc.DeepCopy(), nil)
+			continue
+		}
+
+		if vnfc.State != other.State {
+			d.add(ChangeVNFCState, vnfcPath+".state", vnfc.State, other.State)
+		}
+		if !equalStrings(ipStrings(vnfc.IPs), ipStrings(other.IPs)) {
+			d.add(ChangeIPs, vnfcPath+".ips", copyIPs(vnfc.IPs), copyIPs(other.IPs))
+		}
+		if !equalStrings(ipStrings(vnfc.FloatingIPs), ipStrings(other.FloatingIPs)) {
+			d.add(ChangeFloatingIPs, vnfcPath+".floatingIps", copyIPs(vnfc.FloatingIPs), copyIPs(other.FloatingIPs))
+		}
+	}
+	for i, vnfc := range b.VNFCInstances {
+		if vnfc != nil && !aInstances[bKeys[i]] {
+			d.add(ChangeVNFCAdded, fmt.Sprintf("%s.vnfc_instance[%s]", path, bKeys[i]), nil, vnfc.DeepCopy())
+		}
+	}
+}
+
+func vduKey(vdu *VirtualDeploymentUnit) string {
+	if vdu.ID != "" {
+		return vdu.ID
+	}
+
+	return vdu.Name
+}
+
+// vnfcKey identifies a VNFC instance by its ID, or else the ID on the VIM or the hostname.
+func vnfcKey(vnfc *VNFCInstance) string {
+	switch {
+	case vnfc.ID != "":
+		return vnfc.ID
+	case vnfc.VCID != "":
+		return vnfc.VCID
+	default:
+		return vnfc.Hostname
+	}
+}
+
+// vduKeys returns the key of each VDU by index, see uniqueKeys. The nil VDUs are given their index.
+func vduKeys(vdus []*VirtualDeploymentUnit) []string {
+	keys := make([]string, len(vdus))
+	for i, vdu := range vdus {
+		if vdu != nil {
+			keys[i] = vduKey(vdu)
+		}
+	}
+
+	return uniqueKeys(keys)
+}
+
+// vnfcKeys returns the key of each VNFC instance by index, see uniqueKeys. The nil instances are given their index.
+func vnfcKeys(vnfcs []*VNFCInstance) []string {
+	keys := make([]string, len(vnfcs))
+	for i, vnfc := range vnfcs {
+		if vnfc != nil {
+			keys[i] = vnfcKey(vnfc)
+		}
+	}
+
+	return uniqueKeys(keys)
+}
+
+// uniqueKeys replaces the empty keys, and the ones already taken by a previous element, by the index of their
+// element ("#1"), so that no element overwrites another one in a map.
+func uniqueKeys(keys []string) []string {
+	taken := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		if key == "" || taken[key] {
+			keys[i] = fmt.Sprintf("#%d", i)
+		}
+		taken[keys[i]] = true
+	}
+
+	return keys
+}
+
+// ipStrings returns the IPs as "netName:ip", sorted.
+func ipStrings(ips []*IP) []string {
+	ret := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if ip != nil {
+			ret = append(ret, ip.NetName+":"+ip.IP)
+		}
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+func copyIPs(ips []*IP) []*IP {
+	ret := make([]*IP, len(ips))
+	for i, ip := range ips {
+		ret[i] = ip.DeepCopy()
+	}
+
+	return ret
+}
+
+func copyStrings(s []string) []string {
+	ret := make([]string, len(s))
+	copy(ret, s)
+
+	return ret
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,388 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func loadVNFR(t *testing.T) *VirtualNetworkFunctionRecord {
+	nsr := new(NetworkServiceRecord)
+	if err := json.Unmarshal(readFixture(t, "nsr.json"), nsr); err != nil {
+		t.Fatal(err)
+	}
+
+	return nsr.VNFR[0]
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		// sets up the VNFR before the change, if not nil
+		before func(vnfr *VirtualNetworkFunctionRecord)
+		change func(vnfr *VirtualNetworkFunctionRecord)
+		want   []string
+	}{
+		{
+			name:   "same",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {},
+			want:   []string{},
+		},
+		{
+			name: "status and fields",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.Status = StatusInactive
+				vnfr.Task = "stopping"
+				vnfr.HbVersion = 6
+				vnfr.CyclicDependency = true
+			},
+			want: []string{
+				"STATUS status: ACTIVE -> INACTIVE",
+				"FIELD hbVersion: 5 -> 6",
+				"FIELD task:  -> stopping",
+				"FIELD cyclic_dependency: false -> true",
+			},
+		},
+		{
+			name: "configuration parameters",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.Configurations.ConfigurationParameters = append(vnfr.Configurations.ConfigurationParameters,
+					&ConfigurationParameter{ConfKey: "port", Value: "5001"})
+				vnfr.Provides = &Configuration{ConfigurationParameters: []*ConfigurationParameter{{ConfKey: "private"}}}
+			},
+			want: []string{
+				"CONFIGURATION_PARAMETER configurations.port: <nil> -> 5001",
+				"CONFIGURATION_PARAMETER provides.private: <nil> -> ",
+			},
+		},
+		{
+			name: "configuration parameter removed and changed",
+			before: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.Configurations.ConfigurationParameters = []*ConfigurationParameter{
+					{ConfKey: "port", Value: "5001"},
+					{ConfKey: "user", Value: "ubuntu"},
+				}
+			},
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.Configurations.ConfigurationParameters = []*ConfigurationParameter{{ConfKey: "port", Value: "5002"}}
+			},
+			want: []string{
+				"CONFIGURATION_PARAMETER configurations.port: 5001 -> 5002",
+				"CONFIGURATION_PARAMETER configurations.user: ubuntu -> <nil>",
+			},
+		},
+		{
+			name: "addresses",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VNFAddresses = append(vnfr.VNFAddresses, "192.168.1.6")
+			},
+			want: []string{"VNF_ADDRESSES vnf_address: [192.168.1.5] -> [192.168.1.5 192.168.1.6]"},
+		},
+		{
+			name: "VNFC instance",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfc := vnfr.VDUs[0].VNFCInstances[0]
+				vnfc.State = "FAILED"
+				vnfc.IPs = append(vnfc.IPs, &IP{NetName: "public", IP: "10.0.0.5"})
+				vnfc.FloatingIPs = nil
+				vnfr.VDUs[0].VNFCInstances = append(vnfr.VDUs[0].VNFCInstances, &VNFCInstance{Hostname: "iperf-server-vdu-2"})
+			},
+			want: []string{
+				"VNFC_STATE vdu[d1000000-0000-4000-8000-000000000001].vnfc_instance[1c000000-0000-4000-8000-000000000001].state: ACTIVE -> FAILED",
+				"IPS vdu[d1000000-0000-4000-8000-000000000001].vnfc_instance[1c000000-0000-4000-8000-000000000001].ips: " +
+					"[private:192.168.1.5] -> [private:192.168.1.5 public:10.0.0.5]",
+				"FLOATING_IPS vdu[d1000000-0000-4000-8000-000000000001].vnfc_instance[1c000000-0000-4000-8000-000000000001].floatingIps: " +
+					"[private:172.24.4.12] -> []",
+				"VNFC_ADDED vdu[d1000000-0000-4000-8000-000000000001].vnfc_instance[iperf-server-vdu-2]: <nil> -> iperf-server-vdu-2",
+			},
+		},
+		{
+			name: "IPs reordered",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfc := vnfr.VDUs[0].VNFCInstances[0]
+				vnfc.IPs = append([]*IP{{NetName: "public", IP: "10.0.0.5"}}, vnfc.IPs...)
+			},
+			want: []string{
+				"IPS vdu[d1000000-0000-4000-8000-000000000001].vnfc_instance[1c000000-0000-4000-8000-000000000001].ips: " +
+					"[private:192.168.1.5] -> [private:192.168.1.5 public:10.0.0.5]",
+			},
+		},
+		{
+			name: "VNFC instance removed",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VDUs[0].VNFCInstances = []*VNFCInstance{}
+			},
+			want: []string{
+				"VNFC_REMOVED vdu[d1000000-0000-4000-8000-000000000001].vnfc_instance[1c000000-0000-4000-8000-000000000001]: " +
+					"1c000000-0000-4000-8000-000000000001 -> <nil>",
+			},
+		},
+		{
+			name: "VDUs",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VDUs = []*VirtualDeploymentUnit{{Name: "vdu2"}}
+			},
+			want: []string{
+				"VDU_REMOVED vdu[d1000000-0000-4000-8000-000000000001]: d1000000-0000-4000-8000-000000000001 -> <nil>",
+				"VDU_ADDED vdu[vdu2]: <nil> -> vdu2",
+			},
+		},
+		{
+			name: "nil VDU and VNFC instance",
+			before: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VDUs = append(vnfr.VDUs, nil)
+				vnfr.VDUs[0].VNFCInstances = append(vnfr.VDUs[0].VNFCInstances, nil)
+			},
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VDUs[0].VNFCInstances[0].IPs = append(vnfr.VDUs[0].VNFCInstances[0].IPs, nil)
+			},
+			want: []string{},
+		},
+		{
+			name: "VNFC instances without key",
+			before: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VDUs[0].VNFCInstances = []*VNFCInstance{{State: "ACTIVE"}, {State: "STANDBY"}}
+			},
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VDUs[0].VNFCInstances[1].State = "ACTIVE"
+				vnfr.VDUs[0].VNFCInstances = append(vnfr.VDUs[0].VNFCInstances, &VNFCInstance{State: "STANDBY"})
+			},
+			want: []string{
+				"VNFC_STATE vdu[d1000000-0000-4000-8000-000000000001].vnfc_instance[#1].state: STANDBY -> ACTIVE",
+				"VNFC_ADDED vdu[d1000000-0000-4000-8000-000000000001].vnfc_instance[#2]: <nil> -> ",
+			},
+		},
+		{
+			name: "VDUs with the same name",
+			before: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VDUs = []*VirtualDeploymentUnit{{Name: "vdu"}, {Name: "vdu"}}
+			},
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.VDUs[1].VNFCInstances = []*VNFCInstance{{ID: "vnfc"}}
+			},
+			want: []string{"VNFC_ADDED vdu[#1].vnfc_instance[vnfc]: <nil> -> vnfc"},
+		},
+		{
+			name: "not compared",
+			change: func(vnfr *VirtualNetworkFunctionRecord) {
+				vnfr.LifecycleEventHistory = append(vnfr.LifecycleEventHistory, NewHistoryLifecycleEvent(EventStart, ""))
+				vnfr.MonitoringParameters = []string{"cpu"}
+				vnfr.VDUs[0].Hostname = "other"
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := loadVNFR(t)
+			if tt.before != nil {
+				tt.before(before)
+			}
+			after := before.DeepCopy()
+			tt.change(after)
+
+			got := []string{}
+			for _, c := range Diff(before, after) {
+				got = append(got, c.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffCopies(t *testing.T) {
+	before := loadVNFR(t)
+	after := before.DeepCopy()
+	after.VNFAddresses = []string{"192.168.1.6"}
+	after.VDUs[0].VNFCInstances[0].IPs[0].IP = "192.168.1.6"
+	after.VDUs = append(after.VDUs, &VirtualDeploymentUnit{Name: "vdu2"})
+
+	changes := Diff(before, after).Filter(ChangeVNFAddresses, ChangeIPs, ChangeVDUAdded)
+	if len(changes) != 3 {
+		t.Fatalf("got the changes %v", changes)
+	}
+	after.VNFAddresses[0] = "changed"
+	after.VDUs[0].VNFCInstances[0].IPs[0].IP = "changed"
+	after.VDUs[1].Name = "changed"
+
+	for _, c := range changes {
+		switch v := c.New.(type) {
+		case []string:
+			if v[0] == "changed" {
+				t.Errorf("%s shares the addresses", c.Kind)
+			}
+		case []*IP:
+			if v[0].IP == "changed" {
+				t.Errorf("%s shares the IPs", c.Kind)
+			}
+		case *VirtualDeploymentUnit:
+			if v.Name == "changed" {
+				t.Errorf("%s shares the VDU", c.Kind)
+			}
+		}
+	}
+}
+
+func TestChangesFilter(t *testing.T) {
+	changes := Changes{
+		{Kind: ChangeStatus, Path: "status"},
+		{Kind: ChangeVNFCAdded, Path: "vdu[a].vnfc_instance[b]"},
+		{Kind: ChangeVNFCRemoved, Path: "vdu[a].vnfc_instance[c]"},
+	}
+
+	tests := []struct {
+		kinds []ChangeKind
+		want  Changes
+	}{
+		{nil, Changes{}},
+		{[]ChangeKind{ChangeIPs}, Changes{}},
+		{[]ChangeKind{ChangeStatus}, changes[:1]},
+		{[]ChangeKind{ChangeVNFCAdded, ChangeVNFCRemoved}, changes[1:]},
+	}
+
+	for _, tt := range tests {
+		if got := changes.Filter(tt.kinds...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Filter(%v) = %v, want %v", tt.kinds, got, tt.want)
+		}
+	}
+}
+
+// checkNotShared reports the pointers, maps and slices reachable from both a and b, deep copies of each other.
+func checkNotShared(t *testing.T, path string, a, b reflect.Value) {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			t.Errorf("%s is shared", path)
+			return
+		}
+		checkNotShared(t, path, a.Elem(), b.Elem())
+
+	case reflect.Interface:
+		if !a.IsNil() && !b.IsNil() {
+			checkNotShared(t, path, a.Elem(), b.Elem())
+		}
+
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			checkNotShared(t, path+"."+a.Type().Field(i).Name, a.Field(i), b.Field(i))
+		}
+
+	case reflect.Slice:
+		if a.Len() == 0 || b.Len() == 0 {
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			t.Errorf("%s is shared", path)
+			return
+		}
+		for i := 0; i < a.Len(); i++ {
+			checkNotShared(t, path+"[]", a.Index(i), b.Index(i))
+		}
+
+	case reflect.Map:
+		if a.IsNil() || b.IsNil() {
+			return
+		}
+		if a.Pointer() == b.Pointer() {
+			t.Errorf("%s is shared", path)
+			return
+		}
+		for _, key := range a.MapKeys() {
+			checkNotShared(t, path+"["+key.String()+"]", a.MapIndex(key), b.MapIndex(key))
+		}
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	nsd := loadNSD(t)
+	nsr := new(NetworkServiceRecord)
+	if err := json.Unmarshal(readFixture(t, "nsr.json"), nsr); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		in   interface{}
+		copy func() interface{}
+	}{
+		{"NSD", nsd, func() interface{} { return nsd.DeepCopy() }},
+		{"NSR", nsr, func() interface{} { return nsr.DeepCopy() }},
+		{"VNFR", nsr.VNFR[0], func() interface{} { return nsr.VNFR[0].DeepCopy() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := tt.copy()
+			if !reflect.DeepEqual(out, tt.in) {
+				t.Fatalf("got a copy different from the original")
+			}
+			checkNotShared(t, tt.name, reflect.ValueOf(out), reflect.ValueOf(tt.in))
+		})
+	}
+}
+
+func TestDeepCopyChange(t *testing.T) {
+	vdu := loadVNFR(t).VDUs[0]
+
+	tests := []struct {
+		name string
+		new  interface{}
+		// whether the value is copied, or else shared
+		copied bool
+	}{
+		{"nil", nil, false},
+		{"catalogue type", vdu, true},
+		{"JSON values", map[string]interface{}{"a": []interface{}{"b", map[string]interface{}{"c": 1.0}}}, true},
+		{"string", "ACTIVE", false},
+		{"strings", []string{"192.168.1.5"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Change{Kind: ChangeField, Path: "path", New: tt.new}
+			out := c.DeepCopy()
+			if !reflect.DeepEqual(out, c) {
+				t.Fatalf("got %v, want %v", out, c)
+			}
+			if tt.copied {
+				checkNotShared(t, tt.name, reflect.ValueOf(out.New), reflect.ValueOf(c.New))
+			}
+		})
+	}
+}
+
+func TestDeepCopyNil(t *testing.T) {
+	var vnfr *VirtualNetworkFunctionRecord
+	if vnfr.DeepCopy() != nil {
+		t.Error("got a copy of a nil VNFR")
+	}
+	if got := deepCopyInterface(nil); got != nil {
+		t.Errorf("deepCopyInterface(nil) = %v", got)
+	}
+	var vdu *VirtualDeploymentUnit
+	if got := deepCopyInterface(vdu); got != interface{}(vdu) {
+		t.Errorf("deepCopyInterface(nil VDU) = %v", got)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package graph
+
+import (
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/pkg/errors"
+)
+
+// FromNSD builds the graph of the VNFs of the NSD, named as their VNFDs, from its VNF dependencies and
+// from the parameters each VNFD requires from the VNFs of another type.
+func FromNSD(nsd *catalogue.NetworkServiceDescriptor) (*Graph, error) {
+	g, err := FromVNFDs(nsd.Name, nsd.VNFDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, dep := range nsd.VNFDependencies {
+		if dep.Source == nil || dep.Target == nil {
+			return nil, errors.Errorf("vnf_dependency[%d] has no source or target", i)
+		}
+		// the NSDs usually reference the VNFDs by name only
+		source, target := nsd.FindVNFD(dep.Source.Name), nsd.FindVNFD(dep.Target.Name)
+		if source == nil || target == nil {
+			return nil, errors.Errorf("vnf_dependency[%d] refers to a VNFD not in the NSD", i)
+		}
+		if _, err := g.AddEdge(source.Name, target.Name, dep.Parameters...); err != nil {
+			return nil, errors.Wrapf(err, "vnf_dependency[%d]", i)
+		}
+	}
+
+	return g, nil
+}
+
+// FromVNFDs builds the graph of the VNFDs from the parameters each requires from the VNFDs of another type.
+func FromVNFDs(name string, vnfds ...*catalogue.VirtualNetworkFunctionDescriptor) (*Graph, error) {
+	g := New(name)
+	for _, vnfd := range vnfds {
+		if vnfd.Name == "" {
+			return nil, errors.New("VNFD without name")
+		}
+		if g.Node(vnfd.Name) != nil {
+			return nil, errors.Errorf("VNFD %s is there twice", vnfd.Name)
+		}
+		g.AddNode(&Node{Name: vnfd.Name, Type: vnfd.Type, VNFD: vnfd})
+	}
+
+	for _, target := range vnfds {
+		for typ, required := range target.Requires {
+			var parameters []string
+			if required != nil {
+				parameters = required.Parameters
+			}
+			for _, source := range vnfds {
+				if source.Type != typ {
+					continue
+				}
+				if _, err := g.AddEdge(source.Name, target.Name, parameters...); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// FromNSR builds the graph of the VNFs of the NSR, named as their VNFRs, from its VNF record dependencies.
+// The sources of a dependency are the VNFRs listed by its IDType, or else all the VNFRs of the source types.
+func FromNSR(nsr *catalogue.NetworkServiceRecord) (*Graph, error) {
+	g := New(nsr.Name)
+	for _, vnfr := range nsr.VNFR {
+		if vnfr.Name == "" {
+			return nil, errors.New("VNFR without name")
+		}
+		if g.Node(vnfr.Name) != nil {
+			return nil, errors.Errorf("VNFR %s is there twice", vnfr.Name)
+		}
+		g.AddNode(&Node{Name: vnfr.Name, Type: vnfr.Type, VNFR: vnfr})
+	}
+
+	for i, dep := range nsr.VNFDependency {
+		if g.Node(dep.Target) == nil {
+			return nil, errors.Errorf("vnf_dependency[%d] targets the unknown VNFR %s", i, dep.Target)
+		}
+
+		resolved := catalogue.ResolveDependency(nil, dep)
+		for _, source := range resolved.Sources {
+			parameters := []string{}
+			for key := range source.Parameters {
+				parameters = append(parameters, key)
+			}
+			for _, vnfc := range source.VNFCs {
+				for key := range vnfc.Parameters {
+					parameters = append(parameters, key)
+				}
+			}
+
+			for _, vnfr := range nsr.VNFR {
+				if !isSource(vnfr, source) {
+					continue
+				}
+				if _, err := g.AddEdge(vnfr.Name, dep.Target, parameters...); err != nil {
+					return nil, errors.Wrapf(err, "vnf_dependency[%d]", i)
+				}
+			}
+		}
+	}
+
+	return g, nil
+}
+
+func isSource(vnfr *catalogue.VirtualNetworkFunctionRecord, source *catalogue.DependencySource) bool {
+	if len(source.VNFRIDs) == 0 {
+		return vnfr.Type == source.Type
+	}
+
+	for _, id := range source.VNFRIDs {
+		if vnfr.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FromVNFD builds the graph of the VDUs of the VNFD, named as the VDUs, from its VDU dependencies.
+func FromVNFD(vnfd *catalogue.VirtualNetworkFunctionDescriptor) (*Graph, error) {
+	g := New(vnfd.Name)
+	for _, vdu := range vnfd.VDUs {
+		name := vduName(vdu)
+		if name == "" {
+			return nil, errors.New("VDU without name or ID")
+		}
+		if g.Node(name) != nil {
+			return nil, errors.Errorf("VDU %s is there twice", name)
+		}
+		g.AddNode(&Node{Name: name, VDU: vdu})
+	}
+
+	find := func(ref *catalogue.VirtualDeploymentUnit) *catalogue.VirtualDeploymentUnit {
+		if ref == nil {
+			return nil
+		}
+		for _, vdu := range vnfd.VDUs {
+			if (ref.ID != "" && vdu.ID == ref.ID) || (ref.Name != "" && vdu.Name == ref.Name) {
+				return vdu
+			}
+		}
+		return nil
+	}
+	for i, dep := range vnfd.VDUDependencies {
+		source, target := find(dep.Source), find(dep.Target)
+		if source == nil || target == nil {
+			return nil, errors.Errorf("vdu_dependency[%d] refers to a VDU not in the VNFD", i)
+		}
+		if _, err := g.AddEdge(vduName(source), vduName(target)); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+func vduName(vdu *catalogue.VirtualDeploymentUnit) string {
+	if vdu.Name != "" {
+		return vdu.Name
+	}
+
+	return vdu.ID
+}
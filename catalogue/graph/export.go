@@ -0,0 +1,87 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MarshalJSON encodes the graph with its cycles and, if it has none, its order:
+//
+//	{"name": ..., "nodes": [...], "edges": [...], "cycles": [...], "order": [...]}
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	type graph Graph
+	order, _ := g.Order()
+
+	return json.Marshal(&struct {
+		*graph
+		Cycles [][]string `json:"cycles"`
+		Order  [][]string `json:"order,omitempty"`
+	}{
+		graph:  (*graph)(g),
+		Cycles: g.Cycles(),
+		Order:  order,
+	})
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz, e.g. to render it with "dot -Tsvg".
+// The edges go from the sources to their targets, labelled with the parameters, and the nodes
+// part of a cycle are red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	// marks the nodes part of a cycle
+	g.Cycles()
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "digraph %s {\n", strconv.Quote(g.Name))
+	fmt.Fprintf(bw, "\trankdir=LR;\n")
+	fmt.Fprintf(bw, "\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		label := n.Name
+		if n.Type != "" && n.Type != n.Name {
+			label = fmt.Sprintf("%s\n(%s)", n.Name, n.Type)
+		}
+		attrs := "label=" + strconv.Quote(label)
+		if n.Cyclic {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(bw, "\t%s [%s];\n", strconv.Quote(n.Name), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "\t%s -> %s", strconv.Quote(e.Source), strconv.Quote(e.Target))
+		if len(e.Parameters) > 0 {
+			fmt.Fprintf(bw, " [label=%s]", strconv.Quote(strings.Join(e.Parameters, ", ")))
+		}
+		fmt.Fprintf(bw, ";\n")
+	}
+	fmt.Fprintf(bw, "}\n")
+
+	return bw.Flush()
+}
+
+// DOT returns the graph in the DOT language, see WriteDOT.
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	// a strings.Builder never fails
+	_ = g.WriteDOT(&sb)
+
+	return sb.String()
+}
@@ -0,0 +1,328 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package graph builds the dependency graphs of the VNFs of a network service, or of the VDUs of a VNF,
+// to find their cycles, the order they are instantiated and started in, and to draw them.
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/pkg/errors"
+)
+
+// Node is a VNF, or a VDU, of a graph. Only the element it was built from is set.
+type Node struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+	// Whether the node is part of a cycle, updated by Cycles, Order and the exports
+	Cyclic bool `json:"cyclic"`
+
+	VNFD *catalogue.VirtualNetworkFunctionDescriptor `json:"-"`
+	VNFR *catalogue.VirtualNetworkFunctionRecord     `json:"-"`
+	VDU  *catalogue.VirtualDeploymentUnit            `json:"-"`
+}
+
+// Edge is the dependency of Target on Source: Source is instantiated and started before Target,
+// which is configured with the Parameters of Source.
+type Edge struct {
+	Source     string   `json:"source"`
+	Target     string   `json:"target"`
+	Parameters []string `json:"parameters"`
+}
+
+// Graph is a dependency graph, its nodes sorted by name and its edges by source and target.
+type Graph struct {
+	Name  string  `json:"name,omitempty"`
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+
+	nodes map[string]*Node
+	edges map[[2]string]*Edge
+	// The edges by source and by target, sorted as Edges
+	out map[string][]*Edge
+	in  map[string][]*Edge
+	// The cycles, computed on demand and reset when the graph changes
+	cycles [][]string
+}
+
+// New returns an empty graph.
+func New(name string) *Graph {
+	return &Graph{
+		Name:  name,
+		Nodes: []*Node{},
+		Edges: []*Edge{},
+		nodes: make(map[string]*Node),
+		edges: make(map[[2]string]*Edge),
+		out:   make(map[string][]*Edge),
+		in:    make(map[string][]*Edge),
+	}
+}
+
+// AddNode adds a node, replacing the one with the same name if any.
+func (g *Graph) AddNode(n *Node) *Node {
+	if old, ok := g.nodes[n.Name]; ok {
+		*old = *n
+		g.cycles = nil
+		return old
+	}
+
+	g.nodes[n.Name] = n
+	i := sort.Search(len(g.Nodes), func(i int) bool { return g.Nodes[i].Name >= n.Name })
+	g.Nodes = append(g.Nodes, nil)
+	copy(g.Nodes[i+1:], g.Nodes[i:])
+	g.Nodes[i] = n
+	g.cycles = nil
+
+	return n
+}
+
+// AddEdge adds the dependency of target on source, merging the parameters with the ones of the same
+// dependency if any. An error is returned if source or target is not a node of the graph.
+func (g *Graph) AddEdge(source, target string, parameters ...string) (*Edge, error) {
+	for _, name := range []string{source, target} {
+		if _, ok := g.nodes[name]; !ok {
+			return nil, errors.Errorf("unknown node %s", name)
+		}
+	}
+
+	key := [2]string{source, target}
+	e, ok := g.edges[key]
+	if !ok {
+		e = &Edge{Source: source, Target: target, Parameters: []string{}}
+		g.edges[key] = e
+		g.Edges = insertEdge(g.Edges, e)
+		g.out[source] = insertEdge(g.out[source], e)
+		g.in[target] = insertEdge(g.in[target], e)
+		g.cycles = nil
+	}
+	e.Parameters = mergeStrings(e.Parameters, parameters)
+
+	return e, nil
+}
+
+// insertEdge inserts e in edges, sorted by source and target.
+func insertEdge(edges []*Edge, e *Edge) []*Edge {
+	i := sort.Search(len(edges), func(i int) bool {
+		if edges[i].Source != e.Source {
+			return edges[i].Source > e.Source
+		}
+		return edges[i].Target >= e.Target
+	})
+	edges = append(edges, nil)
+	copy(edges[i+1:], edges[i:])
+	edges[i] = e
+
+	return edges
+}
+
+// Node returns the node named name, nil if there is none.
+func (g *Graph) Node(name string) *Node {
+	return g.nodes[name]
+}
+
+// DependsOn returns the names of the nodes name depends on, sorted.
+func (g *Graph) DependsOn(name string) []string {
+	ret := make([]string, len(g.in[name]))
+	for i, e := range g.in[name] {
+		ret[i] = e.Source
+	}
+
+	return ret
+}
+
+// Dependents returns the names of the nodes depending on name, sorted.
+func (g *Graph) Dependents(name string) []string {
+	ret := make([]string, len(g.out[name]))
+	for i, e := range g.out[name] {
+		ret[i] = e.Target
+	}
+
+	return ret
+}
+
+// CycleError is returned when ordering a graph that has cycles.
+type CycleError struct {
+	Cycles [][]string
+}
+
+func (e *CycleError) Error() string {
+	cycles := make([]string, len(e.Cycles))
+	for i, cycle := range e.Cycles {
+		cycles[i] = "[" + strings.Join(cycle, ", ") + "]"
+	}
+
+	return "dependency cycles: " + strings.Join(cycles, "; ")
+}
+
+// Cycles returns the cycles of the graph, as the sorted names of the nodes of each strongly connected
+// component with more than one node or depending on itself. The cycles are sorted by their first node.
+// The Cyclic flag of the nodes is updated too.
+func (g *Graph) Cycles() [][]string {
+	if g.cycles == nil {
+		g.cycles = g.findCycles()
+		g.markCycles()
+	}
+
+	ret := make([][]string, len(g.cycles))
+	for i, cycle := range g.cycles {
+		ret[i] = append([]string{}, cycle...)
+	}
+
+	return ret
+}
+
+func (g *Graph) findCycles() [][]string {
+	index := 0
+	indexes := make(map[string]int)
+	lowlinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := []string{}
+	cycles := [][]string{}
+
+	// Tarjan's strongly connected components
+	var connect func(name string)
+	connect = func(name string) {
+		indexes[name] = index
+		lowlinks[name] = index
+		index++
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, e := range g.out[name] {
+			next := e.Target
+			if _, visited := indexes[next]; !visited {
+				connect(next)
+				lowlinks[name] = minInt(lowlinks[name], lowlinks[next])
+			} else if onStack[next] {
+				lowlinks[name] = minInt(lowlinks[name], indexes[next])
+			}
+		}
+
+		if lowlinks[name] != indexes[name] {
+			return
+		}
+		component := []string{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == name {
+				break
+			}
+		}
+		if len(component) > 1 || g.edges[[2]string{name, name}] != nil {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if _, visited := indexes[n.Name]; !visited {
+			connect(n.Name)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+
+	return cycles
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (g *Graph) markCycles() {
+	for _, n := range g.Nodes {
+		n.Cyclic = false
+	}
+	for _, cycle := range g.cycles {
+		for _, name := range cycle {
+			g.nodes[name].Cyclic = true
+		}
+	}
+}
+
+// Order returns the nodes in the order they are instantiated and started: each step holds the nodes,
+// sorted, whose dependencies are all in the previous steps, so the nodes of a step can be handled
+// in parallel. The reverse order is the one they are terminated in.
+// A *CycleError is returned if the graph has cycles.
+func (g *Graph) Order() ([][]string, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		return nil, &CycleError{Cycles: cycles}
+	}
+
+	dependencies := make(map[string]int)
+	for _, e := range g.Edges {
+		dependencies[e.Target]++
+	}
+
+	steps := [][]string{}
+	step := []string{}
+	for _, n := range g.Nodes {
+		if dependencies[n.Name] == 0 {
+			step = append(step, n.Name)
+		}
+	}
+	for len(step) > 0 {
+		steps = append(steps, step)
+		next := []string{}
+		for _, name := range step {
+			for _, e := range g.out[name] {
+				if dependencies[e.Target]--; dependencies[e.Target] == 0 {
+					next = append(next, e.Target)
+				}
+			}
+		}
+		sort.Strings(next)
+		step = next
+	}
+
+	return steps, nil
+}
+
+// MarkCyclicDependencies sets the CyclicDependency flag of the VNFDs and VNFRs of the nodes to whether
+// they are part of a cycle, as the NFVO expects it to configure them.
+func (g *Graph) MarkCyclicDependencies() {
+	g.Cycles()
+	for _, n := range g.Nodes {
+		if n.VNFD != nil {
+			n.VNFD.CyclicDependency = n.Cyclic
+		}
+		if n.VNFR != nil {
+			n.VNFR.CyclicDependency = n.Cyclic
+		}
+	}
+}
+
+func mergeStrings(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	ret := make([]string, 0, len(a)+len(b))
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !set[s] {
+			set[s] = true
+			ret = append(ret, s)
+		}
+	}
+	sort.Strings(ret)
+
+	return ret
+}
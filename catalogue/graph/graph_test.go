@@ -0,0 +1,236 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+)
+
+// build returns a graph of the nodes and of the edges, written "source>target".
+func build(t *testing.T, nodes []string, edges ...string) *Graph {
+	g := New("test")
+	for _, name := range nodes {
+		g.AddNode(&Node{Name: name})
+	}
+	for _, edge := range edges {
+		ends := strings.Split(edge, ">")
+		if _, err := g.AddEdge(ends[0], ends[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return g
+}
+
+func TestCyclesAndOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		nodes  []string
+		edges  []string
+		cycles [][]string
+		order  [][]string
+	}{
+		{
+			name:   "no edge",
+			nodes:  []string{"b", "a"},
+			cycles: [][]string{},
+			order:  [][]string{{"a", "b"}},
+		},
+		{
+			name:   "layers",
+			nodes:  []string{"db", "server", "client", "monitor"},
+			edges:  []string{"db>server", "server>client", "db>client", "monitor>client"},
+			cycles: [][]string{},
+			order:  [][]string{{"db", "monitor"}, {"server"}, {"client"}},
+		},
+		{
+			name:   "cycle",
+			nodes:  []string{"a", "b", "c", "d"},
+			edges:  []string{"a>b", "b>c", "c>a", "c>d"},
+			cycles: [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:   "self dependency",
+			nodes:  []string{"a", "b"},
+			edges:  []string{"a>a", "a>b"},
+			cycles: [][]string{{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := build(t, tt.nodes, tt.edges...)
+
+			if got := g.Cycles(); !reflect.DeepEqual(got, tt.cycles) {
+				t.Errorf("Cycles() = %q, want %q", got, tt.cycles)
+			}
+			cyclic := make(map[string]bool)
+			for _, cycle := range tt.cycles {
+				for _, name := range cycle {
+					cyclic[name] = true
+				}
+			}
+			for _, n := range g.Nodes {
+				if n.Cyclic != cyclic[n.Name] {
+					t.Errorf("node %s cyclic = %t", n.Name, n.Cyclic)
+				}
+			}
+
+			order, err := g.Order()
+			if len(tt.cycles) > 0 {
+				if _, ok := err.(*CycleError); !ok {
+					t.Errorf("Order() error = %v, want a *CycleError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("Order() = %q, want %q", order, tt.order)
+			}
+		})
+	}
+}
+
+func TestCyclesUpdatedByChanges(t *testing.T) {
+	g := build(t, []string{"a", "b"}, "a>b")
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Fatalf("Cycles() = %q", cycles)
+	}
+
+	g.AddEdge("b", "a")
+	if got, want := g.Cycles(), [][]string{{"a", "b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %q after adding b>a, want %q", got, want)
+	}
+
+	// the returned cycles do not share the cache
+	g.Cycles()[0][0] = "z"
+	if got := g.Cycles()[0][0]; got != "a" {
+		t.Errorf("Cycles() = %q after changing a result", got)
+	}
+}
+
+func TestAddEdge(t *testing.T) {
+	g := build(t, []string{"a", "b"})
+
+	if _, err := g.AddEdge("a", "c"); err == nil {
+		t.Error("AddEdge() to an unknown node succeeded")
+	}
+	g.AddEdge("a", "b", "port", "hostname")
+	g.AddEdge("a", "b", "hostname", "ip")
+	if len(g.Edges) != 1 {
+		t.Fatalf("Edges = %v", g.Edges)
+	}
+	if got, want := g.Edges[0].Parameters, []string{"hostname", "ip", "port"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Parameters = %q, want %q", got, want)
+	}
+	if got := g.DependsOn("b"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("DependsOn(b) = %q", got)
+	}
+	if got := g.Dependents("a"); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("Dependents(a) = %q", got)
+	}
+}
+
+func TestLargeGraph(t *testing.T) {
+	// a chain of n nodes, each also depending on the first one
+	const n = 5000
+	g := New("large")
+	for i := 0; i < n; i++ {
+		g.AddNode(&Node{Name: fmt.Sprintf("vnf-%05d", i)})
+	}
+	for i := 1; i < n; i++ {
+		g.AddEdge(fmt.Sprintf("vnf-%05d", i-1), fmt.Sprintf("vnf-%05d", i))
+		g.AddEdge("vnf-00000", fmt.Sprintf("vnf-%05d", i))
+	}
+
+	order, err := g.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != n {
+		t.Errorf("Order() has %d steps, want %d", len(order), n)
+	}
+}
+
+func TestFromNSD(t *testing.T) {
+	nsd := &catalogue.NetworkServiceDescriptor{}
+	err := json.Unmarshal([]byte(`{
+		"name": "ns",
+		"vnfd": [
+			{"name": "server", "type": "server"},
+			{"name": "client", "type": "client", "requires": {"server": {"parameters": ["port"]}}},
+			{"name": "monitor", "type": "monitor"}
+		],
+		"vnf_dependency": [{"source": {"name": "monitor"}, "target": {"name": "client"}, "parameters": ["url"]}]
+	}`), nsd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := FromNSD(nsd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := g.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"monitor", "server"}, {"client"}}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Order() = %q, want %q", order, want)
+	}
+
+	dot := g.DOT()
+	for _, line := range []string{`"server" -> "client" [label="port"];`, `"monitor" -> "client" [label="url"];`} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("DOT() has no %s:\n%s", line, dot)
+		}
+	}
+
+	nsd.VNFDs[0].Requires = map[string]*catalogue.RequiresParameters{"client": {Parameters: []string{"ip"}}}
+	g, err = FromNSD(nsd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.MarkCyclicDependencies()
+	if !nsd.VNFDs[0].CyclicDependency || !nsd.VNFDs[1].CyclicDependency || nsd.VNFDs[2].CyclicDependency {
+		t.Errorf("CyclicDependency = %t, %t, %t, want true, true, false",
+			nsd.VNFDs[0].CyclicDependency, nsd.VNFDs[1].CyclicDependency, nsd.VNFDs[2].CyclicDependency)
+	}
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Cycles [][]string
+		Order  [][]string
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"client", "server"}}; !reflect.DeepEqual(decoded.Cycles, want) || decoded.Order != nil {
+		t.Errorf("MarshalJSON() = %s", data)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultAuditLogLines is the number of lines of the audit log of a VNFR kept by the VNFM SDK.
+const DefaultAuditLogLines = 100
+
+// NewHistoryLifecycleEvent returns an event of the lifecycle event history executed now.
+func NewHistoryLifecycleEvent(event Event, description string) *HistoryLifecycleEvent {
+	return &HistoryLifecycleEvent{
+		Event:       string(event),
+		Description: description,
+		ExecutedAt:  string(NewDate()),
+	}
+}
+
+// AddHistoryEvent appends an event executed now to the lifecycle event history of the VNFR.
+func (vnfr *VirtualNetworkFunctionRecord) AddHistoryEvent(event Event, description string) *HistoryLifecycleEvent {
+	e := NewHistoryLifecycleEvent(event, description)
+	vnfr.LifecycleEventHistory = append(vnfr.LifecycleEventHistory, e)
+
+	return e
+}
+
+// AppendAuditLog appends a line, prefixed by the current date, to the audit log of the VNFR,
+// dropping the oldest lines to keep at most maxLines. The log is unbounded if maxLines is not positive.
+func (vnfr *VirtualNetworkFunctionRecord) AppendAuditLog(maxLines int, format string, args ...interface{}) {
+	line := fmt.Sprintf("%s %s", NewDate(), fmt.Sprintf(format, args...))
+	line = strings.Replace(line, "\n", " ", -1)
+
+	var lines []string
+	if vnfr.AuditLog != "" {
+		lines = strings.Split(vnfr.AuditLog, "\n")
+	}
+	lines = append(lines, line)
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+
+	vnfr.AuditLog = strings.Join(lines, "\n")
+}
+
+// AuditLogLines returns the lines of the audit log of the VNFR, oldest first.
+func (vnfr *VirtualNetworkFunctionRecord) AuditLogLines() []string {
+	if vnfr.AuditLog == "" {
+		return nil
+	}
+
+	return strings.Split(vnfr.AuditLog, "\n")
+}
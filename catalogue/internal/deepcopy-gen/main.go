@@ -0,0 +1,278 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// deepcopy-gen generates the DeepCopy and DeepCopyInto methods of the struct and slice types of a package.
+// It is run by go generate in the directory of the package, see catalogue/deepcopy.go.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+type kind int
+
+const (
+	// copied by assignment: basic types and named types based on them
+	kindValue kind = iota
+	kindStruct
+	kindSlice
+	kindInterface
+)
+
+type generator struct {
+	kinds map[string]kind
+	buf   bytes.Buffer
+	// the nesting of the loops being printed, to name their variables
+	depth int
+}
+
+func main() {
+	output := flag.String("o", "deepcopy_generated.go", "the file to generate")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != *output
+	}, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		log.Fatalf("expected one package, found %d", len(pkgs))
+	}
+
+	var pkgName string
+	specs := make(map[string]*ast.TypeSpec)
+	for name, pkg := range pkgs {
+		pkgName = name
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					specs[ts.Name.Name] = ts
+				}
+			}
+		}
+	}
+
+	g := &generator{kinds: make(map[string]kind)}
+	for name, ts := range specs {
+		switch ts.Type.(type) {
+		case *ast.StructType:
+			g.kinds[name] = kindStruct
+		case *ast.ArrayType:
+			g.kinds[name] = kindSlice
+		case *ast.InterfaceType:
+			g.kinds[name] = kindInterface
+		default:
+			g.kinds[name] = kindValue
+		}
+	}
+
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g.printf("// Code generated by deepcopy-gen. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", pkgName)
+
+	for _, name := range names {
+		switch t := specs[name].Type.(type) {
+		case *ast.StructType:
+			g.genStruct(name, t)
+		case *ast.ArrayType:
+			g.genSlice(name, t)
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		log.Fatalf("invalid generated code: %v\n%s", err, g.buf.String())
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) genStruct(name string, t *ast.StructType) {
+	g.printf("// DeepCopyInto copies the receiver into out, which must be non-nil.\n")
+	g.printf("func (in *%s) DeepCopyInto(out *%s) {\n", name, name)
+	g.printf("*out = *in\n")
+	for _, field := range t.Fields.List {
+		names := field.Names
+		if len(names) == 0 {
+			// embedded field, named after its type
+			typ := field.Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			names = []*ast.Ident{ast.NewIdent(types.ExprString(typ))}
+		}
+		for _, n := range names {
+			g.copy("out."+n.Name, "in."+n.Name, field.Type)
+		}
+	}
+	g.printf("}\n\n")
+
+	g.printf("// DeepCopy returns a deep copy of the receiver, nil if it is nil.\n")
+	g.printf("func (in *%s) DeepCopy() *%s {\n", name, name)
+	g.printf("if in == nil {\nreturn nil\n}\n")
+	g.printf("out := new(%s)\n", name)
+	g.printf("in.DeepCopyInto(out)\n")
+	g.printf("return out\n")
+	g.printf("}\n\n")
+}
+
+func (g *generator) genSlice(name string, t *ast.ArrayType) {
+	g.printf("// DeepCopy returns a deep copy of the receiver, nil if it is nil.\n")
+	g.printf("func (in %s) DeepCopy() %s {\n", name, name)
+	g.printf("if in == nil {\nreturn nil\n}\n")
+	g.printf("out := make(%s, len(in))\n", name)
+	g.printf("copy(out, in)\n")
+	g.copyElements("out", "in", t.Elt)
+	g.printf("return out\n")
+	g.printf("}\n\n")
+}
+
+// needsCopy reports whether the values of type t share memory once assigned.
+func (g *generator) needsCopy(t ast.Expr) bool {
+	switch t := t.(type) {
+	case *ast.Ident:
+		return g.kinds[t.Name] != kindValue
+	case *ast.SelectorExpr:
+		log.Fatalf("unsupported type %s", types.ExprString(t))
+	}
+
+	return true
+}
+
+// loopVar names a variable of the loop at the current depth.
+func (g *generator) loopVar(name string) string {
+	if g.depth == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s%d", name, g.depth)
+}
+
+// isStructPointer reports whether t is a pointer to a struct type of the package, copied by its DeepCopy method.
+func (g *generator) isStructPointer(t ast.Expr) bool {
+	star, ok := t.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := star.X.(*ast.Ident)
+	return ok && g.kinds[ident.Name] == kindStruct
+}
+
+// copy prints the statements replacing dst, a shallow copy of src, with a deep copy of src.
+func (g *generator) copy(dst, src string, t ast.Expr) {
+	if !g.needsCopy(t) {
+		return
+	}
+
+	switch t := t.(type) {
+	case *ast.Ident:
+		switch g.kinds[t.Name] {
+		case kindStruct:
+			g.printf("%s.DeepCopyInto(&%s)\n", src, dst)
+		case kindSlice:
+			g.printf("%s = %s.DeepCopy()\n", dst, src)
+		case kindInterface:
+			g.printf("%s = deepCopyInterface(%s)\n", dst, src)
+		}
+
+	case *ast.InterfaceType:
+		g.printf("%s = deepCopyInterface(%s)\n", dst, src)
+
+	case *ast.StarExpr:
+		if g.isStructPointer(t) {
+			g.printf("%s = %s.DeepCopy()\n", dst, src)
+			return
+		}
+		g.printf("if %s != nil {\n", src)
+		g.printf("%s = new(%s)\n", dst, types.ExprString(t.X))
+		g.printf("*%s = *%s\n", dst, src)
+		g.copy("*"+dst, "*"+src, t.X)
+		g.printf("}\n")
+
+	case *ast.ArrayType:
+		if t.Len != nil {
+			log.Fatalf("unsupported array type %s", types.ExprString(t))
+		}
+		g.printf("if %s != nil {\n", src)
+		g.printf("%s = make(%s, len(%s))\n", dst, types.ExprString(t), src)
+		g.printf("copy(%s, %s)\n", dst, src)
+		g.copyElements(dst, src, t.Elt)
+		g.printf("}\n")
+
+	case *ast.MapType:
+		key, value := g.loopVar("key"), g.loopVar("value")
+		g.printf("if %s != nil {\n", src)
+		g.printf("%s = make(%s, len(%s))\n", dst, types.ExprString(t), src)
+		g.printf("for %s, %s := range %s {\n", key, value, src)
+		if g.isStructPointer(t.Value) {
+			value += ".DeepCopy()"
+		} else if g.needsCopy(t.Value) {
+			c := g.loopVar("c")
+			g.printf("%s := %s\n", c, value)
+			g.depth++
+			g.copy(c, value, t.Value)
+			g.depth--
+			value = c
+		}
+		g.printf("%s[%s] = %s\n", dst, key, value)
+		g.printf("}\n")
+		g.printf("}\n")
+
+	default:
+		log.Fatalf("unsupported type %s", types.ExprString(t))
+	}
+}
+
+// copyElements prints the statements deep copying the elements of the slice src into dst, of the same length.
+func (g *generator) copyElements(dst, src string, elt ast.Expr) {
+	if !g.needsCopy(elt) {
+		return
+	}
+
+	i := g.loopVar("i")
+	g.printf("for %s := range %s {\n", i, src)
+	g.depth++
+	g.copy(fmt.Sprintf("%s[%s]", dst, i), fmt.Sprintf("%s[%s]", src, i), elt)
+	g.depth--
+	g.printf("}\n")
+}
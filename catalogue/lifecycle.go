@@ -0,0 +1,293 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StateMachine describes the allowed transitions between the statuses of a record.
+// Staying in the same status is always allowed.
+type StateMachine struct {
+	name string
+	// the status of a record that has none yet
+	initial     Status
+	transitions map[Status][]Status
+}
+
+var (
+	// VNFRStates are the transitions of the status of a VirtualNetworkFunctionRecord.
+	VNFRStates = &StateMachine{
+		name:    "VNFR",
+		initial: StatusNull,
+		transitions: map[Status][]Status{
+			StatusNull:        {StatusInitialized, StatusError, StatusTerminated},
+			StatusInitialized: {StatusInactive, StatusActive, StatusTerminated, StatusError},
+			StatusInactive:    {StatusActive, StatusScaling, StatusTerminated, StatusError},
+			StatusActive:      {StatusInactive, StatusScaling, StatusTerminated, StatusError},
+			StatusScaling:     {StatusActive, StatusInactive, StatusError},
+			StatusError:       {StatusResuming, StatusTerminated},
+			StatusResuming:    {StatusInitialized, StatusInactive, StatusActive, StatusError},
+			StatusTerminated:  {},
+		},
+	}
+
+	// NSRStates are the transitions of the status of a NetworkServiceRecord.
+	NSRStates = &StateMachine{
+		name:    "NSR",
+		initial: StatusNull,
+		transitions: map[Status][]Status{
+			StatusNull:        {StatusInitialized, StatusError, StatusTerminated},
+			StatusInitialized: {StatusInactive, StatusActive, StatusTerminated, StatusError},
+			StatusInactive:    {StatusActive, StatusScaling, StatusTerminated, StatusError},
+			StatusActive:      {StatusInactive, StatusScaling, StatusTerminated, StatusError},
+			StatusScaling:     {StatusActive, StatusError},
+			StatusError:       {StatusResuming, StatusTerminated},
+			StatusResuming:    {StatusActive, StatusError},
+			StatusTerminated:  {},
+		},
+	}
+
+	// VNFCStates are the transitions of the state of a VNFCInstance, which has none until it is deployed.
+	VNFCStates = &StateMachine{
+		name:    "VNFC instance",
+		initial: "",
+		transitions: map[Status][]Status{
+			"":             {StatusInactive, StatusActive, StatusStandby, StatusFailed},
+			StatusInactive: {StatusActive, StatusFailed},
+			StatusActive:   {StatusInactive, StatusStandby, StatusFailed},
+			StatusStandby:  {StatusActive, StatusFailed},
+			StatusFailed:   {StatusActive, StatusInactive},
+		},
+	}
+)
+
+// Allowed reports whether a record can go from the status from to the status to.
+// An empty status is the initial one.
+func (m *StateMachine) Allowed(from, to Status) bool {
+	from, to = m.normalize(from), m.normalize(to)
+	if from == to {
+		return true
+	}
+
+	for _, next := range m.transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check returns a *TransitionError if the transition is not allowed.
+func (m *StateMachine) Check(from, to Status) error {
+	if m.Allowed(from, to) {
+		return nil
+	}
+
+	return &TransitionError{Machine: m.name, From: m.normalize(from), To: m.normalize(to)}
+}
+
+// Next returns the statuses a record can go to from the status from, nil if it is final or unknown.
+func (m *StateMachine) Next(from Status) []Status {
+	next := m.transitions[m.normalize(from)]
+	if len(next) == 0 {
+		return nil
+	}
+
+	ret := make([]Status, len(next))
+	copy(ret, next)
+
+	return ret
+}
+
+func (m *StateMachine) normalize(s Status) Status {
+	if s == "" {
+		return m.initial
+	}
+
+	return s
+}
+
+// TransitionError is a transition not allowed by a StateMachine.
+type TransitionError struct {
+	Machine string
+	// Path locates the record in the VNFR for the VNFC instances, e.g. "vdu[vdu1].vnfc_instance[abc]"
+	Path     string
+	From, To Status
+}
+
+func (e *TransitionError) Error() string {
+	what := e.Machine
+	if e.Path != "" {
+		what = fmt.Sprintf("%s %s", e.Machine, e.Path)
+	}
+
+	return fmt.Sprintf("%s cannot go from %s to %s", what, describeStatus(e.From), describeStatus(e.To))
+}
+
+func describeStatus(s Status) string {
+	if s == "" {
+		return "no state"
+	}
+
+	return string(s)
+}
+
+// TransitionErrors are all the transitions not allowed between two versions of a VNFR.
+type TransitionErrors []*TransitionError
+
+func (errs TransitionErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// transitionEvent names the lifecycle event recorded in the history for a transition.
+func transitionEvent(from, to Status) Event {
+	switch to {
+	case StatusInitialized:
+		return EventInstantiate
+	case StatusInactive:
+		if from == StatusInitialized {
+			return EventConfigure
+		}
+		return EventStop
+	case StatusActive:
+		if from == StatusScaling {
+			return EventScale
+		}
+		return EventStart
+	case StatusScaling:
+		return EventScale
+	case StatusTerminated:
+		return EventTerminate
+	case StatusError, StatusFailed:
+		return EventError
+	default:
+		return Event(to)
+	}
+}
+
+func newHistoryEvent(path string, from, to Status) *HistoryLifecycleEvent {
+	description := fmt.Sprintf("%s -> %s", describeStatus(from), describeStatus(to))
+	if path != "" {
+		description = path + ": " + description
+	}
+
+	return NewHistoryLifecycleEvent(transitionEvent(from, to), description)
+}
+
+// Transition sets the status of the VNFR to to and appends the transition to its lifecycle event history.
+// A *TransitionError is returned, and nothing changed, if VNFRStates does not allow it.
+func (vnfr *VirtualNetworkFunctionRecord) Transition(to Status) error {
+	from := vnfr.Status
+	if err := VNFRStates.Check(from, to); err != nil {
+		return err
+	}
+	if VNFRStates.normalize(from) == to {
+		return nil
+	}
+
+	vnfr.Status = to
+	vnfr.LifecycleEventHistory = append(vnfr.LifecycleEventHistory, newHistoryEvent("", VNFRStates.normalize(from), to))
+
+	return nil
+}
+
+// Transition sets the status of the NSR to to and appends the transition to its lifecycle event history.
+// A *TransitionError is returned, and nothing changed, if NSRStates does not allow it.
+func (nsr *NetworkServiceRecord) Transition(to Status) error {
+	from := nsr.Status
+	if err := NSRStates.Check(from, to); err != nil {
+		return err
+	}
+	if NSRStates.normalize(from) == to {
+		return nil
+	}
+
+	nsr.Status = to
+	nsr.LifecycleEventHistory = append(nsr.LifecycleEventHistory, &LifecycleEvent{
+		Event:           transitionEvent(NSRStates.normalize(from), to),
+		LifecycleEvents: []string{fmt.Sprintf("%s -> %s", NSRStates.normalize(from), to)},
+	})
+
+	return nil
+}
+
+// vnfcStates calls f with the path of each VNFC instance of the VNFR, skipping the nil VDUs and instances.
+func vnfcStates(vnfr *VirtualNetworkFunctionRecord, f func(path string, vnfc *VNFCInstance)) {
+	vduKeys := vduKeys(vnfr.VDUs)
+	for i, vdu := range vnfr.VDUs {
+		if vdu == nil {
+			continue
+		}
+		keys := vnfcKeys(vdu.VNFCInstances)
+		for j, vnfc := range vdu.VNFCInstances {
+			if vnfc != nil {
+				f(fmt.Sprintf("vdu[%s].vnfc_instance[%s]", vduKeys[i], keys[j]), vnfc)
+			}
+		}
+	}
+}
+
+// RecordTransitions checks the changes of status of the VNFR and of the state of its VNFC instances from before
+// to after, e.g. the VNFR given to a handler and the one it returned, and appends them to the lifecycle event
+// history of after. before can be nil for a VNFR not created yet.
+// The VNFC instances are matched by VDU and by ID, VIM ID or hostname, or else by index; the nil ones are skipped.
+// If a transition is not allowed TransitionErrors are returned and the history is left as it is.
+func RecordTransitions(before, after *VirtualNetworkFunctionRecord) error {
+	if before == nil {
+		before = &VirtualNetworkFunctionRecord{Status: StatusNull}
+	}
+
+	errs := TransitionErrors{}
+	events := []*HistoryLifecycleEvent{}
+	record := func(m *StateMachine, path string, from, to Status) {
+		from, to = m.normalize(from), m.normalize(to)
+		if from == to {
+			return
+		}
+		if !m.Allowed(from, to) {
+			errs = append(errs, &TransitionError{Machine: m.name, Path: path, From: from, To: to})
+			return
+		}
+		events = append(events, newHistoryEvent(path, from, to))
+	}
+
+	record(VNFRStates, "", before.Status, after.Status)
+
+	// the states by path
+	states := make(map[string]Status)
+	vnfcStates(before, func(path string, vnfc *VNFCInstance) {
+		states[path] = Status(vnfc.State)
+	})
+	vnfcStates(after, func(path string, vnfc *VNFCInstance) {
+		record(VNFCStates, path, states[path], Status(vnfc.State))
+	})
+
+	if len(errs) > 0 {
+		return errs
+	}
+	after.LifecycleEventHistory = append(after.LifecycleEventHistory, events...)
+
+	return nil
+}
@@ -0,0 +1,359 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStateMachines(t *testing.T) {
+	tests := []struct {
+		machine  *StateMachine
+		from, to Status
+		allowed  bool
+	}{
+		{VNFRStates, StatusNull, StatusInitialized, true},
+		{VNFRStates, "", StatusInitialized, true},
+		{VNFRStates, "", StatusNull, true},
+		{VNFRStates, StatusActive, StatusActive, true},
+		{VNFRStates, StatusInitialized, StatusActive, true},
+		{VNFRStates, StatusActive, StatusScaling, true},
+		{VNFRStates, StatusScaling, StatusInactive, true},
+		{VNFRStates, StatusError, StatusResuming, true},
+		{VNFRStates, StatusResuming, StatusInitialized, true},
+		{VNFRStates, StatusNull, StatusActive, false},
+		{VNFRStates, StatusTerminated, StatusActive, false},
+		{VNFRStates, StatusScaling, StatusTerminated, false},
+		{VNFRStates, StatusError, StatusActive, false},
+		{VNFRStates, Status("UNKNOWN"), StatusActive, false},
+
+		{NSRStates, StatusNull, StatusInitialized, true},
+		{NSRStates, StatusInactive, StatusActive, true},
+		{NSRStates, StatusResuming, StatusActive, true},
+		{NSRStates, StatusScaling, StatusInactive, false},
+		{NSRStates, StatusResuming, StatusInitialized, false},
+		{NSRStates, StatusTerminated, StatusNull, false},
+
+		{VNFCStates, "", StatusActive, true},
+		{VNFCStates, StatusActive, StatusStandby, true},
+		{VNFCStates, StatusStandby, StatusFailed, true},
+		{VNFCStates, StatusFailed, StatusActive, true},
+		{VNFCStates, StatusInactive, StatusStandby, false},
+		{VNFCStates, StatusActive, "", false},
+		{VNFCStates, StatusNull, StatusActive, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.machine.name+" "+describeStatus(tt.from)+" to "+describeStatus(tt.to), func(t *testing.T) {
+			if got := tt.machine.Allowed(tt.from, tt.to); got != tt.allowed {
+				t.Errorf("Allowed() = %t, want %t", got, tt.allowed)
+			}
+
+			err := tt.machine.Check(tt.from, tt.to)
+			if tt.allowed {
+				if err != nil {
+					t.Errorf("Check() = %v, want nil", err)
+				}
+				return
+			}
+			terr, ok := err.(*TransitionError)
+			if !ok {
+				t.Fatalf("Check() = %v, want a *TransitionError", err)
+			}
+			if terr.From != tt.machine.normalize(tt.from) || terr.To != tt.machine.normalize(tt.to) {
+				t.Errorf("got the transition from %q to %q", terr.From, terr.To)
+			}
+		})
+	}
+}
+
+func TestStateMachineNext(t *testing.T) {
+	tests := []struct {
+		machine *StateMachine
+		from    Status
+		want    []Status
+	}{
+		{VNFRStates, "", []Status{StatusInitialized, StatusError, StatusTerminated}},
+		{VNFRStates, StatusError, []Status{StatusResuming, StatusTerminated}},
+		{VNFRStates, StatusTerminated, nil},
+		{VNFRStates, Status("UNKNOWN"), nil},
+		{VNFCStates, "", []Status{StatusInactive, StatusActive, StatusStandby, StatusFailed}},
+	}
+
+	for _, tt := range tests {
+		got := tt.machine.Next(tt.from)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s.Next(%q) = %v, want %v", tt.machine.name, tt.from, got, tt.want)
+		}
+		// the transitions of the machine cannot be changed through the result
+		if len(got) > 0 {
+			got[0] = Status("CHANGED")
+			if again := tt.machine.Next(tt.from); !reflect.DeepEqual(again, tt.want) {
+				t.Errorf("%s.Next(%q) = %v after changing its result", tt.machine.name, tt.from, again)
+			}
+		}
+	}
+}
+
+func TestTransitionError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			&TransitionError{Machine: "VNFR", From: StatusTerminated, To: StatusActive},
+			"VNFR cannot go from TERMINATED to ACTIVE",
+		},
+		{
+			&TransitionError{Machine: "VNFC instance", Path: "vdu[vdu1].vnfc_instance[abc]", From: StatusActive, To: ""},
+			"VNFC instance vdu[vdu1].vnfc_instance[abc] cannot go from ACTIVE to no state",
+		},
+		{
+			TransitionErrors{
+				{Machine: "VNFR", From: StatusNull, To: StatusActive},
+				{Machine: "VNFC instance", Path: "vdu[vdu1].vnfc_instance[abc]", From: StatusInactive, To: StatusStandby},
+			},
+			"VNFR cannot go from NULL to ACTIVE; VNFC instance vdu[vdu1].vnfc_instance[abc] cannot go from INACTIVE to STANDBY",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestVNFRTransition(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   Status
+		to     []Status
+		status Status
+		events []string
+		err    bool
+	}{
+		{
+			name:   "deployment",
+			from:   StatusNull,
+			to:     []Status{StatusInitialized, StatusInactive, StatusActive},
+			status: StatusActive,
+			events: []string{"INSTANTIATE", "CONFIGURE", "START"},
+		},
+		{
+			name:   "scaling",
+			from:   StatusActive,
+			to:     []Status{StatusScaling, StatusActive},
+			status: StatusActive,
+			events: []string{"SCALE", "SCALE"},
+		},
+		{
+			name:   "same status",
+			from:   StatusActive,
+			to:     []Status{StatusActive},
+			status: StatusActive,
+			events: []string{},
+		},
+		{
+			name:   "stop and fail",
+			from:   StatusActive,
+			to:     []Status{StatusInactive, StatusError},
+			status: StatusError,
+			events: []string{"STOP", "ERROR"},
+		},
+		{
+			name:   "not allowed",
+			from:   StatusTerminated,
+			to:     []Status{StatusActive},
+			status: StatusTerminated,
+			events: []string{},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vnfr := &VirtualNetworkFunctionRecord{Status: tt.from, LifecycleEventHistory: []*HistoryLifecycleEvent{}}
+			var err error
+			for _, to := range tt.to {
+				if err = vnfr.Transition(to); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.err {
+				t.Errorf("got the error %v", err)
+			}
+			if vnfr.Status != tt.status {
+				t.Errorf("got the status %s, want %s", vnfr.Status, tt.status)
+			}
+
+			events := []string{}
+			for _, e := range vnfr.LifecycleEventHistory {
+				events = append(events, e.Event)
+			}
+			if !reflect.DeepEqual(events, tt.events) {
+				t.Errorf("got the events %v, want %v", events, tt.events)
+			}
+		})
+	}
+}
+
+func TestNSRTransition(t *testing.T) {
+	nsr := &NetworkServiceRecord{Status: StatusNull, LifecycleEventHistory: LifecycleEvents{}}
+	for _, to := range []Status{StatusInitialized, StatusActive, StatusActive} {
+		if err := nsr.Transition(to); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := nsr.Transition(StatusNull); err == nil {
+		t.Error("got no error going back to NULL")
+	}
+
+	want := LifecycleEvents{
+		{Event: EventInstantiate, LifecycleEvents: []string{"NULL -> INITIALIZED"}},
+		{Event: EventStart, LifecycleEvents: []string{"INITIALIZED -> ACTIVE"}},
+	}
+	if nsr.Status != StatusActive || !reflect.DeepEqual(nsr.LifecycleEventHistory, want) {
+		t.Errorf("got the status %s and the history %v", nsr.Status, nsr.LifecycleEventHistory)
+	}
+}
+
+func TestRecordTransitions(t *testing.T) {
+	vnfr := func(status Status, states ...string) *VirtualNetworkFunctionRecord {
+		vdu := &VirtualDeploymentUnit{Name: "vdu1", VNFCInstances: []*VNFCInstance{}}
+		for i, state := range states {
+			vdu.VNFCInstances = append(vdu.VNFCInstances, &VNFCInstance{ID: string(rune('a' + i)), State: state})
+		}
+		return &VirtualNetworkFunctionRecord{
+			Status:                status,
+			VDUs:                  []*VirtualDeploymentUnit{vdu},
+			LifecycleEventHistory: []*HistoryLifecycleEvent{},
+		}
+	}
+	// the VNFC instances without identifiers, e.g. created by the handler
+	anonymous := func(status Status, states ...string) *VirtualNetworkFunctionRecord {
+		r := vnfr(status)
+		for _, state := range states {
+			r.VDUs[0].VNFCInstances = append(r.VDUs[0].VNFCInstances, &VNFCInstance{State: state})
+		}
+		return r
+	}
+	withNil := func(r *VirtualNetworkFunctionRecord) *VirtualNetworkFunctionRecord {
+		r.VDUs[0].VNFCInstances = append(r.VDUs[0].VNFCInstances, nil)
+		r.VDUs = append(r.VDUs, nil)
+		return r
+	}
+
+	tests := []struct {
+		name          string
+		before, after *VirtualNetworkFunctionRecord
+		// the descriptions of the events recorded
+		events []string
+		errs   []string
+	}{
+		{
+			name:   "created",
+			before: nil,
+			after:  vnfr(StatusInitialized, "ACTIVE"),
+			events: []string{"NULL -> INITIALIZED", "vdu[vdu1].vnfc_instance[a]: no state -> ACTIVE"},
+		},
+		{
+			name:   "unchanged",
+			before: vnfr(StatusActive, "ACTIVE", "STANDBY"),
+			after:  vnfr(StatusActive, "ACTIVE", "STANDBY"),
+			events: []string{},
+		},
+		{
+			name:   "failover",
+			before: vnfr(StatusActive, "ACTIVE", "STANDBY"),
+			after:  vnfr(StatusActive, "FAILED", "ACTIVE"),
+			events: []string{"vdu[vdu1].vnfc_instance[a]: ACTIVE -> FAILED", "vdu[vdu1].vnfc_instance[b]: STANDBY -> ACTIVE"},
+		},
+		{
+			name:   "scaled out",
+			before: vnfr(StatusActive, "ACTIVE"),
+			after:  vnfr(StatusActive, "ACTIVE", "ACTIVE"),
+			events: []string{"vdu[vdu1].vnfc_instance[b]: no state -> ACTIVE"},
+		},
+		{
+			name:   "nil VDU and VNFC instance",
+			before: withNil(vnfr(StatusActive, "ACTIVE")),
+			after:  withNil(vnfr(StatusActive, "STANDBY")),
+			events: []string{"vdu[vdu1].vnfc_instance[a]: ACTIVE -> STANDBY"},
+		},
+		{
+			name:   "created without identifiers",
+			before: vnfr(StatusActive),
+			after:  anonymous(StatusActive, "ACTIVE", "STANDBY"),
+			events: []string{"vdu[vdu1].vnfc_instance[#0]: no state -> ACTIVE", "vdu[vdu1].vnfc_instance[#1]: no state -> STANDBY"},
+		},
+		{
+			name:   "without identifiers not allowed",
+			before: anonymous(StatusActive, "INACTIVE", "ACTIVE"),
+			after:  anonymous(StatusActive, "STANDBY", "ACTIVE"),
+			errs:   []string{"VNFC instance vdu[vdu1].vnfc_instance[#0] cannot go from INACTIVE to STANDBY"},
+		},
+		{
+			name:   "not allowed",
+			before: vnfr(StatusTerminated, "ACTIVE"),
+			after:  vnfr(StatusActive, "INACTIVE"),
+			errs:   []string{"VNFR cannot go from TERMINATED to ACTIVE"},
+		},
+		{
+			name:   "VNFC instance not allowed",
+			before: vnfr(StatusActive, "INACTIVE"),
+			after:  vnfr(StatusActive, "STANDBY"),
+			errs:   []string{"VNFC instance vdu[vdu1].vnfc_instance[a] cannot go from INACTIVE to STANDBY"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RecordTransitions(tt.before, tt.after)
+
+			if len(tt.errs) > 0 {
+				errs, ok := err.(TransitionErrors)
+				if !ok {
+					t.Fatalf("got %v, want TransitionErrors", err)
+				}
+				got := []string{}
+				for _, e := range errs {
+					got = append(got, e.Error())
+				}
+				if !reflect.DeepEqual(got, tt.errs) {
+					t.Errorf("got the errors %q, want %q", got, tt.errs)
+				}
+				if len(tt.after.LifecycleEventHistory) != 0 {
+					t.Errorf("got the history %v, want it unchanged", tt.after.LifecycleEventHistory)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := []string{}
+			for _, e := range tt.after.LifecycleEventHistory {
+				got = append(got, e.Description)
+			}
+			if !reflect.DeepEqual(got, tt.events) {
+				t.Errorf("got the events %q, want %q", got, tt.events)
+			}
+		})
+	}
+}
@@ -61,6 +61,23 @@ type NFVEntityDescriptor struct {
 	ConnectionPoints          []*ConnectionPoint              `json:"connection_point"`
 }
 
+// PhysicalNetworkFunctionDescriptor as described in ETSI GS NFV-MAN 001 V1.1.1 (2014-12)
+type PhysicalNetworkFunctionDescriptor struct {
+	ID                 string                   `json:"id,omitempty"`
+	HbVersion          int                      `json:"hbVersion,omitempty"`
+	ProjectID          string                   `json:"projectId"`
+	Shared             bool                     `json:"shared,omitempty"`
+	Metadata           map[string]string        `json:"metadata,omitempty"`
+	Vendor             string                   `json:"vendor"`
+	Version            string                   `json:"version"`
+	Description        string                   `json:"description"`
+	ConnectionPoints   []*ConnectionPoint       `json:"connection_point"`
+	VirtualLinks       []*VirtualLinkDescriptor `json:"virtual_link"`
+	DescriptorVersion  string                   `json:"descriptor_version"`
+	DeploymentFlavours []*DeploymentFlavour     `json:"deployment_flavour"`
+	PNFDSecurity       *Security                `json:"pnfd_security,omitempty"`
+}
+
 // VDUDepencency as described in ETSI GS NFV-MAN 001 V1.1.1 (2014-12)
 type VDUDependency struct {
 	ID        string                 `json:"id,omitempty"`
@@ -160,6 +177,19 @@ type VNFComponent struct {
 	ConnectionPoints []*VNFDConnectionPoint `json:"connection_point"`
 }
 
+// VNFDependency describes a dependency between two VNFs of a NetworkServiceDescriptor:
+// the target VNF needs the parameters provided by the source one.
+type VNFDependency struct {
+	ID         string                            `json:"id,omitempty"`
+	HbVersion  int                               `json:"hbVersion,omitempty"`
+	ProjectID  string                            `json:"projectId"`
+	Shared     bool                              `json:"shared,omitempty"`
+	Metadata   map[string]string                 `json:"metadata,omitempty"`
+	Source     *VirtualNetworkFunctionDescriptor `json:"source,omitempty"`
+	Target     *VirtualNetworkFunctionDescriptor `json:"target,omitempty"`
+	Parameters []string                          `json:"parameters"`
+}
+
 // Virtual Network Function Descriptor Connection Point as defined by
 // ETSI GS NFV-MAN 001 V1.1.1
 type VNFDConnectionPoint struct {
@@ -114,6 +114,12 @@ const (
 
 	// Resuming
 	StatusResuming = Status("RESUMING")
+
+	// Standby, VNFC instances only
+	StatusStandby = Status("STANDBY")
+
+	// Failed, VNFC instances only
+	StatusFailed = Status("FAILED")
 )
 
 type VirtualLinkRecord struct {
@@ -78,14 +78,18 @@ func (cfg *Configuration) Append(p *ConfigurationParameter) {
 	cfg.ConfigurationParameters = append(cfg.ConfigurationParameters, p)
 }
 
+// Date is a date as the NFVO formats and parses it, e.g. "Jan 2, 2006 3:04:05 PM".
 type Date string
 
+// DateLayout is the layout of a Date, the default date and time format of the Java NFVO.
+const DateLayout = "Jan 2, 2006 3:04:05 PM"
+
 func NewDate() Date {
 	return NewDateWithTime(time.Now())
 }
 
 func NewDateWithTime(t time.Time) Date {
-	return Date(t.Format("Jan 2, 2006 3:4:5 PM"))
+	return Date(t.Format(DateLayout))
 }
 
 func UnixDate(timestamp int64) Date {
@@ -97,16 +101,15 @@ func (d Date) Time() time.Time {
 		return time.Unix(0, 0)
 	}
 
-	t, err := time.Parse("Jan 2, 2006 3:4:5 PM", string(d))
-	// Try other formats
-	if err != nil {
-		t, err = time.Parse("Jan 02, 2006 03:04:05 PM", string(d))
-		if err != nil {
-			return time.Unix(0, 0)
+	// "3:4:5" accepts the minutes and seconds with or without padding, as the NFVO and older
+	// versions of the SDK wrote them, while "04" and "05" in DateLayout require two digits
+	for _, layout := range []string{"Jan 2, 2006 3:4:5 PM", time.RFC3339} {
+		if t, err := time.Parse(layout, string(d)); err == nil {
+			return t
 		}
 	}
 
-	return t
+	return time.Unix(0, 0)
 }
 
 type DependencyParameters struct {
@@ -332,6 +335,8 @@ type ManagerCredentials struct {
 type PluginRegisterMessage struct {
 	Type   string `json:"type"`
 	Action string `json:"action"`
+	// The handler version and the supported methods of the plugin, a plugin has no endpoint to carry them
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 type VnfmRegisterMessage struct {
@@ -0,0 +1,60 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateTime(t *testing.T) {
+	want := time.Date(2017, time.March, 7, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		date Date
+		want time.Time
+	}{
+		{"padded", "Mar 7, 2017 3:04:05 PM", want},
+		{"unpadded", "Mar 7, 2017 3:4:5 PM", want},
+		{"padded day and hour", "Mar 07, 2017 03:04:05 PM", want},
+		{"morning", "Mar 7, 2017 9:4:5 AM", time.Date(2017, time.March, 7, 9, 4, 5, 0, time.UTC)},
+		{"RFC 3339", "2017-03-07T15:04:05Z", want},
+		{"empty", "", time.Unix(0, 0)},
+		{"invalid", "yesterday", time.Unix(0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.date.Time(); !got.Equal(tt.want) {
+				t.Errorf("Date(%q).Time() = %v, want %v", tt.date, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDateWithTime(t *testing.T) {
+	tm := time.Date(2017, time.March, 7, 15, 4, 5, 0, time.UTC)
+
+	d := NewDateWithTime(tm)
+	if d != "Mar 7, 2017 3:04:05 PM" {
+		t.Errorf("NewDateWithTime(%v) = %q", tm, d)
+	}
+	if got := d.Time(); !got.Equal(tm) {
+		t.Errorf("Date(%q).Time() = %v, want %v", d, got, tm)
+	}
+}
@@ -0,0 +1,265 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// NetworkServiceDescriptor as described in ETSI GS NFV-MAN 001 V1.1.1 (2014-12)
+type NetworkServiceDescriptor struct {
+	ID                        string                               `json:"id,omitempty"`
+	HbVersion                 int                                  `json:"hbVersion,omitempty"`
+	ProjectID                 string                               `json:"projectId"`
+	Shared                    bool                                 `json:"shared,omitempty"`
+	Metadata                  map[string]string                    `json:"metadata,omitempty"`
+	Name                      string                               `json:"name"`
+	Vendor                    string                               `json:"vendor"`
+	Version                   string                               `json:"version"`
+	VNFFGDs                   []*VNFForwardingGraphDescriptor      `json:"vnffgd"`
+	VLDs                      []*VirtualLinkDescriptor             `json:"vld"`
+	MonitoringParameters      []string                             `json:"monitoring_parameter"`
+	ServiceDeploymentFlavours []*DeploymentFlavour                 `json:"service_deployment_flavour"`
+	AutoScalePolicies         []*AutoScalePolicy                   `json:"auto_scale_policy"`
+	ConnectionPoints          []*ConnectionPoint                   `json:"connection_point"`
+	VNFDs                     []*VirtualNetworkFunctionDescriptor  `json:"vnfd"`
+	PNFDs                     []*PhysicalNetworkFunctionDescriptor `json:"pnfd"`
+	VNFDependencies           []*VNFDependency                     `json:"vnf_dependency"`
+	NSDSecurity               *Security                            `json:"nsd_security,omitempty"`
+	Enabled                   bool                                 `json:"enabled"`
+	CreatedAt                 string                               `json:"createdAt,omitempty"`
+}
+
+// NewNSD returns a new, enabled NSD composed of the given VNFDs.
+func NewNSD(name, vendor, version string, vnfds ...*VirtualNetworkFunctionDescriptor) *NetworkServiceDescriptor {
+	return &NetworkServiceDescriptor{
+		Name:                      name,
+		Vendor:                    vendor,
+		Version:                   version,
+		VNFFGDs:                   []*VNFForwardingGraphDescriptor{},
+		VLDs:                      []*VirtualLinkDescriptor{},
+		MonitoringParameters:      []string{},
+		ServiceDeploymentFlavours: []*DeploymentFlavour{},
+		AutoScalePolicies:         []*AutoScalePolicy{},
+		ConnectionPoints:          []*ConnectionPoint{},
+		VNFDs:                     append([]*VirtualNetworkFunctionDescriptor{}, vnfds...),
+		PNFDs:                     []*PhysicalNetworkFunctionDescriptor{},
+		VNFDependencies:           []*VNFDependency{},
+		Enabled:                   true,
+	}
+}
+
+// NewVNFDependency returns a dependency of target on the given parameters of source.
+func NewVNFDependency(source, target *VirtualNetworkFunctionDescriptor, parameters ...string) *VNFDependency {
+	return &VNFDependency{
+		Source:     source,
+		Target:     target,
+		Parameters: append([]string{}, parameters...),
+	}
+}
+
+// AddVLD adds a VirtualLinkDescriptor with the given name to the NSD, and returns it.
+func (nsd *NetworkServiceDescriptor) AddVLD(name string) *VirtualLinkDescriptor {
+	vld := &VirtualLinkDescriptor{
+		Name:             name,
+		QoS:              []string{},
+		TestAccess:       []string{},
+		ConnectivityType: []string{},
+		Connections:      []string{},
+	}
+	nsd.VLDs = append(nsd.VLDs, vld)
+
+	return vld
+}
+
+// AddDependency adds to the NSD a dependency of target on the given parameters of source, and returns it.
+func (nsd *NetworkServiceDescriptor) AddDependency(source, target *VirtualNetworkFunctionDescriptor, parameters ...string) *VNFDependency {
+	dep := NewVNFDependency(source, target, parameters...)
+	nsd.VNFDependencies = append(nsd.VNFDependencies, dep)
+
+	return dep
+}
+
+// FindVNFD returns the VNFD of the NSD with the given name, or nil if there is none.
+func (nsd *NetworkServiceDescriptor) FindVNFD(name string) *VirtualNetworkFunctionDescriptor {
+	for _, vnfd := range nsd.VNFDs {
+		if vnfd != nil && vnfd.Name == name {
+			return vnfd
+		}
+	}
+
+	return nil
+}
+
+func (nsd *NetworkServiceDescriptor) String() string {
+	b, e := json.MarshalIndent(nsd, "", " ")
+	if e != nil {
+		return fmt.Sprint(*nsd)
+	}
+
+	return string(b)
+}
+
+// NewNSR returns a new NSR for the given NSD, in the NULL state and without VNFRs:
+// these are added as the VNFMs instantiate them.
+func NewNSR(nsd *NetworkServiceDescriptor) (*NetworkServiceRecord, error) {
+	autoScalePolicies := make([]*AutoScalePolicy, len(nsd.AutoScalePolicies))
+	for i, asp := range nsd.AutoScalePolicies {
+		autoScalePolicies[i] = copyAutoScalePolicy(asp)
+	}
+
+	connectionPoints := make([]*ConnectionPoint, len(nsd.ConnectionPoints))
+	for i, connectionPoint := range nsd.ConnectionPoints {
+		connectionPoints[i] = new(ConnectionPoint)
+		*connectionPoints[i] = *connectionPoint
+	}
+
+	monitoringParameters := make([]string, len(nsd.MonitoringParameters))
+	copy(monitoringParameters, nsd.MonitoringParameters)
+
+	vlrs := make([]*VirtualLinkRecord, len(nsd.VLDs))
+	for i, vld := range nsd.VLDs {
+		vlrs[i] = makeVLRFromVLD(vld, nsd.ID)
+	}
+
+	dependencies, err := nsd.makeRecordDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NetworkServiceRecord{
+		Name:                  nsd.Name,
+		AutoScalePolicy:       autoScalePolicies,
+		ConnectionPoint:       connectionPoints,
+		CreatedAt:             string(NewDate()),
+		DescriptorReference:   nsd.ID,
+		KeyNames:              []string{},
+		LifecycleEventHistory: LifecycleEvents{},
+		LifecycleEvents:       LifecycleEvents{},
+		MonitoringParameter:   monitoringParameters,
+		PNFR:                  []*PhysicalNetworkFunctionRecord{},
+		ProjectID:             nsd.ProjectID,
+		Status:                StatusNull,
+		Vendor:                nsd.Vendor,
+		Version:               nsd.Version,
+		VLR:                   vlrs,
+		VNFDependency:         dependencies,
+		VNFFGR:                []*VNFForwardingGraphRecord{},
+		VNFR:                  []*VirtualNetworkFunctionRecord{},
+	}, nil
+}
+
+// copyAutoScalePolicy returns a deep copy of the policy of a descriptor without the IDs, for a new record.
+func copyAutoScalePolicy(asp *AutoScalePolicy) *AutoScalePolicy {
+	newAsp := asp.DeepCopy()
+	if newAsp == nil {
+		return nil
+	}
+	newAsp.ID = ""
+	for _, action := range newAsp.Actions {
+		if action != nil {
+			action.ID = ""
+		}
+	}
+	for _, alarm := range newAsp.Alarms {
+		if alarm != nil {
+			alarm.ID = ""
+		}
+	}
+
+	return newAsp
+}
+
+func makeVLRFromVLD(vld *VirtualLinkDescriptor, nsdID string) *VirtualLinkRecord {
+	qos := make([]string, len(vld.QoS))
+	copy(qos, vld.QoS)
+
+	testAccess := make([]string, len(vld.TestAccess))
+	copy(testAccess, vld.TestAccess)
+
+	connectivityType := make([]string, len(vld.ConnectivityType))
+	copy(connectivityType, vld.ConnectivityType)
+
+	connections := make([]string, len(vld.Connections))
+	copy(connections, vld.Connections)
+
+	return &VirtualLinkRecord{
+		Name:                  vld.Name,
+		ConnectivityType:      connectivityType,
+		Connection:            connections,
+		DescriptorReference:   vld.ID,
+		ExtID:                 vld.ExtID,
+		LeafRequirement:       vld.LeafRequirement,
+		LifecycleEventHistory: LifecycleEvents{},
+		NumberOfEndpoints:     vld.NumberOfEndpoints,
+		ParentNs:              nsdID,
+		QoS:                   qos,
+		RootRequirement:       vld.RootRequirement,
+		Status:                LinkDown,
+		TestAccess:            testAccess,
+		Vendor:                vld.Vendor,
+		Version:               vld.DescriptorVersion,
+	}
+}
+
+// makeRecordDependencies merges the dependencies of the NSD by target VNF, as the NFVO does:
+// the record of a target holds the parameters required from each of its sources, by source type.
+func (nsd *NetworkServiceDescriptor) makeRecordDependencies() ([]*VNFRecordDependency, error) {
+	records := []*VNFRecordDependency{}
+	byTarget := make(map[string]*VNFRecordDependency)
+
+	for _, dep := range nsd.VNFDependencies {
+		if dep.Source == nil || dep.Source.Name == "" || dep.Target == nil || dep.Target.Name == "" {
+			return nil, errors.New("VNF dependency without source or target")
+		}
+
+		record, ok := byTarget[dep.Target.Name]
+		if !ok {
+			record = &VNFRecordDependency{
+				Target:         dep.Target.Name,
+				Parameters:     make(map[string]*DependencyParameters),
+				VNFCParameters: make(map[string]*VNFCDependencyParameters),
+				IDType:         make(map[string]string),
+			}
+			byTarget[dep.Target.Name] = record
+			records = append(records, record)
+		}
+
+		// the NSDs usually reference the VNFDs by name only
+		sourceType := dep.Source.Type
+		if vnfd := nsd.FindVNFD(dep.Source.Name); sourceType == "" && vnfd != nil {
+			sourceType = vnfd.Type
+		}
+		if sourceType == "" {
+			return nil, errors.Errorf("unknown type of the VNF %s", dep.Source.Name)
+		}
+
+		params, ok := record.Parameters[sourceType]
+		if !ok {
+			params = &DependencyParameters{Parameters: make(map[string]string)}
+			record.Parameters[sourceType] = params
+		}
+		for _, key := range dep.Parameters {
+			params.Parameters[key] = ""
+		}
+	}
+
+	return records, nil
+}
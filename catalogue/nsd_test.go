@@ -0,0 +1,332 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func loadNSD(t *testing.T) *NetworkServiceDescriptor {
+	nsd := new(NetworkServiceDescriptor)
+	if err := json.Unmarshal(readFixture(t, "nsd.json"), nsd); err != nil {
+		t.Fatal(err)
+	}
+
+	return nsd
+}
+
+// jsonContains reports the values of want missing or different in got, both decoded JSON documents:
+// the structs also marshal the fields the NFVO leaves out.
+func jsonContains(path string, want, got interface{}) []string {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %v, want an object", path, got)}
+		}
+		diffs := []string{}
+		for key, value := range w {
+			diffs = append(diffs, jsonContains(path+"."+key, value, g[key])...)
+		}
+		return diffs
+
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return []string{fmt.Sprintf("%s: got %v, want %d elements", path, got, len(w))}
+		}
+		diffs := []string{}
+		for i := range w {
+			diffs = append(diffs, jsonContains(fmt.Sprintf("%s[%d]", path, i), w[i], g[i])...)
+		}
+		return diffs
+
+	default:
+		if !reflect.DeepEqual(want, got) {
+			return []string{fmt.Sprintf("%s: got %v, want %v", path, got, want)}
+		}
+		return nil
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		fixture string
+		value   func() interface{}
+	}{
+		{"nsd.json", func() interface{} { return new(NetworkServiceDescriptor) }},
+		{"nsr.json", func() interface{} { return new(NetworkServiceRecord) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			fixture := readFixture(t, tt.fixture)
+
+			v := tt.value()
+			if err := json.Unmarshal(fixture, v); err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			again := tt.value()
+			if err := json.Unmarshal(b, again); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, again) {
+				t.Errorf("got %s after a round trip, want %s", again, v)
+			}
+
+			var want, got interface{}
+			if err := json.Unmarshal(fixture, &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			for _, diff := range jsonContains("", want, got) {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewNSD(t *testing.T) {
+	server := &VirtualNetworkFunctionDescriptor{Name: "iperf-server", Type: "server"}
+	client := &VirtualNetworkFunctionDescriptor{Name: "iperf-client", Type: "client"}
+	vnfds := []*VirtualNetworkFunctionDescriptor{server, client}
+
+	nsd := NewNSD("NSD iperf", "FOKUS", "1.0", vnfds...)
+	vnfds[0] = nil
+
+	if nsd.Name != "NSD iperf" || nsd.Vendor != "FOKUS" || nsd.Version != "1.0" || !nsd.Enabled {
+		t.Errorf("got %s", nsd)
+	}
+	if !reflect.DeepEqual(nsd.VNFDs, []*VirtualNetworkFunctionDescriptor{server, client}) {
+		t.Errorf("got the VNFDs %v, want the ones given", nsd.VNFDs)
+	}
+	if got := nsd.FindVNFD("iperf-client"); got != client {
+		t.Errorf("FindVNFD(iperf-client) = %v", got)
+	}
+	if got := nsd.FindVNFD("unknown"); got != nil {
+		t.Errorf("FindVNFD(unknown) = %v, want nil", got)
+	}
+
+	// the NFVO rejects the null lists
+	b, err := json.Marshal(nsd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"vnffgd", "vld", "monitoring_parameter", "service_deployment_flavour",
+		"auto_scale_policy", "connection_point", "pnfd", "vnf_dependency"} {
+		if list, ok := fields[key].([]interface{}); !ok || len(list) != 0 {
+			t.Errorf("got %s = %v, want []", key, fields[key])
+		}
+	}
+}
+
+func TestNewNSR(t *testing.T) {
+	nsd := loadNSD(t)
+
+	nsr, err := NewNSR(nsd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nsr.Name != nsd.Name || nsr.DescriptorReference != nsd.ID || nsr.ProjectID != nsd.ProjectID {
+		t.Errorf("got the NSR %s of the NSD %s", nsr.Name, nsr.DescriptorReference)
+	}
+	if nsr.Status != StatusNull {
+		t.Errorf("got the status %s, want %s", nsr.Status, StatusNull)
+	}
+	if len(nsr.VNFR) != 0 {
+		t.Errorf("got %d VNFRs, want none", len(nsr.VNFR))
+	}
+	if len(nsr.VLR) != 1 {
+		t.Fatalf("got %d VLRs, want 1", len(nsr.VLR))
+	}
+	vlr := nsr.VLR[0]
+	if vlr.Name != "private" || vlr.DescriptorReference != nsd.VLDs[0].ID || vlr.ParentNs != nsd.ID || vlr.Status != LinkDown {
+		t.Errorf("got the VLR %+v", vlr)
+	}
+	if !reflect.DeepEqual(vlr.QoS, nsd.VLDs[0].QoS) {
+		t.Errorf("got the QoS %v, want %v", vlr.QoS, nsd.VLDs[0].QoS)
+	}
+
+	want := []*VNFRecordDependency{{
+		Target: "iperf-client",
+		Parameters: map[string]*DependencyParameters{
+			"server": {Parameters: map[string]string{"private": "", "hostname": ""}},
+		},
+		VNFCParameters: map[string]*VNFCDependencyParameters{},
+		IDType:         map[string]string{},
+	}}
+	if !reflect.DeepEqual(nsr.VNFDependency, want) {
+		b, _ := json.Marshal(nsr.VNFDependency)
+		t.Errorf("got the dependencies %s", b)
+	}
+}
+
+func TestMakeRecordDependencies(t *testing.T) {
+	server := &VirtualNetworkFunctionDescriptor{Name: "iperf-server", Type: "server"}
+	client := &VirtualNetworkFunctionDescriptor{Name: "iperf-client", Type: "client"}
+	monitor := &VirtualNetworkFunctionDescriptor{Name: "monitor", Type: "monitor"}
+	byName := func(name string) *VirtualNetworkFunctionDescriptor {
+		return &VirtualNetworkFunctionDescriptor{Name: name}
+	}
+
+	tests := []struct {
+		name string
+		deps []*VNFDependency
+		// the parameters of each target, by source type
+		want map[string]map[string][]string
+		err  bool
+	}{
+		{
+			name: "none",
+			deps: []*VNFDependency{},
+			want: map[string]map[string][]string{},
+		},
+		{
+			name: "source by name",
+			deps: []*VNFDependency{NewVNFDependency(byName("iperf-server"), byName("iperf-client"), "private")},
+			want: map[string]map[string][]string{"iperf-client": {"server": {"private"}}},
+		},
+		{
+			name: "merged by target",
+			deps: []*VNFDependency{
+				NewVNFDependency(server, client, "private"),
+				NewVNFDependency(server, client, "hostname"),
+				NewVNFDependency(monitor, client, "url"),
+				NewVNFDependency(server, monitor, "private"),
+			},
+			want: map[string]map[string][]string{
+				"iperf-client": {"server": {"hostname", "private"}, "monitor": {"url"}},
+				"monitor":      {"server": {"private"}},
+			},
+		},
+		{
+			name: "without source",
+			deps: []*VNFDependency{NewVNFDependency(nil, client, "private")},
+			err:  true,
+		},
+		{
+			name: "without target name",
+			deps: []*VNFDependency{NewVNFDependency(server, byName(""), "private")},
+			err:  true,
+		},
+		{
+			name: "unknown source type",
+			deps: []*VNFDependency{NewVNFDependency(byName("unknown"), client, "private")},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsd := NewNSD("NSD iperf", "FOKUS", "1.0", server, client, monitor)
+			nsd.VNFDependencies = tt.deps
+
+			records, err := nsd.makeRecordDependencies()
+			if tt.err {
+				if err == nil {
+					t.Errorf("got no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(map[string]map[string][]string)
+			for _, record := range records {
+				if _, ok := got[record.Target]; ok {
+					t.Errorf("got %s twice", record.Target)
+				}
+				if len(record.VNFCParameters) != 0 || len(record.IDType) != 0 {
+					t.Errorf("got VNFC parameters or VNFR IDs for %s before the instantiation", record.Target)
+				}
+				got[record.Target] = make(map[string][]string)
+				for typ, params := range record.Parameters {
+					for _, key := range sortedParameterKeys(params.Parameters) {
+						if params.Parameters[key] != "" {
+							t.Errorf("got %s.%s = %q, want no value", typ, key, params.Parameters[key])
+						}
+						got[record.Target][typ] = append(got[record.Target][typ], key)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyAutoScalePolicy(t *testing.T) {
+	asp := &AutoScalePolicy{
+		ID:        "a5000000-0000-4000-8000-000000000001",
+		ProjectID: "project",
+		Name:      "scale-out",
+		Metadata:  map[string]string{"owner": "ops"},
+		Threshold: 100,
+		Alarms:    []*ScalingAlarm{{ID: "alarm", Metric: "cpu", Threshold: 80}, nil},
+		Actions:   []*ScalingAction{{ID: "action", Type: "SCALE_OUT", Value: "1"}, nil},
+	}
+
+	got := copyAutoScalePolicy(asp)
+	want := &AutoScalePolicy{
+		ProjectID: "project",
+		Name:      "scale-out",
+		Metadata:  map[string]string{"owner": "ops"},
+		Threshold: 100,
+		Alarms:    []*ScalingAlarm{{Metric: "cpu", Threshold: 80}, nil},
+		Actions:   []*ScalingAction{{Type: "SCALE_OUT", Value: "1"}, nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		b, _ := json.Marshal(got)
+		t.Errorf("got %s", b)
+	}
+
+	got.Metadata["owner"] = "changed"
+	got.Actions[0].Value = "2"
+	if asp.Metadata["owner"] != "ops" || asp.Actions[0].Value != "1" || asp.ID == "" || asp.Alarms[0].ID == "" {
+		t.Errorf("the copy shares the policy %+v", asp)
+	}
+	if copyAutoScalePolicy(nil) != nil {
+		t.Error("got a copy of nil")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// schema-gen writes the JSON Schemas of the descriptors, records and messages of the catalogue.
+// It is run by go generate in catalogue/schema, see schema.go.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/openbaton/go-openbaton/catalogue/messages"
+	"github.com/openbaton/go-openbaton/catalogue/schema"
+)
+
+// The messages of the NFVO and the VNFMs, with the actions they are decoded for,
+// see the unmarshalling of the messages in catalogue/messages
+var messageTypes = []struct {
+	msg     interface{}
+	actions []catalogue.Action
+}{
+	{messages.OrAllocateResources{}, []catalogue.Action{catalogue.ActionAllocateResources}},
+	{messages.OrError{}, []catalogue.Action{catalogue.ActionError}},
+	{messages.OrGeneric{}, []catalogue.Action{
+		catalogue.ActionAllocateResources,
+		catalogue.ActionReleaseResources,
+		catalogue.ActionModify,
+		catalogue.ActionUpdateVNFR,
+		catalogue.ActionScaled,
+		catalogue.ActionReleaseResourcesFinish,
+		catalogue.ActionInstantiateFinish,
+		catalogue.ActionConfigure,
+		catalogue.ActionResume,
+	}},
+	{messages.OrGrantLifecycleOperation{}, []catalogue.Action{catalogue.ActionGrantOperation}},
+	{messages.OrHealVNFRequest{}, []catalogue.Action{catalogue.ActionHeal}},
+	{messages.OrInstantiate{}, []catalogue.Action{catalogue.ActionInstantiate}},
+	{messages.OrScaling{}, []catalogue.Action{catalogue.ActionScaleIn, catalogue.ActionScaleOut, catalogue.ActionScaling}},
+	{messages.OrStartStop{}, []catalogue.Action{catalogue.ActionStart, catalogue.ActionStop}},
+	{messages.OrUpdate{}, []catalogue.Action{catalogue.ActionUpdate}},
+	{messages.VNFMAllocateResources{}, []catalogue.Action{catalogue.ActionAllocateResources}},
+	{messages.VNFMError{}, []catalogue.Action{catalogue.ActionError}},
+	{messages.VNFMGeneric{}, []catalogue.Action{
+		catalogue.ActionGrantOperation,
+		catalogue.ActionScaleIn,
+		catalogue.ActionScaleOut,
+		catalogue.ActionReleaseResources,
+		catalogue.ActionModify,
+		catalogue.ActionUpdateVNFR,
+		catalogue.ActionUpdate,
+		catalogue.ActionReleaseResourcesFinish,
+		catalogue.ActionInstantiateFinish,
+		catalogue.ActionConfigure,
+		catalogue.ActionResume,
+	}},
+	{messages.VNFMGrantLifecycleOperation{}, []catalogue.Action{catalogue.ActionGrantOperation}},
+	{messages.VNFMHealed{}, []catalogue.Action{catalogue.ActionHeal}},
+	{messages.VNFMInstantiate{}, []catalogue.Action{catalogue.ActionInstantiate}},
+	{messages.VNFMScaled{}, []catalogue.Action{catalogue.ActionScaled}},
+	{messages.VNFMScaling{}, []catalogue.Action{catalogue.ActionScaling}},
+	{messages.VNFMStartStop{}, []catalogue.Action{catalogue.ActionStart, catalogue.ActionStop}},
+}
+
+// The properties the documents must have, by definition
+var required = map[string][]string{
+	"NetworkServiceDescriptor":         {"name", "vendor", "version", "vnfd"},
+	"VirtualNetworkFunctionDescriptor": {"name", "vendor", "version", "type", "endpoint", "vdu", "deployment_flavour"},
+	"VirtualNetworkFunctionRecord":     {"name", "vendor", "version", "type", "endpoint", "descriptor_reference", "status", "vdu"},
+}
+
+func main() {
+	dir := flag.String("d", ".", "the directory the schemas are written to")
+	flag.Parse()
+
+	documents := map[string]*schema.Schema{"messages.schema.json": messagesSchema()}
+	for _, d := range []struct {
+		name, title string
+		v           interface{}
+	}{
+		{"vnfd", "Virtual Network Function Descriptor", catalogue.VirtualNetworkFunctionDescriptor{}},
+		{"nsd", "Network Service Descriptor", catalogue.NetworkServiceDescriptor{}},
+		{"vnfr", "Virtual Network Function Record", catalogue.VirtualNetworkFunctionRecord{}},
+		{"nsr", "Network Service Record", catalogue.NetworkServiceRecord{}},
+	} {
+		// the strict variant rejects the properties unknown to the catalogue, e.g. misspelled ones
+		documents[d.name+".schema.json"] = schema.For(d.name+".schema.json", d.title, d.v)
+		documents[d.name+".strict.schema.json"] = schema.StrictFor(d.name+".strict.schema.json", d.title, d.v)
+	}
+
+	for name, doc := range documents {
+		for def, props := range required {
+			if s, ok := doc.Definitions[def]; ok {
+				s.Require(props...)
+			}
+		}
+
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(*dir, name), append(data, '\n'), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// messagesSchema validates any message, and each message through its definition, e.g.
+// "messages.schema.json#/definitions/OrInstantiate". The action of the message is a property of its body,
+// limited to the actions the message is decoded for.
+func messagesSchema() *schema.Schema {
+	r := schema.NewReflector()
+	doc := &schema.Schema{
+		Schema: schema.Draft,
+		ID:     "messages.schema.json",
+		Title:  "Messages exchanged by the NFVO and the VNFMs",
+	}
+
+	for _, mt := range messageTypes {
+		ref := r.Reflect(mt.msg)
+		doc.AnyOf = append(doc.AnyOf, ref)
+
+		action := &schema.Schema{Type: "string"}
+		for _, a := range mt.actions {
+			action.Enum = append(action.Enum, a)
+		}
+		def := r.Definitions()[strings.TrimPrefix(ref.Ref, "#/definitions/")]
+		def.Properties["action"] = action
+		def.Require("action")
+	}
+	doc.Definitions = r.Definitions()
+
+	return doc
+}
@@ -0,0 +1,244 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package schema generates the JSON Schemas of the catalogue types from their JSON encoding.
+// The schemas of the descriptors, records and messages are generated in this directory, run go generate
+// after changing the catalogue types.
+package schema
+
+//go:generate go run ./internal/schema-gen
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Draft is the JSON Schema version of the generated schemas.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema, limited to the keywords used for the catalogue types.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// A type name or a list of type names
+	Type                 interface{}        `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+
+	// Makes the schema the boolean schema true or false if not nil, ignoring the keywords
+	Bool *bool `json:"-"`
+}
+
+// False returns the schema accepting no value, e.g. to forbid additional properties.
+func False() *Schema {
+	b := false
+	return &Schema{Bool: &b}
+}
+
+// MarshalJSON encodes the boolean schemas as true or false.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.Bool != nil {
+		return json.Marshal(*s.Bool)
+	}
+
+	type keywords Schema
+	return json.Marshal((*keywords)(s))
+}
+
+// Reflector builds the schemas of Go types, sharing the definitions of the struct types.
+type Reflector struct {
+	// Strict forbids the properties not declared by the struct types, set it before reflecting
+	Strict bool
+
+	definitions map[string]*Schema
+	names       map[reflect.Type]string
+}
+
+// NewReflector returns a Reflector without definitions.
+func NewReflector() *Reflector {
+	return &Reflector{
+		definitions: make(map[string]*Schema),
+		names:       make(map[reflect.Type]string),
+	}
+}
+
+// Reflect returns the schema of the type of v, referring to the definitions of the struct types.
+func (r *Reflector) Reflect(v interface{}) *Schema {
+	return r.reflect(reflect.TypeOf(v))
+}
+
+// Definitions returns the definitions of the struct types reflected so far, by name.
+func (r *Reflector) Definitions() map[string]*Schema {
+	return r.definitions
+}
+
+// Document returns a schema document validating the type of v, with the definitions of all the types
+// reflected by r.
+func (r *Reflector) Document(id, title string, v interface{}) *Schema {
+	// the keywords next to a $ref are ignored
+	return &Schema{
+		Schema:      Draft,
+		ID:          id,
+		Title:       title,
+		AllOf:       []*Schema{r.Reflect(v)},
+		Definitions: r.definitions,
+	}
+}
+
+// For returns the schema document of the type of v.
+func For(id, title string, v interface{}) *Schema {
+	return NewReflector().Document(id, title, v)
+}
+
+// StrictFor returns the schema document of the type of v, rejecting the properties unknown to the catalogue.
+func StrictFor(id, title string, v interface{}) *Schema {
+	r := NewReflector()
+	r.Strict = true
+	return r.Document(id, title, v)
+}
+
+func (r *Reflector) reflect(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(r.reflect(t.Elem()))
+
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+
+	case reflect.String:
+		return &Schema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoded in base64
+			return &Schema{Type: "string"}
+		}
+		return &Schema{Type: []string{"array", "null"}, Items: r.reflect(t.Elem())}
+
+	case reflect.Map:
+		return &Schema{Type: []string{"object", "null"}, AdditionalProperties: r.reflect(t.Elem())}
+
+	case reflect.Struct:
+		return &Schema{Ref: "#/definitions/" + r.define(t)}
+
+	default:
+		// interfaces accept any value
+		return &Schema{}
+	}
+}
+
+// nullable allows null in place of the value, as the JSON encoding of a nil pointer.
+func nullable(s *Schema) *Schema {
+	switch t := s.Type.(type) {
+	case string:
+		s.Type = []string{t, "null"}
+		return s
+	case []string:
+		return s
+	}
+	if s.Ref == "" {
+		return s
+	}
+
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+// define adds the definition of the struct type t if it is not there yet, and returns its name.
+func (r *Reflector) define(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if name == "" {
+		name = "Anonymous"
+	}
+	if _, taken := r.definitions[name]; taken {
+		// the same name in another package
+		pkg := pathBase(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	r.names[t] = name
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	if r.Strict {
+		s.AdditionalProperties = False()
+	}
+	r.definitions[name] = s
+	r.addProperties(s, t, false)
+
+	return name
+}
+
+// addProperties adds the properties of the fields of t, which do not replace the ones of the outer
+// struct if t is embedded.
+func (r *Reflector) addProperties(s *Schema, t reflect.Type, embedded bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			r.addProperties(s, ft, true)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := s.Properties[name]; ok && embedded {
+			continue
+		}
+		s.Properties[name] = r.reflect(f.Type)
+	}
+}
+
+// Require marks the properties of s as required, sorted.
+func (s *Schema) Require(names ...string) {
+	s.Required = append(s.Required, names...)
+	sort.Strings(s.Required)
+}
+
+func pathBase(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
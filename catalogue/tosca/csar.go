@@ -0,0 +1,296 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tosca
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// The layout of a CSAR archive
+const (
+	MetaFile         = "TOSCA-Metadata/TOSCA.meta"
+	PackageMetaFile  = "TOSCA-Metadata/Metadata.yaml"
+	DefinitionsDir   = "Definitions/"
+	ScriptsDir       = "Scripts/"
+	EntryDefinitions = "Entry-Definitions"
+)
+
+// CSAR is a Cloud Service ARchive: a service template with the scripts of its VNFs.
+type CSAR struct {
+	// The entries of TOSCA-Metadata/TOSCA.meta
+	Meta map[string]string
+
+	Template *ServiceTemplate
+	NSD      *catalogue.NetworkServiceDescriptor
+
+	// The packages of the VNFs, in the order of NSD.VNFDs
+	Packages []*catalogue.VNFPackage
+}
+
+// Package returns the package of the VNFD with the given name, or nil if there is none.
+func (c *CSAR) Package(name string) *catalogue.VNFPackage {
+	for _, pkg := range c.Packages {
+		if pkg.Name == name {
+			return pkg
+		}
+	}
+
+	return nil
+}
+
+// The optional TOSCA-Metadata/Metadata.yaml, applying to the packages of every VNF
+type packageMetadata struct {
+	NFVOVersion string   `yaml:"nfvo_version"`
+	VIMTypes    []string `yaml:"vim_types"`
+	ScriptsLink string   `yaml:"scripts-link"`
+	Image       struct {
+		Link string `yaml:"link"`
+	} `yaml:"image"`
+}
+
+// OpenCSAR reads the CSAR archive in the given file.
+func OpenCSAR(name string) (*CSAR, error) {
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	c, err := readCSAR(&r.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	return c, nil
+}
+
+// ReadCSAR reads a CSAR archive of the given size.
+func ReadCSAR(r io.ReaderAt, size int64) (*CSAR, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return readCSAR(zr)
+}
+
+func readCSAR(zr *zip.Reader) (*CSAR, error) {
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() {
+			files[path.Clean(f.Name)] = f
+		}
+	}
+
+	c := &CSAR{Meta: make(map[string]string)}
+
+	if f, ok := files[MetaFile]; ok {
+		data, err := readFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if c.Meta, err = parseMeta(data); err != nil {
+			return nil, errors.Wrap(err, MetaFile)
+		}
+	}
+
+	entry, err := entryDefinitions(c.Meta, files)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readFile(files[entry])
+	if err != nil {
+		return nil, err
+	}
+	if c.Template, err = Parse(data); err != nil {
+		return nil, errors.Wrap(err, entry)
+	}
+	if c.NSD, err = c.Template.NSD(); err != nil {
+		return nil, errors.Wrap(err, entry)
+	}
+
+	var meta packageMetadata
+	if f, ok := files[PackageMetaFile]; ok {
+		data, err := readFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &meta); err != nil {
+			return nil, errors.Wrap(err, PackageMetaFile)
+		}
+	}
+
+	for _, vnfd := range c.NSD.VNFDs {
+		pkg := &catalogue.VNFPackage{
+			Name:        vnfd.Name,
+			NFVOVersion: meta.NFVOVersion,
+			VIMTypes:    meta.VIMTypes,
+			ImageLink:   meta.Image.Link,
+			ScriptsLink: meta.ScriptsLink,
+			Scripts:     []*catalogue.Script{},
+		}
+		if pkg.VIMTypes == nil {
+			pkg.VIMTypes = []string{}
+		}
+		c.Packages = append(c.Packages, pkg)
+	}
+
+	if err := c.addScripts(files); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// entryDefinitions returns the service template of the archive: the Entry-Definitions of the metadata or else
+// the only YAML file in Definitions.
+func entryDefinitions(meta map[string]string, files map[string]*zip.File) (string, error) {
+	if entry, ok := meta[EntryDefinitions]; ok {
+		entry = path.Clean(entry)
+		if _, ok := files[entry]; !ok {
+			return "", errors.Errorf("missing %s %s", EntryDefinitions, entry)
+		}
+		return entry, nil
+	}
+
+	var candidates []string
+	for name := range files {
+		ext := path.Ext(name)
+		if strings.HasPrefix(name, DefinitionsDir) && (ext == ".yaml" || ext == ".yml") {
+			candidates = append(candidates, name)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", errors.New("no service template in " + DefinitionsDir)
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+		return "", errors.Errorf("%s is required to choose among %s", EntryDefinitions, strings.Join(candidates, ", "))
+	}
+}
+
+// addScripts adds the scripts in Scripts/<vnf>/ to the package of the VNF with that name or type, and the ones
+// directly in Scripts/ to every package. The scripts the lifecycle events refer to must be in the package, unless
+// it has a scripts link.
+func (c *CSAR) addScripts(files map[string]*zip.File) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if strings.HasPrefix(name, ScriptsDir) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		payload, err := readFile(files[name])
+		if err != nil {
+			return err
+		}
+
+		rel := strings.TrimPrefix(name, ScriptsDir)
+		dir, file := "", rel
+		if i := strings.Index(rel, "/"); i >= 0 {
+			dir, file = rel[:i], rel[i+1:]
+		}
+
+		found := false
+		for i, vnfd := range c.NSD.VNFDs {
+			if dir == "" || dir == vnfd.Name || dir == vnfd.Type {
+				pkg := c.Packages[i]
+				pkg.Scripts = append(pkg.Scripts, &catalogue.Script{Name: file, Payload: payload})
+				found = true
+			}
+		}
+		if !found {
+			return errors.Errorf("the scripts in %s%s do not belong to any VNF", ScriptsDir, dir)
+		}
+	}
+
+	for i, vnfd := range c.NSD.VNFDs {
+		pkg := c.Packages[i]
+		if pkg.ScriptsLink != "" {
+			continue
+		}
+
+		scripts := make(map[string]bool)
+		for _, script := range pkg.Scripts {
+			scripts[script.Name] = true
+		}
+		for _, le := range vnfd.LifecycleEvents {
+			if le == nil {
+				continue
+			}
+			for _, script := range le.LifecycleEvents {
+				if !scripts[script] {
+					return errors.Errorf("the %s event of %s refers to the missing script %s", le.Event, vnfd.Name, script)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseMeta parses the "Key: Value" lines of TOSCA.meta.
+func parseMeta(data []byte) (map[string]string, error) {
+	meta := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		i := strings.Index(line, ":")
+		if i < 0 {
+			return nil, errors.Errorf("invalid line %q", line)
+		}
+		meta[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+
+	return meta, scanner.Err()
+}
+
+func readFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.Wrap(err, f.Name)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, f.Name)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,224 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tosca
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// archive returns a zip archive with the given files.
+func archive(t *testing.T, files map[string]string) *bytes.Reader {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func scriptNames(c *CSAR, vnf string) []string {
+	names := []string{}
+	for _, script := range c.Package(vnf).Scripts {
+		names = append(names, script.Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func TestOpenCSAR(t *testing.T) {
+	c, err := OpenCSAR(filepath.Join("testdata", "iperf.csar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Meta[EntryDefinitions] != "Definitions/iperf.yaml" || c.NSD.Name != "NSD-iperf" {
+		t.Errorf("got the NSD %s from %s", c.NSD.Name, c.Meta[EntryDefinitions])
+	}
+	if len(c.Packages) != 2 || c.Packages[0].Name != c.NSD.VNFDs[0].Name || c.Packages[1].Name != c.NSD.VNFDs[1].Name {
+		t.Fatalf("got the packages %+v", c.Packages)
+	}
+	for _, pkg := range c.Packages {
+		if pkg.NFVOVersion != "5.2.0" || !reflect.DeepEqual(pkg.VIMTypes, []string{"openstack"}) || pkg.ImageLink == "" {
+			t.Errorf("got the package %+v", pkg)
+		}
+	}
+
+	// the shared script goes to every package, the others to the VNF with the name or type of their directory
+	if got, want := scriptNames(c, "iperf-server"), []string{"install-srv.sh", "install.sh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got the server scripts %q, want %q", got, want)
+	}
+	if got, want := scriptNames(c, "iperf-client"), []string{"install.sh", "server_start-clt.sh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got the client scripts %q, want %q", got, want)
+	}
+	if c.Package("iperf") != nil {
+		t.Error("got the package of an unknown VNF")
+	}
+}
+
+func TestReadCSARScripts(t *testing.T) {
+	template, err := os.ReadFile(filepath.Join("testdata", "iperf.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		scripts map[string]string
+		meta    string
+		err     bool
+	}{
+		{
+			name: "per VNF",
+			scripts: map[string]string{
+				"Scripts/iperf-server/install.sh":          "",
+				"Scripts/iperf-server/install-srv.sh":      "",
+				"Scripts/iperf-client/install.sh":          "",
+				"Scripts/iperf-client/server_start-clt.sh": "",
+			},
+		},
+		{
+			name: "unknown directory",
+			scripts: map[string]string{
+				"Scripts/install.sh":                  "",
+				"Scripts/iperf-server/install-srv.sh": "",
+				"Scripts/client/server_start-clt.sh":  "",
+				"Scripts/iperf/start.sh":              "",
+			},
+			err: true,
+		},
+		{
+			name: "missing script",
+			scripts: map[string]string{
+				"Scripts/install.sh":                  "",
+				"Scripts/iperf-server/install-srv.sh": "",
+			},
+			err: true,
+		},
+		{
+			name:    "scripts link",
+			scripts: map[string]string{},
+			meta:    "scripts-link: https://github.com/openbaton/vnf-scripts.git\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"Definitions/iperf.yaml": string(template)}
+			for name, content := range tt.scripts {
+				files[name] = content
+			}
+			if tt.meta != "" {
+				files[PackageMetaFile] = tt.meta
+			}
+
+			r := archive(t, files)
+			_, err := ReadCSAR(r, r.Size())
+			if tt.err != (err != nil) {
+				t.Errorf("got the error %v, want one: %t", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestEntryDefinitions(t *testing.T) {
+	tests := []struct {
+		name  string
+		meta  map[string]string
+		files []string
+		want  string
+		err   bool
+	}{
+		{
+			name:  "from the metadata",
+			meta:  map[string]string{EntryDefinitions: "./Definitions/iperf.yaml"},
+			files: []string{"Definitions/iperf.yaml", "Definitions/iperf-old.yaml"},
+			want:  "Definitions/iperf.yaml",
+		},
+		{
+			name:  "missing entry",
+			meta:  map[string]string{EntryDefinitions: "Definitions/iperf.yaml"},
+			files: []string{"Definitions/iperf.yml"},
+			err:   true,
+		},
+		{
+			name:  "single candidate",
+			files: []string{"Definitions/iperf.yml", "Definitions/README.md", "Scripts/install.yaml"},
+			want:  "Definitions/iperf.yml",
+		},
+		{
+			name:  "no candidate",
+			files: []string{"Scripts/install.sh"},
+			err:   true,
+		},
+		{
+			name:  "several candidates",
+			files: []string{"Definitions/iperf.yaml", "Definitions/iperf-old.yaml"},
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := make(map[string]*zip.File)
+			for _, name := range tt.files {
+				files[name] = &zip.File{FileHeader: zip.FileHeader{Name: name}}
+			}
+
+			got, err := entryDefinitions(tt.meta, files)
+			if tt.err {
+				if err == nil {
+					t.Errorf("got %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddScriptsNilLifecycleEvent(t *testing.T) {
+	c, err := OpenCSAR(filepath.Join("testdata", "iperf.csar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.NSD.VNFDs[0].LifecycleEvents = append(c.NSD.VNFDs[0].LifecycleEvents, nil)
+	if err := c.addScripts(nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,399 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tosca
+
+import (
+	"sort"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type vnfProperties struct {
+	ID                 string                         `yaml:"ID"`
+	Vendor             string                         `yaml:"vendor"`
+	Version            string                         `yaml:"version"`
+	Endpoint           string                         `yaml:"endpoint"`
+	Type               string                         `yaml:"type"`
+	VNFPackageLocation string                         `yaml:"vnfPackageLocation"`
+	Configurations     *configurationsProperties      `yaml:"configurations"`
+	DeploymentFlavours []*deploymentFlavourProperties `yaml:"deploymentFlavour"`
+	Provides           []string                       `yaml:"provides"`
+}
+
+type configurationsProperties struct {
+	Name string `yaml:"name"`
+	// a list of single entry maps, e.g. "- key: value"
+	ConfigurationParameters []map[string]string `yaml:"configurationParameters"`
+}
+
+type deploymentFlavourProperties struct {
+	FlavourKey string `yaml:"flavour_key"`
+}
+
+type vduProperties struct {
+	ScaleInOut      int      `yaml:"scale_in_out"`
+	VIMInstanceName []string `yaml:"vim_instance_name"`
+	Hostname        string   `yaml:"hostname"`
+}
+
+type cpProperties struct {
+	Type        string `yaml:"type"`
+	FloatingIP  string `yaml:"floatingIP"`
+	FixedIP     string `yaml:"fixedIp"`
+	InterfaceID int    `yaml:"interfaceId"`
+}
+
+type vlProperties struct {
+	Vendor          string   `yaml:"vendor"`
+	QoS             []string `yaml:"qos"`
+	RootRequirement string   `yaml:"root_requirement"`
+	LeafRequirement string   `yaml:"leaf_requirement"`
+}
+
+// properties decodes the properties of a node, leaving v untouched if there are none.
+func properties(name string, node *NodeTemplate, v interface{}) error {
+	if node.Properties.Kind == 0 {
+		return nil
+	}
+
+	if err := node.Properties.Decode(v); err != nil {
+		return errors.Wrapf(err, "invalid properties of %s", name)
+	}
+
+	return nil
+}
+
+// nodes returns the names of the nodes of the given type, sorted.
+func (st *ServiceTemplate) nodes(typ string) []string {
+	var names []string
+	for name, node := range st.TopologyTemplate.NodeTemplates {
+		if node.Type == typ {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// node returns the node with the given name, failing if it does not exist or is not of the given type.
+func (st *ServiceTemplate) node(name, typ string) (*NodeTemplate, error) {
+	node, ok := st.TopologyTemplate.NodeTemplates[name]
+	if !ok {
+		return nil, errors.Errorf("unknown node %s", name)
+	}
+	if node.Type != typ {
+		return nil, errors.Errorf("the node %s is a %s, not a %s", name, node.Type, typ)
+	}
+
+	return node, nil
+}
+
+// NSD converts the service template to a NetworkServiceDescriptor: every VNF node becomes a VNFD, with the VDU,
+// CP and VL nodes it requires, every VL node a VLD and every relationship a VNF dependency.
+func (st *ServiceTemplate) NSD() (*catalogue.NetworkServiceDescriptor, error) {
+	for name, node := range st.TopologyTemplate.NodeTemplates {
+		switch node.Type {
+		case NodeVNF, NodeVDU, NodeCP, NodeVL:
+		default:
+			return nil, errors.Errorf("the node %s has the unsupported type %s", name, node.Type)
+		}
+	}
+
+	nsd := catalogue.NewNSD(st.Metadata.ID, st.Metadata.Vendor, st.Metadata.Version)
+
+	for _, name := range st.nodes(NodeVL) {
+		var props vlProperties
+		if err := properties(name, st.TopologyTemplate.NodeTemplates[name], &props); err != nil {
+			return nil, err
+		}
+
+		vld := nsd.AddVLD(name)
+		vld.Vendor = props.Vendor
+		vld.RootRequirement = props.RootRequirement
+		vld.LeafRequirement = props.LeafRequirement
+		if props.QoS != nil {
+			vld.QoS = props.QoS
+		}
+	}
+
+	owners := make(map[string]string)
+	for _, name := range st.nodes(NodeVNF) {
+		vnfd, err := st.vnfd(name, owners)
+		if err != nil {
+			return nil, err
+		}
+		nsd.VNFDs = append(nsd.VNFDs, vnfd)
+	}
+
+	for _, name := range st.nodes(NodeVDU) {
+		if _, ok := owners[name]; !ok {
+			return nil, errors.Errorf("the VDU %s is not required by any VNF", name)
+		}
+	}
+
+	names := make([]string, 0, len(st.RelationshipsTemplate))
+	for name := range st.RelationshipsTemplate {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rel := st.RelationshipsTemplate[name]
+		if rel == nil || rel.Type != RelationshipConnectsTo {
+			return nil, errors.Errorf("the relationship %s is not a %s", name, RelationshipConnectsTo)
+		}
+
+		source, target := nsd.FindVNFD(rel.Source), nsd.FindVNFD(rel.Target)
+		if source == nil || target == nil {
+			return nil, errors.Errorf("the relationship %s is not between two VNFs", name)
+		}
+
+		nsd.AddDependency(source, target, rel.Parameters...)
+
+		if target.Requires == nil {
+			target.Requires = make(map[string]*catalogue.RequiresParameters)
+		}
+		req, ok := target.Requires[source.Type]
+		if !ok {
+			req = &catalogue.RequiresParameters{Parameters: []string{}}
+			target.Requires[source.Type] = req
+		}
+		req.Parameters = mergeStrings(req.Parameters, rel.Parameters)
+	}
+
+	return nsd, nil
+}
+
+// vnfd converts the VNF node with the given name, recording it as the owner of its VDUs.
+func (st *ServiceTemplate) vnfd(name string, owners map[string]string) (*catalogue.VirtualNetworkFunctionDescriptor, error) {
+	node := st.TopologyTemplate.NodeTemplates[name]
+
+	var props vnfProperties
+	if err := properties(name, node, &props); err != nil {
+		return nil, err
+	}
+
+	vnfd := &catalogue.VirtualNetworkFunctionDescriptor{
+		ID:                   props.ID,
+		Name:                 name,
+		Vendor:               props.Vendor,
+		Version:              props.Version,
+		Type:                 props.Type,
+		Endpoint:             props.Endpoint,
+		VNFPackageLocation:   props.VNFPackageLocation,
+		Provides:             props.Provides,
+		VNFFGDs:              []*catalogue.VNFForwardingGraphDescriptor{},
+		VLDs:                 []*catalogue.VirtualLinkDescriptor{},
+		MonitoringParameters: []string{},
+		AutoScalePolicies:    []*catalogue.AutoScalePolicy{},
+		ConnectionPoints:     []*catalogue.ConnectionPoint{},
+		LifecycleEvents:      lifecycleEvents(node),
+		VDUs:                 []*catalogue.VirtualDeploymentUnit{},
+		VirtualLinks:         []*catalogue.InternalVirtualLink{},
+		VDUDependencies:      []*catalogue.VDUDependency{},
+		DeploymentFlavours:   []*catalogue.VNFDeploymentFlavour{},
+	}
+	if vnfd.Type == "" {
+		vnfd.Type = name
+	}
+
+	if props.Configurations != nil {
+		vnfd.Configurations = &catalogue.Configuration{
+			Name:                    props.Configurations.Name,
+			ConfigurationParameters: []*catalogue.ConfigurationParameter{},
+		}
+		for _, params := range props.Configurations.ConfigurationParameters {
+			keys := make([]string, 0, len(params))
+			for key := range params {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				vnfd.Configurations.Append(&catalogue.ConfigurationParameter{ConfKey: key, Value: params[key]})
+			}
+		}
+	}
+
+	for _, df := range props.DeploymentFlavours {
+		flavour := &catalogue.VNFDeploymentFlavour{DfConstraint: []string{}, ConstituentVDU: []*catalogue.ConstituentVDU{}}
+		flavour.FlavourKey = df.FlavourKey
+		vnfd.DeploymentFlavours = append(vnfd.DeploymentFlavours, flavour)
+	}
+
+	for _, vl := range node.Requirement(RequirementVirtualLink) {
+		if _, err := st.node(vl, NodeVL); err != nil {
+			return nil, errors.Wrapf(err, "invalid virtual link of %s", name)
+		}
+
+		vnfd.VirtualLinks = append(vnfd.VirtualLinks, &catalogue.InternalVirtualLink{
+			Name:                       vl,
+			QoS:                        []string{},
+			TestAccess:                 []string{},
+			ConnectivityType:           []string{},
+			ConnectionPointsReferences: []string{},
+		})
+	}
+
+	for _, vduName := range node.Requirement(RequirementVDU) {
+		if owner, ok := owners[vduName]; ok {
+			return nil, errors.Errorf("the VDU %s is required by both %s and %s", vduName, owner, name)
+		}
+		owners[vduName] = name
+
+		vdu, err := st.vdu(vduName)
+		if err != nil {
+			return nil, err
+		}
+		vnfd.VDUs = append(vnfd.VDUs, vdu)
+	}
+
+	return vnfd, nil
+}
+
+// vdu converts the VDU node with the given name, with a VNFC for the CPs bound to it.
+func (st *ServiceTemplate) vdu(name string) (*catalogue.VirtualDeploymentUnit, error) {
+	node, err := st.node(name, NodeVDU)
+	if err != nil {
+		return nil, err
+	}
+
+	var props vduProperties
+	if err := properties(name, node, &props); err != nil {
+		return nil, err
+	}
+
+	vdu := &catalogue.VirtualDeploymentUnit{
+		Name:                 name,
+		VMImages:             []string{},
+		LifecycleEvents:      catalogue.LifecycleEvents{},
+		ScaleInOut:           props.ScaleInOut,
+		VNFCs:                []*catalogue.VNFComponent{},
+		VNFCInstances:        []*catalogue.VNFCInstance{},
+		MonitoringParameters: []string{},
+		Hostname:             props.Hostname,
+		VIMInstanceNames:     props.VIMInstanceName,
+	}
+	if vdu.VIMInstanceNames == nil {
+		vdu.VIMInstanceNames = []string{}
+	}
+
+	artifacts := make([]string, 0, len(node.Artifacts))
+	for artifact := range node.Artifacts {
+		artifacts = append(artifacts, artifact)
+	}
+	sort.Strings(artifacts)
+	for _, artifact := range artifacts {
+		if a := node.Artifacts[artifact]; a != nil && a.Type == ArtifactImage {
+			vdu.VMImages = append(vdu.VMImages, a.File)
+		}
+	}
+
+	var cps []*catalogue.VNFDConnectionPoint
+	for _, cpName := range st.nodes(NodeCP) {
+		cpNode := st.TopologyTemplate.NodeTemplates[cpName]
+		bindings := cpNode.Requirement(RequirementVirtualBinding)
+		if len(bindings) == 0 || bindings[0] != name {
+			continue
+		}
+
+		var cpProps cpProperties
+		if err := properties(cpName, cpNode, &cpProps); err != nil {
+			return nil, err
+		}
+
+		links := cpNode.Requirement(RequirementVirtualLink)
+		if len(links) != 1 {
+			return nil, errors.Errorf("the CP %s must require exactly one virtual link", cpName)
+		}
+
+		cp := &catalogue.VNFDConnectionPoint{
+			Type:                 cpProps.Type,
+			FloatingIP:           cpProps.FloatingIP,
+			FixedIp:              cpProps.FixedIP,
+			VirtualLinkReference: links[0],
+			InterfaceID:          cpProps.InterfaceID,
+		}
+		if !hasProperty(cpNode, "interfaceId") {
+			cp.InterfaceID = len(cps)
+		}
+		cps = append(cps, cp)
+	}
+	if cps != nil {
+		vdu.VNFCs = append(vdu.VNFCs, &catalogue.VNFComponent{ConnectionPoints: cps})
+	}
+
+	return vdu, nil
+}
+
+// mergeStrings returns the union of a and b, sorted.
+func mergeStrings(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	ret := make([]string, 0, len(a)+len(b))
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !set[s] {
+			set[s] = true
+			ret = append(ret, s)
+		}
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+// hasProperty reports whether the given property of the node is set.
+func hasProperty(node *NodeTemplate, key string) bool {
+	if node.Properties.Kind != yaml.MappingNode {
+		return false
+	}
+
+	for i := 0; i+1 < len(node.Properties.Content); i += 2 {
+		if node.Properties.Content[i].Value == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lifecycleEvents converts the lifecycle interface of a node, sorted by event.
+func lifecycleEvents(node *NodeTemplate) catalogue.LifecycleEvents {
+	lifecycle := node.Interfaces["lifecycle"]
+
+	events := make([]string, 0, len(lifecycle))
+	for event := range lifecycle {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+
+	les := catalogue.LifecycleEvents{}
+	for _, event := range events {
+		scripts := lifecycle[event]
+		if scripts == nil {
+			scripts = []string{}
+		}
+		les = append(les, &catalogue.LifecycleEvent{
+			Event:           catalogue.Event(event),
+			LifecycleEvents: scripts,
+		})
+	}
+
+	return les
+}
@@ -0,0 +1,224 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tosca
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+)
+
+func parseFixture(t *testing.T) *ServiceTemplate {
+	st, err := ParseFile(filepath.Join("testdata", "iperf.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return st
+}
+
+func TestNSD(t *testing.T) {
+	nsd, err := parseFixture(t).NSD()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if nsd.Name != "NSD-iperf" || nsd.Vendor != "Fokus" || nsd.Version != "0.1" {
+		t.Errorf("got the NSD %s %s %s", nsd.Name, nsd.Vendor, nsd.Version)
+	}
+	if len(nsd.VLDs) != 1 || nsd.VLDs[0].Name != "private" || nsd.VLDs[0].Vendor != "Fokus" {
+		t.Errorf("got the VLDs %+v", nsd.VLDs)
+	}
+
+	names := []string{}
+	for _, vnfd := range nsd.VNFDs {
+		names = append(names, vnfd.Name+" "+vnfd.Type)
+	}
+	if want := []string{"iperf-client client", "iperf-server server"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("got the VNFDs %q, want %q", names, want)
+	}
+
+	server := nsd.FindVNFD("iperf-server")
+	if len(server.VDUs) != 1 || server.VDUs[0].Name != "VDU2" || server.VDUs[0].Hostname != "iperf-server" {
+		t.Errorf("got the VDUs %+v", server.VDUs)
+	}
+	if len(server.VirtualLinks) != 1 || server.VirtualLinks[0].Name != "private" {
+		t.Errorf("got the virtual links %+v", server.VirtualLinks)
+	}
+	if len(server.DeploymentFlavours) != 1 || server.DeploymentFlavours[0].FlavourKey != "m1.small" {
+		t.Errorf("got the deployment flavours %+v", server.DeploymentFlavours)
+	}
+	if server.Configurations == nil || server.Configurations.Name != "iperf-configuration" {
+		t.Fatalf("got the configurations %+v", server.Configurations)
+	}
+	if params := server.Configurations.ConfigurationParameters; len(params) != 1 || params[0].ConfKey != "port" || params[0].Value != "5001" {
+		t.Errorf("got the configuration parameters %+v", params)
+	}
+	events := []string{}
+	for _, le := range server.LifecycleEvents {
+		events = append(events, string(le.Event))
+	}
+	if want := []string{"INSTANTIATE"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("got the lifecycle events %q, want %q", events, want)
+	}
+
+	client := nsd.FindVNFD("iperf-client")
+	events = []string{}
+	for _, le := range client.LifecycleEvents {
+		events = append(events, string(le.Event))
+	}
+	if want := []string{"CONFIGURE", "INSTANTIATE"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("got the lifecycle events %q, want %q", events, want)
+	}
+	if vdu := client.VDUs[0]; vdu.ScaleInOut != 2 || !reflect.DeepEqual(vdu.VIMInstanceNames, []string{"vim-instance"}) ||
+		!reflect.DeepEqual(vdu.VMImages, []string{"ubuntu-16.04"}) {
+		t.Errorf("got the VDU %+v", vdu)
+	}
+
+	if len(nsd.VNFDependencies) != 2 {
+		t.Errorf("got %d VNF dependencies, want 2", len(nsd.VNFDependencies))
+	}
+	if server.Requires != nil {
+		t.Errorf("got the requires %+v of the source", server.Requires)
+	}
+	want := map[string]*catalogue.RequiresParameters{"server": {Parameters: []string{"hostname", "private"}}}
+	if !reflect.DeepEqual(client.Requires, want) {
+		t.Errorf("got the requires %+v, want %+v", client.Requires, want)
+	}
+}
+
+func TestNSDErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(st *ServiceTemplate)
+	}{
+		{
+			name:   "unsupported node type",
+			change: func(st *ServiceTemplate) { st.TopologyTemplate.NodeTemplates["VDU1"].Type = "tosca.nodes.Compute" },
+		},
+		{
+			name:   "VDU not required",
+			change: func(st *ServiceTemplate) { st.TopologyTemplate.NodeTemplates["VDU3"] = &NodeTemplate{Type: NodeVDU} },
+		},
+		{
+			name: "VDU required twice",
+			change: func(st *ServiceTemplate) {
+				node := st.TopologyTemplate.NodeTemplates["iperf-client"]
+				node.Requirements = append(node.Requirements, map[string]string{RequirementVDU: "VDU2"})
+			},
+		},
+		{
+			name: "unknown virtual link",
+			change: func(st *ServiceTemplate) {
+				node := st.TopologyTemplate.NodeTemplates["iperf-client"]
+				node.Requirements = append(node.Requirements, map[string]string{RequirementVirtualLink: "public"})
+			},
+		},
+		{
+			name: "CP without virtual link",
+			change: func(st *ServiceTemplate) {
+				st.TopologyTemplate.NodeTemplates["CP1"].Requirements = []map[string]string{{RequirementVirtualBinding: "VDU1"}}
+			},
+		},
+		{
+			name:   "relationship not between VNFs",
+			change: func(st *ServiceTemplate) { st.RelationshipsTemplate["connection_server_client"].Target = "VDU1" },
+		},
+		{
+			name: "unsupported relationship",
+			change: func(st *ServiceTemplate) {
+				st.RelationshipsTemplate["connection_server_client"].Type = "tosca.relationships.DependsOn"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := parseFixture(t)
+			tt.change(st)
+			if _, err := st.NSD(); err == nil {
+				t.Error("got no error, want one")
+			}
+		})
+	}
+}
+
+func TestVDUConnectionPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		vdu  string
+		// the virtual link, floating IP and interface of the CPs of the VNFC
+		want []catalogue.VNFDConnectionPoint
+	}{
+		{
+			name: "default interface",
+			vdu:  "VDU1",
+			want: []catalogue.VNFDConnectionPoint{{VirtualLinkReference: "private", FloatingIP: "random", InterfaceID: 0}},
+		},
+		{
+			name: "set and default interfaces",
+			vdu:  "VDU2",
+			want: []catalogue.VNFDConnectionPoint{
+				{VirtualLinkReference: "private", FloatingIP: "random", InterfaceID: 1},
+				{VirtualLinkReference: "private", InterfaceID: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vdu, err := parseFixture(t).vdu(tt.vdu)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(vdu.VNFCs) != 1 {
+				t.Fatalf("got %d VNFCs, want 1", len(vdu.VNFCs))
+			}
+
+			got := []catalogue.VNFDConnectionPoint{}
+			for _, cp := range vdu.VNFCs[0].ConnectionPoints {
+				got = append(got, catalogue.VNFDConnectionPoint{
+					VirtualLinkReference: cp.VirtualLinkReference,
+					FloatingIP:           cp.FloatingIP,
+					InterfaceID:          cp.InterfaceID,
+				})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got the CPs %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	st := parseFixture(t)
+	delete(st.TopologyTemplate.NodeTemplates, "CP1")
+	vdu, err := st.vdu("VDU1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vdu.VNFCs) != 0 {
+		t.Errorf("got the VNFCs %+v of a VDU without CPs", vdu.VNFCs)
+	}
+}
+
+func TestMergeStrings(t *testing.T) {
+	got := mergeStrings([]string{"private", "hostname"}, []string{"private", "floatingIp"})
+	if want := []string{"floatingIp", "hostname", "private"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tosca
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// The node types of the Open Baton TOSCA NFV profile
+const (
+	NodeVNF = "openbaton.type.VNF"
+	NodeVDU = "tosca.nodes.nfv.VDU"
+	NodeCP  = "tosca.nodes.nfv.CP"
+	NodeVL  = "tosca.nodes.nfv.VL"
+
+	// An artifact holding the image of a VDU
+	ArtifactImage = "tosca.artifacts.Deployment.Image.VM"
+
+	// A relationship between two VNFs, the target depends on the source
+	RelationshipConnectsTo = "tosca.nodes.relationships.ConnectsTo"
+)
+
+// The names of the requirements of the node templates
+const (
+	RequirementVirtualLink    = "virtualLink"
+	RequirementVDU            = "vdu"
+	RequirementVirtualBinding = "virtualBinding"
+)
+
+// ServiceTemplate is a TOSCA NFV service template describing a network service.
+type ServiceTemplate struct {
+	DefinitionsVersion    string                           `yaml:"tosca_definitions_version"`
+	Description           string                           `yaml:"description"`
+	Metadata              Metadata                         `yaml:"metadata"`
+	TopologyTemplate      TopologyTemplate                 `yaml:"topology_template"`
+	RelationshipsTemplate map[string]*RelationshipTemplate `yaml:"relationships_template"`
+}
+
+type Metadata struct {
+	ID      string `yaml:"ID"`
+	Vendor  string `yaml:"vendor"`
+	Version string `yaml:"version"`
+}
+
+type TopologyTemplate struct {
+	NodeTemplates map[string]*NodeTemplate `yaml:"node_templates"`
+}
+
+// NodeTemplate is a node of the topology; its properties depend on its type and are decoded by the conversion to
+// the catalogue.
+type NodeTemplate struct {
+	Type         string                         `yaml:"type"`
+	Properties   yaml.Node                      `yaml:"properties"`
+	Requirements []map[string]string            `yaml:"requirements"`
+	Interfaces   map[string]map[string][]string `yaml:"interfaces"`
+	Artifacts    map[string]*Artifact           `yaml:"artifacts"`
+}
+
+// Requirement returns the targets of the requirements of the node with the given name, in order.
+func (n *NodeTemplate) Requirement(name string) []string {
+	var targets []string
+	for _, req := range n.Requirements {
+		if target, ok := req[name]; ok {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+type Artifact struct {
+	Type string `yaml:"type"`
+	File string `yaml:"file"`
+}
+
+// RelationshipTemplate is a dependency of the target VNF on the given parameters of the source one.
+type RelationshipTemplate struct {
+	Type       string   `yaml:"type"`
+	Source     string   `yaml:"source"`
+	Target     string   `yaml:"target"`
+	Parameters []string `yaml:"parameters"`
+}
+
+// Parse decodes a TOSCA NFV service template in YAML.
+func Parse(data []byte) (*ServiceTemplate, error) {
+	st := new(ServiceTemplate)
+	if err := yaml.Unmarshal(data, st); err != nil {
+		return nil, errors.Wrap(err, "invalid service template")
+	}
+
+	if !strings.HasPrefix(st.DefinitionsVersion, "tosca_simple_profile_for_nfv") &&
+		!strings.HasPrefix(st.DefinitionsVersion, "tosca_simple_yaml") {
+		return nil, errors.Errorf("unsupported tosca_definitions_version %q", st.DefinitionsVersion)
+	}
+
+	for name, node := range st.TopologyTemplate.NodeTemplates {
+		if node == nil {
+			return nil, errors.Errorf("empty node template %s", name)
+		}
+	}
+
+	return st, nil
+}
+
+// ParseFile decodes the TOSCA NFV service template in the given file.
+func ParseFile(path string) (*ServiceTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := Parse(data)
+	if err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+
+	return st, nil
+}
@@ -0,0 +1,474 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type FindingSeverity string
+
+const (
+	// The descriptor would be rejected by the NFVO or fail at deploy time
+	FindingError = FindingSeverity("ERROR")
+
+	// The descriptor is accepted but probably does not do what is meant
+	FindingWarning = FindingSeverity("WARNING")
+)
+
+type FindingCode string
+
+const (
+	FindingVDUWithoutImage        = FindingCode("VDU_WITHOUT_IMAGE")
+	FindingUnknownVirtualLink     = FindingCode("UNKNOWN_VIRTUAL_LINK")
+	FindingUnknownVDU             = FindingCode("UNKNOWN_VDU")
+	FindingUnknownEvent           = FindingCode("UNKNOWN_EVENT")
+	FindingDuplicateName          = FindingCode("DUPLICATE_NAME")
+	FindingInvalidScaleInOut      = FindingCode("INVALID_SCALE_IN_OUT")
+	FindingUnknownVNFD            = FindingCode("UNKNOWN_VNFD")
+	FindingUnsatisfiedRequirement = FindingCode("UNSATISFIED_REQUIREMENT")
+	FindingUndeclaredRequirement  = FindingCode("UNDECLARED_REQUIREMENT")
+	FindingMissingDependency      = FindingCode("MISSING_DEPENDENCY")
+	FindingNullElement            = FindingCode("NULL_ELEMENT")
+)
+
+// Finding is a problem found by Validate or ValidateNSD in a descriptor.
+// Path locates the faulty element with the JSON names of the fields, e.g. "vdu[0].vm_image".
+type Finding struct {
+	Severity FindingSeverity `json:"severity"`
+	Code     FindingCode     `json:"code"`
+	Path     string          `json:"path"`
+	Message  string          `json:"message"`
+}
+
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s %s: %s", f.Severity, f.Path, f.Message)
+}
+
+// Findings is the result of the validation of a descriptor, empty if it is valid.
+type Findings []*Finding
+
+// Errors returns only the findings with FindingError.
+func (fs Findings) Errors() Findings {
+	ret := Findings{}
+
+	for _, f := range fs {
+		if f.Severity == FindingError {
+			ret = append(ret, f)
+		}
+	}
+
+	return ret
+}
+
+// Err returns an error describing the findings with FindingError, or nil if there are none.
+func (fs Findings) Err() error {
+	errs := fs.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// Error implements the error interface, listing one finding per line.
+func (fs Findings) Error() string {
+	lines := make([]string, len(fs))
+	for i, f := range fs {
+		lines[i] = f.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var knownEvents = map[Event]bool{
+	EventGranted:         true,
+	EventAllocate:        true,
+	EventScale:           true,
+	EventRelease:         true,
+	EventError:           true,
+	EventInstantiate:     true,
+	EventTerminate:       true,
+	EventConfigure:       true,
+	EventStart:           true,
+	EventStop:            true,
+	EventHeal:            true,
+	EventScaleOut:        true,
+	EventScaleIn:         true,
+	EventScaleUp:         true,
+	EventScaleDown:       true,
+	EventUpdate:          true,
+	EventUpdateRollback:  true,
+	EventUpgrade:         true,
+	EventUpgradeRollback: true,
+	EventReset:           true,
+}
+
+// Valid reports whether e is one of the events known to the NFVO.
+func (e Event) Valid() bool {
+	return knownEvents[e]
+}
+
+type validator struct {
+	findings Findings
+}
+
+func (v *validator) add(severity FindingSeverity, code FindingCode, path, format string, args ...interface{}) {
+	v.findings = append(v.findings, &Finding{
+		Severity: severity,
+		Code:     code,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkNull reports whether the element i of a list is null, adding a finding if it is.
+// The checks of the elements skip the null ones.
+func (v *validator) checkNull(path string, i int, isNull bool) bool {
+	if isNull {
+		v.add(FindingError, FindingNullElement, fmt.Sprintf("%s[%d]", path, i), "null element")
+	}
+
+	return isNull
+}
+
+// checkDuplicates reports the names used more than once; the element i of a list is named by name(i),
+// the null elements by "".
+func (v *validator) checkDuplicates(path string, n int, name func(int) string) {
+	seen := make(map[string]bool)
+	for i := 0; i < n; i++ {
+		nm := name(i)
+		if nm == "" {
+			continue
+		}
+		if seen[nm] {
+			v.add(FindingError, FindingDuplicateName, fmt.Sprintf("%s[%d]", path, i), "duplicate name %q", nm)
+		}
+		seen[nm] = true
+	}
+}
+
+func (v *validator) checkEvents(path string, events LifecycleEvents) {
+	for i, le := range events {
+		if v.checkNull(path, i, le == nil) {
+			continue
+		}
+		if !le.Event.Valid() {
+			v.add(FindingError, FindingUnknownEvent, fmt.Sprintf("%s[%d].event", path, i), "unknown lifecycle event %q", le.Event)
+		}
+	}
+}
+
+// Validate checks the consistency of a VNFD, returning the problems found.
+func Validate(vnfd *VirtualNetworkFunctionDescriptor) Findings {
+	v := &validator{findings: Findings{}}
+	v.validateVNFD("", vnfd)
+
+	return v.findings
+}
+
+func (v *validator) validateVNFD(prefix string, vnfd *VirtualNetworkFunctionDescriptor) {
+	v.checkDuplicates(prefix+"vdu", len(vnfd.VDUs), func(i int) string {
+		if vnfd.VDUs[i] == nil {
+			return ""
+		}
+		return vnfd.VDUs[i].Name
+	})
+	v.checkDuplicates(prefix+"virtual_link", len(vnfd.VirtualLinks), func(i int) string {
+		if v.checkNull(prefix+"virtual_link", i, vnfd.VirtualLinks[i] == nil) {
+			return ""
+		}
+		return vnfd.VirtualLinks[i].Name
+	})
+	v.checkDuplicates(prefix+"deployment_flavour", len(vnfd.DeploymentFlavours), func(i int) string {
+		if v.checkNull(prefix+"deployment_flavour", i, vnfd.DeploymentFlavours[i] == nil) {
+			return ""
+		}
+		return vnfd.DeploymentFlavours[i].FlavourKey
+	})
+	v.checkEvents(prefix+"lifecycle_event", vnfd.LifecycleEvents)
+
+	links := make(map[string]bool)
+	for _, link := range vnfd.VirtualLinks {
+		if link != nil {
+			links[link.Name] = true
+		}
+	}
+
+	vdus := make(map[string]*VirtualDeploymentUnit)
+	for _, vdu := range vnfd.VDUs {
+		if vdu == nil {
+			continue
+		}
+		if vdu.ID != "" {
+			vdus[vdu.ID] = vdu
+		}
+		if vdu.Name != "" {
+			vdus[vdu.Name] = vdu
+		}
+	}
+
+	for i, vdu := range vnfd.VDUs {
+		if v.checkNull(prefix+"vdu", i, vdu == nil) {
+			continue
+		}
+		path := fmt.Sprintf("%svdu[%d]", prefix, i)
+
+		if len(vdu.VMImages) == 0 {
+			v.add(FindingError, FindingVDUWithoutImage, path+".vm_image", "the VDU %q has no image", vdu.Name)
+		}
+
+		switch {
+		case vdu.ScaleInOut < 1:
+			v.add(FindingError, FindingInvalidScaleInOut, path+".scale_in_out", "scale_in_out must be at least 1, is %d", vdu.ScaleInOut)
+		case vdu.ScaleInOut < len(vdu.VNFCs):
+			v.add(FindingError, FindingInvalidScaleInOut, path+".scale_in_out",
+				"scale_in_out is %d but the VDU has %d VNFCs", vdu.ScaleInOut, len(vdu.VNFCs))
+		}
+
+		v.checkEvents(path+".lifecycle_event", vdu.LifecycleEvents)
+
+		for j, vnfc := range vdu.VNFCs {
+			if v.checkNull(path+".vnfc", j, vnfc == nil) {
+				continue
+			}
+			for k, cp := range vnfc.ConnectionPoints {
+				if v.checkNull(fmt.Sprintf("%s.vnfc[%d].connection_point", path, j), k, cp == nil) {
+					continue
+				}
+				if !links[cp.VirtualLinkReference] {
+					v.add(FindingError, FindingUnknownVirtualLink, fmt.Sprintf("%s.vnfc[%d].connection_point[%d].virtual_link_reference", path, j, k),
+						"unknown virtual link %q", cp.VirtualLinkReference)
+				}
+			}
+		}
+	}
+
+	for i, dep := range vnfd.VDUDependencies {
+		if v.checkNull(prefix+"vdu_dependency", i, dep == nil) {
+			continue
+		}
+		path := fmt.Sprintf("%svdu_dependency[%d]", prefix, i)
+		if !knownVDU(vdus, dep.Source) {
+			v.add(FindingError, FindingUnknownVDU, path+".source", "unknown VDU %s", describeVDU(dep.Source))
+		}
+		if !knownVDU(vdus, dep.Target) {
+			v.add(FindingError, FindingUnknownVDU, path+".target", "unknown VDU %s", describeVDU(dep.Target))
+		}
+	}
+
+	for i, df := range vnfd.DeploymentFlavours {
+		if df == nil {
+			continue
+		}
+		for j, cvdu := range df.ConstituentVDU {
+			if v.checkNull(fmt.Sprintf("%sdeployment_flavour[%d].constituent_vdu", prefix, i), j, cvdu == nil) {
+				continue
+			}
+			path := fmt.Sprintf("%sdeployment_flavour[%d].constituent_vdu[%d]", prefix, i, j)
+			vdu, ok := vdus[cvdu.VDUReference]
+			if !ok {
+				v.add(FindingError, FindingUnknownVDU, path+".vdu_reference", "unknown VDU %q", cvdu.VDUReference)
+				continue
+			}
+			if cvdu.NumberOfInstances > vdu.ScaleInOut {
+				v.add(FindingError, FindingInvalidScaleInOut, path+".number_of_instances",
+					"%d instances exceed the scale_in_out %d of the VDU %q", cvdu.NumberOfInstances, vdu.ScaleInOut, vdu.Name)
+			}
+		}
+	}
+}
+
+func knownVDU(vdus map[string]*VirtualDeploymentUnit, vdu *VirtualDeploymentUnit) bool {
+	if vdu == nil {
+		return false
+	}
+
+	_, byID := vdus[vdu.ID]
+	_, byName := vdus[vdu.Name]
+
+	return (vdu.ID != "" && byID) || (vdu.Name != "" && byName)
+}
+
+func describeVDU(vdu *VirtualDeploymentUnit) string {
+	switch {
+	case vdu == nil:
+		return "(none)"
+	case vdu.Name != "":
+		return fmt.Sprintf("%q", vdu.Name)
+	default:
+		return fmt.Sprintf("with id %q", vdu.ID)
+	}
+}
+
+// ValidateNSD checks the consistency of an NSD and of its VNFDs, including that the parameters
+// required by each VNFD are provided by the VNFDs it depends on.
+func ValidateNSD(nsd *NetworkServiceDescriptor) Findings {
+	v := &validator{findings: Findings{}}
+
+	v.checkDuplicates("vnfd", len(nsd.VNFDs), func(i int) string {
+		if nsd.VNFDs[i] == nil {
+			return ""
+		}
+		return nsd.VNFDs[i].Name
+	})
+	v.checkDuplicates("vld", len(nsd.VLDs), func(i int) string {
+		if v.checkNull("vld", i, nsd.VLDs[i] == nil) {
+			return ""
+		}
+		return nsd.VLDs[i].Name
+	})
+	for i, vnfd := range nsd.VNFDs {
+		if v.checkNull("vnfd", i, vnfd == nil) {
+			continue
+		}
+		v.validateVNFD(fmt.Sprintf("vnfd[%d].", i), vnfd)
+	}
+
+	byType := make(map[string]*VirtualNetworkFunctionDescriptor)
+	for _, vnfd := range nsd.VNFDs {
+		if vnfd != nil {
+			byType[vnfd.Type] = vnfd
+		}
+	}
+
+	// the parameters each target requires, by source type, according to the vnf_dependency
+	declared := make(map[string]map[string]map[string]bool)
+	for i, dep := range nsd.VNFDependencies {
+		if v.checkNull("vnf_dependency", i, dep == nil) {
+			continue
+		}
+		path := fmt.Sprintf("vnf_dependency[%d]", i)
+		source := v.dependencyVNFD(nsd, path+".source", dep.Source)
+		target := v.dependencyVNFD(nsd, path+".target", dep.Target)
+		if source == nil || target == nil {
+			continue
+		}
+
+		if declared[target.Name] == nil {
+			declared[target.Name] = make(map[string]map[string]bool)
+		}
+		if declared[target.Name][source.Type] == nil {
+			declared[target.Name][source.Type] = make(map[string]bool)
+		}
+
+		var required map[string]bool
+		if req := target.Requires[source.Type]; req != nil {
+			required = toSet(req.Parameters)
+		}
+		for _, param := range dep.Parameters {
+			declared[target.Name][source.Type][param] = true
+			if !required[param] {
+				v.add(FindingWarning, FindingUndeclaredRequirement, path+".parameters",
+					"the VNFD %q does not require %q from %q", target.Name, param, source.Type)
+			}
+		}
+	}
+
+	for i, vnfd := range nsd.VNFDs {
+		if vnfd == nil {
+			continue
+		}
+		for _, sourceType := range sortedKeys(vnfd.Requires) {
+			path := fmt.Sprintf("vnfd[%d].requires.%s", i, sourceType)
+			if vnfd.Requires[sourceType] == nil {
+				v.add(FindingError, FindingNullElement, path, "null element")
+				continue
+			}
+			source, ok := byType[sourceType]
+			if !ok {
+				v.add(FindingError, FindingUnknownVNFD, path, "no VNFD of type %q in the NSD", sourceType)
+				continue
+			}
+
+			provided := providedParameters(nsd, source)
+			for _, param := range vnfd.Requires[sourceType].Parameters {
+				if !provided[param] {
+					v.add(FindingError, FindingUnsatisfiedRequirement, path,
+						"the VNFD %q requires %q, which the VNFD %q does not provide", vnfd.Name, param, source.Name)
+				}
+				if !declared[vnfd.Name][sourceType][param] {
+					v.add(FindingWarning, FindingMissingDependency, path,
+						"no vnf_dependency gives %q from %q to %q", param, source.Name, vnfd.Name)
+				}
+			}
+		}
+	}
+
+	return v.findings
+}
+
+// dependencyVNFD returns the VNFD of the NSD a dependency refers to by name, nil (and a finding) if unknown.
+func (v *validator) dependencyVNFD(nsd *NetworkServiceDescriptor, path string, ref *VirtualNetworkFunctionDescriptor) *VirtualNetworkFunctionDescriptor {
+	if ref == nil {
+		v.add(FindingError, FindingUnknownVNFD, path, "missing VNFD")
+		return nil
+	}
+
+	vnfd := nsd.FindVNFD(ref.Name)
+	if vnfd == nil {
+		v.add(FindingError, FindingUnknownVNFD, path, "unknown VNFD %q", ref.Name)
+	}
+
+	return vnfd
+}
+
+// providedParameters returns the parameters a VNF makes available to the ones depending on it:
+// its provides, its hostname and, for each network it is connected to, its IP and floating IP.
+func providedParameters(nsd *NetworkServiceDescriptor, vnfd *VirtualNetworkFunctionDescriptor) map[string]bool {
+	provided := toSet(vnfd.Provides)
+	provided["hostname"] = true
+
+	networks := make([]string, 0, len(vnfd.VirtualLinks)+len(nsd.VLDs))
+	for _, link := range vnfd.VirtualLinks {
+		if link != nil {
+			networks = append(networks, link.Name)
+		}
+	}
+	for _, vld := range nsd.VLDs {
+		if vld != nil {
+			networks = append(networks, vld.Name)
+		}
+	}
+	for _, network := range networks {
+		provided[network] = true
+		provided[network+"_floatingIp"] = true
+	}
+
+	return provided
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+
+	return set
+}
+
+func sortedKeys(m map[string]*RequiresParameters) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
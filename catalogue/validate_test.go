@@ -0,0 +1,194 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// findingKeys returns the findings as "CODE path", sorted.
+func findingKeys(fs Findings) []string {
+	keys := make([]string, len(fs))
+	for i, f := range fs {
+		keys[i] = string(f.Code) + " " + f.Path
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+const validVNFD = `{
+	"name": "server",
+	"type": "server",
+	"virtual_link": [{"name": "private"}],
+	"lifecycle_event": [{"event": "INSTANTIATE", "lifecycle_events": ["install.sh"]}],
+	"vdu": [{
+		"name": "vdu1",
+		"vm_image": ["ubuntu"],
+		"scale_in_out": 2,
+		"vnfc": [{"connection_point": [{"virtual_link_reference": "private"}]}]
+	}],
+	"deployment_flavour": [{"flavour_key": "m1.small"}]
+}`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		vnfd string
+		want []string
+	}{
+		{"valid", validVNFD, []string{}},
+		{
+			"invalid",
+			`{
+				"vdu": [
+					{"name": "vdu1", "scale_in_out": 0, "lifecycle_event": [{"event": "REBOOT"}],
+					 "vnfc": [{"connection_point": [{"virtual_link_reference": "public"}]}]},
+					{"name": "vdu1", "vm_image": ["ubuntu"], "scale_in_out": 1}
+				],
+				"vdu_dependency": [{"source": {"name": "vdu1"}, "target": {"name": "vdu2"}}]
+			}`,
+			[]string{
+				"DUPLICATE_NAME vdu[1]",
+				"INVALID_SCALE_IN_OUT vdu[0].scale_in_out",
+				"UNKNOWN_EVENT vdu[0].lifecycle_event[0].event",
+				"UNKNOWN_VDU vdu_dependency[0].target",
+				"UNKNOWN_VIRTUAL_LINK vdu[0].vnfc[0].connection_point[0].virtual_link_reference",
+				"VDU_WITHOUT_IMAGE vdu[0].vm_image",
+			},
+		},
+		{
+			"null elements",
+			`{
+				"virtual_link": [null],
+				"lifecycle_event": [null],
+				"vdu": [
+					null,
+					{"name": "vdu1", "vm_image": ["ubuntu"], "scale_in_out": 2, "lifecycle_event": [null],
+					 "vnfc": [null, {"connection_point": [null]}]}
+				],
+				"vdu_dependency": [null],
+				"deployment_flavour": [null, {"flavour_key": "m1.small", "constituent_vdu": [null]}]
+			}`,
+			[]string{
+				"NULL_ELEMENT deployment_flavour[0]",
+				"NULL_ELEMENT deployment_flavour[1].constituent_vdu[0]",
+				"NULL_ELEMENT lifecycle_event[0]",
+				"NULL_ELEMENT vdu[0]",
+				"NULL_ELEMENT vdu[1].lifecycle_event[0]",
+				"NULL_ELEMENT vdu[1].vnfc[0]",
+				"NULL_ELEMENT vdu[1].vnfc[1].connection_point[0]",
+				"NULL_ELEMENT vdu_dependency[0]",
+				"NULL_ELEMENT virtual_link[0]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vnfd := &VirtualNetworkFunctionDescriptor{}
+			if err := json.Unmarshal([]byte(tt.vnfd), vnfd); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := findingKeys(Validate(vnfd)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNSD(t *testing.T) {
+	tests := []struct {
+		name string
+		nsd  string
+		want []string
+	}{
+		{
+			"satisfied",
+			`{
+				"vld": [{"name": "private"}],
+				"vnfd": [
+					{"name": "server", "type": "server", "provides": ["port"],
+					 "vdu": [{"name": "vdu1", "vm_image": ["ubuntu"], "scale_in_out": 1}]},
+					{"name": "client", "type": "client", "requires": {"server": {"parameters": ["port", "private"]}},
+					 "vdu": [{"name": "vdu2", "vm_image": ["ubuntu"], "scale_in_out": 1}]}
+				],
+				"vnf_dependency": [{"source": {"name": "server"}, "target": {"name": "client"}, "parameters": ["port", "private"]}]
+			}`,
+			[]string{},
+		},
+		{
+			"unsatisfied",
+			`{
+				"vnfd": [
+					{"name": "server", "type": "server",
+					 "vdu": [{"name": "vdu1", "vm_image": ["ubuntu"], "scale_in_out": 1}]},
+					{"name": "client", "type": "client", "requires": {"server": {"parameters": ["port"]}, "db": {"parameters": ["url"]}},
+					 "vdu": [{"name": "vdu2", "vm_image": ["ubuntu"], "scale_in_out": 1}]}
+				],
+				"vnf_dependency": [
+					{"source": {"name": "server"}, "target": {"name": "client"}, "parameters": ["user"]},
+					{"source": {"name": "proxy"}, "target": {"name": "client"}}
+				]
+			}`,
+			[]string{
+				"MISSING_DEPENDENCY vnfd[1].requires.server",
+				"UNDECLARED_REQUIREMENT vnf_dependency[0].parameters",
+				"UNKNOWN_VNFD vnf_dependency[1].source",
+				"UNKNOWN_VNFD vnfd[1].requires.db",
+				"UNSATISFIED_REQUIREMENT vnfd[1].requires.server",
+			},
+		},
+		{
+			"null elements",
+			`{
+				"vld": [null],
+				"vnfd": [
+					null,
+					{"name": "client", "type": "client", "requires": {"server": null},
+					 "vdu": [null]}
+				],
+				"vnf_dependency": [null, {"source": null, "target": {"name": "client"}}]
+			}`,
+			[]string{
+				"NULL_ELEMENT vld[0]",
+				"NULL_ELEMENT vnf_dependency[0]",
+				"NULL_ELEMENT vnfd[0]",
+				"NULL_ELEMENT vnfd[1].requires.server",
+				"NULL_ELEMENT vnfd[1].vdu[0]",
+				"UNKNOWN_VNFD vnf_dependency[1].source",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsd := &NetworkServiceDescriptor{}
+			if err := json.Unmarshal([]byte(tt.nsd), nsd); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := findingKeys(ValidateNSD(nsd)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateNSD() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vnfpackage
+
+// How the NFVO obtains the image of a package
+type ImageUpload string
+
+const (
+	// Upload the image of the package, or the one at the link
+	UploadTrue = ImageUpload("true")
+
+	// Use an image already on the VIM, by name or ID
+	UploadFalse = ImageUpload("false")
+
+	// Use an image already on the VIM if there is one, upload it otherwise
+	UploadCheck = ImageUpload("check")
+)
+
+// Metadata is the content of the Metadata.yaml of a package.
+type Metadata struct {
+	Name        string       `yaml:"name"`
+	Description string       `yaml:"description,omitempty"`
+	Provider    string       `yaml:"provider,omitempty"`
+	NFVOVersion string       `yaml:"nfvo_version"`
+	VIMTypes    []string     `yaml:"vim_types"`
+	Image       Image        `yaml:"image"`
+	ImageConfig *ImageConfig `yaml:"image-config,omitempty"`
+	ScriptsLink string       `yaml:"scripts-link,omitempty"`
+}
+
+type Image struct {
+	Upload ImageUpload `yaml:"upload"`
+	Names  []string    `yaml:"names,omitempty"`
+	IDs    []string    `yaml:"ids,omitempty"`
+	Link   string      `yaml:"link,omitempty"`
+}
+
+// ImageConfig describes the image to upload.
+type ImageConfig struct {
+	Name            string `yaml:"name"`
+	DiskFormat      string `yaml:"diskFormat"`
+	ContainerFormat string `yaml:"containerFormat"`
+	MinCPU          string `yaml:"minCPU,omitempty"`
+	MinDisk         int64  `yaml:"minDisk"`
+	MinRAM          int64  `yaml:"minRam"`
+	IsPublic        bool   `yaml:"isPublic"`
+}
@@ -0,0 +1,269 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vnfpackage
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// The layout of a package
+const (
+	MetadataFile = "Metadata.yaml"
+	VNFDFile     = "vnfd.json"
+	ScriptsDir   = "scripts/"
+
+	// The extension of the image file
+	ImageExt = ".img"
+)
+
+// Package is an Open Baton VNF package: the metadata, the VNFD, the scripts and optionally the image of a VNF.
+type Package struct {
+	Metadata *Metadata
+	VNFD     *catalogue.VirtualNetworkFunctionDescriptor
+	Scripts  []*catalogue.Script
+
+	// The image to upload, ImageFile is its name in the archive
+	ImageFile string
+	Image     []byte
+}
+
+// New returns a package for the given VNFD, to be uploaded on the VIMs of the given types.
+func New(vnfd *catalogue.VirtualNetworkFunctionDescriptor, vimTypes ...string) *Package {
+	return &Package{
+		Metadata: &Metadata{
+			Name:     vnfd.Name,
+			VIMTypes: append([]string{}, vimTypes...),
+			Image:    Image{Upload: UploadFalse},
+		},
+		VNFD:    vnfd,
+		Scripts: []*catalogue.Script{},
+	}
+}
+
+// AddScript adds a script to the package, replacing the one with the same name if any.
+func (p *Package) AddScript(name string, payload []byte) {
+	for _, script := range p.Scripts {
+		if script.Name == name {
+			script.Payload = payload
+			return
+		}
+	}
+
+	p.Scripts = append(p.Scripts, &catalogue.Script{Name: name, Payload: payload})
+}
+
+// Script returns the script with the given name, or nil if there is none.
+func (p *Package) Script(name string) *catalogue.Script {
+	for _, script := range p.Scripts {
+		if script.Name == name {
+			return script
+		}
+	}
+
+	return nil
+}
+
+// VNFPackage returns the catalogue representation of the package.
+func (p *Package) VNFPackage() *catalogue.VNFPackage {
+	vimTypes := make([]string, len(p.Metadata.VIMTypes))
+	copy(vimTypes, p.Metadata.VIMTypes)
+
+	return &catalogue.VNFPackage{
+		Name:        p.Metadata.Name,
+		NFVOVersion: p.Metadata.NFVOVersion,
+		VIMTypes:    vimTypes,
+		ImageLink:   p.Metadata.Image.Link,
+		ScriptsLink: p.Metadata.ScriptsLink,
+		Scripts:     p.Scripts,
+	}
+}
+
+// Open reads the package in the given file.
+func Open(name string) (*Package, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p, err := Read(f)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	return p, nil
+}
+
+// Read reads a package from a tar archive, optionally gzipped.
+// Only the structure of the package is checked, see Validate for its content.
+func Read(r io.Reader) (*Package, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	p := &Package{Scripts: []*catalogue.Script{}}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrap(err, hdr.Name)
+		}
+
+		name := strings.TrimPrefix(path.Clean(hdr.Name), "./")
+		switch {
+		case name == MetadataFile:
+			p.Metadata = new(Metadata)
+			if err := yaml.Unmarshal(data, p.Metadata); err != nil {
+				return nil, errors.Wrap(err, MetadataFile)
+			}
+
+		case name == VNFDFile:
+			p.VNFD = new(catalogue.VirtualNetworkFunctionDescriptor)
+			if err := json.Unmarshal(data, p.VNFD); err != nil {
+				return nil, errors.Wrap(err, VNFDFile)
+			}
+
+		case strings.HasPrefix(name, ScriptsDir):
+			p.Scripts = append(p.Scripts, &catalogue.Script{Name: strings.TrimPrefix(name, ScriptsDir), Payload: data})
+
+		case path.Ext(name) == ImageExt:
+			if p.Image != nil {
+				return nil, errors.Errorf("more than one image: %s and %s", p.ImageFile, name)
+			}
+			p.ImageFile, p.Image = name, data
+		}
+	}
+
+	if p.Metadata == nil {
+		return nil, errors.Errorf("missing %s", MetadataFile)
+	}
+	if p.VNFD == nil {
+		return nil, errors.Errorf("missing %s", VNFDFile)
+	}
+
+	return p, nil
+}
+
+// WriteFile writes the package to the given file as a tar archive.
+func (p *Package) WriteFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Write writes the package as a tar archive, with the entries in a stable order.
+func (p *Package) Write(w io.Writer) error {
+	if p.Metadata == nil || p.VNFD == nil {
+		return errors.New("the metadata and the VNFD are required")
+	}
+
+	metadata, err := yaml.Marshal(p.Metadata)
+	if err != nil {
+		return err
+	}
+
+	vnfd, err := json.MarshalIndent(p.VNFD, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+	now := time.Now()
+	add := func(name string, mode int64, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    mode,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := io.Copy(tw, bytes.NewReader(data))
+		return err
+	}
+
+	if err := add(MetadataFile, 0644, metadata); err != nil {
+		return err
+	}
+	if err := add(VNFDFile, 0644, vnfd); err != nil {
+		return err
+	}
+
+	scripts := make([]*catalogue.Script, len(p.Scripts))
+	copy(scripts, p.Scripts)
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].Name < scripts[j].Name })
+	for _, script := range scripts {
+		if err := add(ScriptsDir+script.Name, 0755, script.Payload); err != nil {
+			return err
+		}
+	}
+
+	if p.Image != nil {
+		imageFile := p.ImageFile
+		if imageFile == "" {
+			imageFile = p.Metadata.Name + ImageExt
+		}
+		if err := add(imageFile, 0644, p.Image); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
@@ -0,0 +1,207 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vnfpackage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+)
+
+func openFixture(t *testing.T, name string) *Package {
+	p, err := Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, fixture := range []string{"iperf-server.tar", "iperf-server.tar.gz"} {
+		t.Run(fixture, func(t *testing.T) {
+			p := openFixture(t, fixture)
+
+			if p.Metadata.Name != "iperf-server" || p.VNFD.Name != "iperf-server" || p.Image != nil {
+				t.Errorf("got the package %s of the VNFD %s", p.Metadata.Name, p.VNFD.Name)
+			}
+			names := []string{}
+			for _, script := range p.Scripts {
+				names = append(names, script.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, []string{"install.sh", "start.sh"}) {
+				t.Errorf("got the scripts %q", names)
+			}
+			if findings := p.Validate(); len(findings) > 0 {
+				t.Errorf("got the findings %v", findings)
+			}
+
+			var plain bytes.Buffer
+			if err := p.Write(&plain); err != nil {
+				t.Fatal(err)
+			}
+			var gzipped bytes.Buffer
+			gz := gzip.NewWriter(&gzipped)
+			if _, err := gz.Write(plain.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			if err := gz.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			for name, archive := range map[string][]byte{"plain": plain.Bytes(), "gzipped": gzipped.Bytes()} {
+				again, err := Read(bytes.NewReader(archive))
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if !reflect.DeepEqual(again, p) {
+					t.Errorf("%s: got %+v after a round trip, want %+v", name, again, p)
+				}
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	write := func(p *Package) []byte {
+		var buf bytes.Buffer
+		if err := p.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name    string
+		archive func(p *Package) []byte
+		err     bool
+	}{
+		{
+			name:    "with image",
+			archive: func(p *Package) []byte { p.Image = []byte("image"); return write(p) },
+		},
+		{
+			name:    "not an archive",
+			archive: func(p *Package) []byte { return []byte("iperf-server") },
+			err:     true,
+		},
+		{
+			name:    "empty",
+			archive: func(p *Package) []byte { return nil },
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := openFixture(t, "iperf-server.tar")
+			got, err := Read(bytes.NewReader(tt.archive(p)))
+			if tt.err {
+				if err == nil {
+					t.Error("got no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ImageFile != "iperf-server"+ImageExt || !bytes.Equal(got.Image, p.Image) {
+				t.Errorf("got the image %s %q", got.ImageFile, got.Image)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(p *Package)
+		// the severity, code and path of the findings
+		want []string
+	}{
+		{
+			name:   "valid",
+			change: func(p *Package) {},
+			want:   []string{},
+		},
+		{
+			name: "missing script",
+			change: func(p *Package) {
+				p.Scripts = p.Scripts[:1]
+			},
+			want: []string{"ERROR MISSING_SCRIPT vnfd.json:lifecycle_event[1]"},
+		},
+		{
+			name: "scripts link",
+			change: func(p *Package) {
+				p.Metadata.ScriptsLink = "https://github.com/openbaton/vnf-scripts.git"
+				p.Scripts = []*catalogue.Script{}
+			},
+			want: []string{},
+		},
+		{
+			name: "nil lifecycle event",
+			change: func(p *Package) {
+				p.VNFD.LifecycleEvents = append(p.VNFD.LifecycleEvents, nil)
+			},
+			want: []string{"ERROR NULL_ELEMENT vnfd.json:lifecycle_event[2]"},
+		},
+		{
+			name: "metadata",
+			change: func(p *Package) {
+				p.Metadata.Name = "iperf"
+				p.Metadata.VIMTypes = nil
+				p.Metadata.Image = Image{Upload: UploadTrue}
+			},
+			want: []string{
+				"WARNING INVALID_METADATA Metadata.yaml:name",
+				"ERROR INVALID_METADATA Metadata.yaml:vim_types",
+				"ERROR INVALID_METADATA Metadata.yaml:image.link",
+				"ERROR INVALID_METADATA Metadata.yaml:image-config",
+			},
+		},
+		{
+			name: "image never uploaded",
+			change: func(p *Package) {
+				p.Image = []byte("image")
+				p.ImageFile = "iperf-server.img"
+			},
+			want: []string{"WARNING INVALID_METADATA iperf-server.img"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := openFixture(t, "iperf-server.tar")
+			tt.change(p)
+
+			got := []string{}
+			for _, f := range p.Validate() {
+				got = append(got, string(f.Severity)+" "+string(f.Code)+" "+f.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package vnfpackage
+
+import (
+	"fmt"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+)
+
+const (
+	FindingInvalidMetadata = catalogue.FindingCode("INVALID_METADATA")
+	FindingMissingScript   = catalogue.FindingCode("MISSING_SCRIPT")
+)
+
+// Validate checks the metadata of the package, that the scripts the lifecycle events refer to are in it and the
+// VNFD itself (see catalogue.Validate). The paths of the findings are prefixed by the file they refer to.
+func (p *Package) Validate() catalogue.Findings {
+	findings := catalogue.Findings{}
+	add := func(severity catalogue.FindingSeverity, code catalogue.FindingCode, path, format string, args ...interface{}) {
+		findings = append(findings, &catalogue.Finding{
+			Severity: severity,
+			Code:     code,
+			Path:     path,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+	invalid := func(field, format string, args ...interface{}) {
+		add(catalogue.FindingError, FindingInvalidMetadata, MetadataFile+":"+field, format, args...)
+	}
+
+	md := p.Metadata
+	if md.Name == "" {
+		invalid("name", "the name is required")
+	} else if p.VNFD.Name != "" && p.VNFD.Name != md.Name {
+		add(catalogue.FindingWarning, FindingInvalidMetadata, MetadataFile+":name",
+			"the package is named %q but its VNFD %q", md.Name, p.VNFD.Name)
+	}
+	if len(md.VIMTypes) == 0 {
+		invalid("vim_types", "at least one VIM type is required")
+	}
+
+	image := md.Image
+	switch image.Upload {
+	case UploadTrue:
+		if image.Link == "" && p.Image == nil {
+			invalid("image.link", "uploading the image requires its link or the image in the package")
+		}
+	case UploadFalse:
+		if len(image.Names) == 0 && len(image.IDs) == 0 {
+			invalid("image", "the names or IDs of the image are required if it is not uploaded")
+		}
+	case UploadCheck:
+		if len(image.Names) == 0 && len(image.IDs) == 0 {
+			invalid("image", "the names or IDs of the image are required to check for it")
+		}
+		if image.Link == "" && p.Image == nil {
+			invalid("image.link", "uploading the image requires its link or the image in the package")
+		}
+	default:
+		invalid("image.upload", "unknown value %q, expected true, false or check", image.Upload)
+	}
+	if image.Upload != UploadFalse && md.ImageConfig == nil {
+		invalid("image-config", "the image configuration is required to upload the image")
+	}
+	if image.Upload == UploadFalse && p.Image != nil {
+		add(catalogue.FindingWarning, FindingInvalidMetadata, p.ImageFile, "the image is never uploaded")
+	}
+
+	if md.ScriptsLink == "" {
+		for i, le := range p.VNFD.LifecycleEvents {
+			// reported by catalogue.Validate
+			if le == nil {
+				continue
+			}
+			for _, script := range le.LifecycleEvents {
+				if p.Script(script) == nil {
+					add(catalogue.FindingError, FindingMissingScript, fmt.Sprintf("%s:lifecycle_event[%d]", VNFDFile, i),
+						"the script %s of the %s event is not in %s", script, le.Event, ScriptsDir)
+				}
+			}
+		}
+	}
+
+	for _, finding := range catalogue.Validate(p.VNFD) {
+		finding.Path = VNFDFile + ":" + finding.Path
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
@@ -71,7 +71,15 @@ func NewVNFR(
 
 	autoScalePolicies := make([]*AutoScalePolicy, len(vnfd.AutoScalePolicies))
 	for i, asp := range vnfd.AutoScalePolicies {
-		autoScalePolicies[i] = cloneAutoScalePolicy(asp, vnfd)
+		autoScalePolicies[i] = copyAutoScalePolicy(asp)
+		if asp == nil {
+			continue
+		}
+		for _, action := range autoScalePolicies[i].Actions {
+			if action != nil && action.Target == "" {
+				action.Target = vnfd.Type
+			}
+		}
 	}
 
 	configurations := &Configuration{
@@ -222,45 +230,6 @@ func (vnfr *VirtualNetworkFunctionRecord) String() string {
 	return string(b)
 }
 
-func cloneAutoScalePolicy(asp *AutoScalePolicy, vnfd *VirtualNetworkFunctionDescriptor) *AutoScalePolicy {
-	newAsp := &AutoScalePolicy{
-		Name:               asp.Name,
-		Type:               asp.Type,
-		Cooldown:           asp.Cooldown,
-		Period:             asp.Period,
-		ComparisonOperator: asp.ComparisonOperator,
-		Threshold:          asp.Threshold,
-		Mode:               asp.Mode,
-	}
-
-	newAsp.Actions = make([]*ScalingAction, len(asp.Actions))
-	for i, action := range asp.Actions {
-		target := action.Target
-		if target == "" {
-			target = vnfd.Type
-		}
-
-		newAsp.Actions[i] = &ScalingAction{
-			Target: target,
-			Type:   action.Type,
-			Value:  action.Value,
-		}
-	}
-
-	newAsp.Alarms = make([]*ScalingAlarm, len(asp.Alarms))
-	for i, alarm := range asp.Alarms {
-		newAsp.Alarms[i] = &ScalingAlarm{
-			ComparisonOperator: alarm.ComparisonOperator,
-			Metric:             alarm.Metric,
-			Statistic:          alarm.Statistic,
-			Threshold:          alarm.Threshold,
-			Weight:             alarm.Weight,
-		}
-	}
-
-	return newAsp
-}
-
 func cloneInternalVirtualLink(oldIVL *InternalVirtualLink, vlrs []*VirtualLinkRecord) *InternalVirtualLink {
 	extID := ""
 	name := oldIVL.Name
@@ -293,19 +262,6 @@ func cloneInternalVirtualLink(oldIVL *InternalVirtualLink, vlrs []*VirtualLinkRe
 	}
 }
 
-func cloneVRFaultManagementPolicy(oldVRFMP *VRFaultManagementPolicy) *VRFaultManagementPolicy {
-	newVRFMP := new(VRFaultManagementPolicy)
-	*newVRFMP = *oldVRFMP
-
-	newVRFMP.Criteria = make([]*Criteria, len(oldVRFMP.Criteria))
-	for i, criteria := range oldVRFMP.Criteria {
-		newVRFMP.Criteria[i] = new(Criteria)
-		*newVRFMP.Criteria[i] = *criteria
-	}
-
-	return newVRFMP
-}
-
 func makeVDUFromParent(parentVDU *VirtualDeploymentUnit) *VirtualDeploymentUnit {
 	// copy all of the struct at once, and then deep clone the pointer/list parts
 	newVDU := new(VirtualDeploymentUnit)
@@ -361,7 +317,7 @@ func makeVDUFromParent(parentVDU *VirtualDeploymentUnit) *VirtualDeploymentUnit
 
 	if parentVDU.FaultManagementPolicies != nil {
 		for i, vrfmp := range parentVDU.FaultManagementPolicies {
-			newVDU.FaultManagementPolicies[i] = cloneVRFaultManagementPolicy(vrfmp)
+			newVDU.FaultManagementPolicies[i] = vrfmp.DeepCopy()
 		}
 	}
 
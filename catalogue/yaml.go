@@ -0,0 +1,373 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// The catalogue types are written in YAML with the names of their JSON fields, through their JSON encoding:
+// a YAML document is a valid descriptor or record if the equivalent JSON document is.
+// The scalars decoded into strings keep their YAML text, e.g. "version: 1.0" decodes to the version "1.0".
+
+// MarshalYAML returns the YAML encoding of v, with the names of the JSON fields in the order of the JSON encoding.
+func MarshalYAML(v interface{}) ([]byte, error) {
+	node, err := yamlNodeOf(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(node)
+}
+
+// UnmarshalYAML decodes the YAML document data into v, a pointer, matching the names of the JSON fields.
+func UnmarshalYAML(data []byte, v interface{}) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+
+	return decodeYAMLNode(&node, v)
+}
+
+// The main documents implement yaml.Marshaler and yaml.Unmarshaler, so they can be part of other YAML documents.
+
+func (vnfd VirtualNetworkFunctionDescriptor) MarshalYAML() (interface{}, error) {
+	return yamlNodeOf(&vnfd)
+}
+
+func (vnfd *VirtualNetworkFunctionDescriptor) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAMLNode(node, vnfd)
+}
+
+func (nsd NetworkServiceDescriptor) MarshalYAML() (interface{}, error) {
+	return yamlNodeOf(&nsd)
+}
+
+func (nsd *NetworkServiceDescriptor) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAMLNode(node, nsd)
+}
+
+func (vnfr VirtualNetworkFunctionRecord) MarshalYAML() (interface{}, error) {
+	return yamlNodeOf(&vnfr)
+}
+
+func (vnfr *VirtualNetworkFunctionRecord) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAMLNode(node, vnfr)
+}
+
+func (nsr NetworkServiceRecord) MarshalYAML() (interface{}, error) {
+	return yamlNodeOf(&nsr)
+}
+
+func (nsr *NetworkServiceRecord) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAMLNode(node, nsr)
+}
+
+// yamlNodeOf converts the JSON encoding of v into a YAML node, keeping the order of the fields.
+func yamlNodeOf(v interface{}) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return jsonToYAML(dec)
+}
+
+func jsonToYAML(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if t == '{' {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			value, err := jsonToYAML(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		// the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		if len(node.Content) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		return node, nil
+
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}, nil
+
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t)}, nil
+
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
+
+// decodeYAMLNode decodes node into v, a pointer, through its JSON encoding.
+func decodeYAMLNode(node *yaml.Node, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.Errorf("cannot decode YAML into a non-pointer %T", v)
+	}
+
+	var buf bytes.Buffer
+	if err := yamlToJSON(&buf, node, rv.Type().Elem()); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(buf.Bytes(), v)
+}
+
+// yamlToJSON writes node as JSON. t is the type the JSON is decoded into, nil if unknown: the scalars
+// decoded into strings are written as strings whatever their YAML type.
+func yamlToJSON(w *bytes.Buffer, node *yaml.Node, t reflect.Type) error {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t != nil && t.Kind() == reflect.Interface {
+		t = nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			w.WriteString("null")
+			return nil
+		}
+		return yamlToJSON(w, node.Content[0], t)
+
+	case yaml.AliasNode:
+		return yamlToJSON(w, node.Alias, t)
+
+	case yaml.MappingNode:
+		w.WriteByte('{')
+		pairs := mappingPairs(node)
+		for i := 0; i < len(pairs); i += 2 {
+			key, value := pairs[i], pairs[i+1]
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeJSONString(w, key.Value)
+			w.WriteByte(':')
+			if err := yamlToJSON(w, value, fieldType(t, key.Value)); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('}')
+		return nil
+
+	case yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		w.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			if err := yamlToJSON(w, item, elem); err != nil {
+				return err
+			}
+		}
+		w.WriteByte(']')
+		return nil
+	}
+
+	return scalarToJSON(w, node, t)
+}
+
+func scalarToJSON(w *bytes.Buffer, node *yaml.Node, t reflect.Type) error {
+	tag := node.ShortTag()
+	if tag == "!!null" {
+		w.WriteString("null")
+		return nil
+	}
+	if t != nil && t.Kind() == reflect.String {
+		writeJSONString(w, node.Value)
+		return nil
+	}
+
+	switch tag {
+	case "!!int":
+		var i int64
+		if err := node.Decode(&i); err != nil {
+			return err
+		}
+		w.WriteString(strconv.FormatInt(i, 10))
+	case "!!float":
+		var f float64
+		if err := node.Decode(&f); err != nil {
+			return err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return errors.Errorf("line %d: %s cannot be written in JSON", node.Line, node.Value)
+		}
+		w.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return err
+		}
+		w.WriteString(strconv.FormatBool(b))
+	default:
+		writeJSONString(w, node.Value)
+	}
+
+	return nil
+}
+
+// mappingPairs returns the keys and values of a mapping, with the ones of the mappings merged by "<<".
+func mappingPairs(node *yaml.Node) []*yaml.Node {
+	var pairs []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.ShortTag() != "!!merge" {
+			pairs = append(pairs, key, value)
+			continue
+		}
+
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		merged := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			merged = value.Content
+		}
+		for _, m := range merged {
+			if m.Kind == yaml.AliasNode {
+				m = m.Alias
+			}
+			if m.Kind == yaml.MappingNode {
+				pairs = append(pairs, mappingPairs(m)...)
+			}
+		}
+	}
+
+	return pairs
+}
+
+func writeJSONString(w io.Writer, s string) {
+	data, _ := json.Marshal(s)
+	w.Write(data)
+}
+
+// fieldType returns the type of the value of key in a JSON object decoded into t, nil if unknown.
+func fieldType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		fields := jsonFieldsOf(t)
+		if ft, ok := fields[key]; ok {
+			return ft
+		}
+		// as encoding/json, match the names case-insensitively
+		for name, ft := range fields {
+			if strings.EqualFold(name, key) {
+				return ft
+			}
+		}
+	}
+
+	return nil
+}
+
+var jsonFieldsCache sync.Map
+
+// jsonFieldsOf returns the types of the fields of the struct type t by JSON name, with the fields of
+// the embedded structs.
+func jsonFieldsOf(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.(map[string]reflect.Type)
+	}
+
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for embedded, et := range jsonFieldsOf(ft) {
+				if _, ok := fields[embedded]; !ok {
+					fields[embedded] = et
+				}
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+
+	jsonFieldsCache.Store(t, fields)
+
+	return fields
+}
@@ -0,0 +1,286 @@
+/*
+ *  Copyright (c) 2017 Open Baton (http://openbaton.org)
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package catalogue
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	tests := []struct {
+		fixture string
+		value   func() interface{}
+	}{
+		{"nsd.json", func() interface{} { return new(NetworkServiceDescriptor) }},
+		{"nsr.json", func() interface{} { return new(NetworkServiceRecord) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			v := tt.value()
+			if err := json.Unmarshal(readFixture(t, tt.fixture), v); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := MarshalYAML(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again := tt.value()
+			if err := UnmarshalYAML(data, again); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, again) {
+				t.Errorf("got %s after a round trip through\n%s", again, data)
+			}
+
+			// yaml.v3 goes through the methods of the documents
+			data, err = yaml.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again = tt.value()
+			if err := yaml.Unmarshal(data, again); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, again) {
+				t.Errorf("got %s after a round trip with yaml.v3", again)
+			}
+		})
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	vnfd := &VirtualNetworkFunctionDescriptor{
+		Name:     "iperf-server",
+		Version:  "1.0",
+		Metadata: map[string]string{"b": "2", "a": "true"},
+		VDUs:     []*VirtualDeploymentUnit{},
+		Type:     "server",
+	}
+
+	data, err := MarshalYAML(vnfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fields in the order of the JSON encoding, the strings quoted where YAML would read another type
+	wants := []string{
+		"projectId: \"\"\nmetadata:\n    a: \"true\"\n    b: \"2\"\nname: iperf-server\n",
+		"version: \"1.0\"\n",
+		"vnffgd: null\n",
+		"vdu: []\n",
+		"type: server\n",
+		"cyclicDependency: false\n",
+	}
+	last := -1
+	for _, want := range wants {
+		i := strings.Index(string(data), want)
+		if i < 0 {
+			t.Errorf("got\n%s\nwant it to contain %q", data, want)
+			continue
+		}
+		if i < last {
+			t.Errorf("got %q out of order in\n%s", want, data)
+		}
+		last = i
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	vdu := func(name string, scaleInOut int, images ...string) *VirtualDeploymentUnit {
+		return &VirtualDeploymentUnit{Name: name, ScaleInOut: scaleInOut, VMImages: images}
+	}
+
+	tests := []struct {
+		name string
+		yaml string
+		want *VirtualNetworkFunctionDescriptor
+		err  string
+	}{
+		{
+			name: "scalars into strings",
+			yaml: `
+name: iperf-server
+version: 1.0
+vendor: 2017
+type: true
+metadata:
+  scale: 1.50
+  enabled: yes
+  empty: ~
+`,
+			want: &VirtualNetworkFunctionDescriptor{
+				Name:     "iperf-server",
+				Version:  "1.0",
+				Vendor:   "2017",
+				Type:     "true",
+				Metadata: map[string]string{"scale": "1.50", "enabled": "yes", "empty": ""},
+			},
+		},
+		{
+			name: "numbers and booleans",
+			yaml: `
+name: iperf-server
+hbVersion: 0x10
+shared: true
+cyclicDependency: false
+vdu:
+  - name: vdu1
+    scale_in_out: 2
+`,
+			want: &VirtualNetworkFunctionDescriptor{
+				Name:      "iperf-server",
+				HbVersion: 16,
+				Shared:    true,
+				VDUs:      []*VirtualDeploymentUnit{vdu("vdu1", 2)},
+			},
+		},
+		{
+			name: "case-insensitive names",
+			yaml: `
+Name: iperf-server
+VERSION: 1.0
+`,
+			want: &VirtualNetworkFunctionDescriptor{Name: "iperf-server", Version: "1.0"},
+		},
+		{
+			name: "anchors and merge keys",
+			yaml: `
+name: iperf-server
+vdu:
+  - &vdu
+    name: vdu1
+    scale_in_out: 2
+    vm_image: &images [ubuntu-16.04]
+  - <<: *vdu
+    name: vdu2
+  - <<: [{scale_in_out: 3}, *vdu]
+    name: vdu3
+    vm_image: *images
+`,
+			want: &VirtualNetworkFunctionDescriptor{
+				Name: "iperf-server",
+				VDUs: []*VirtualDeploymentUnit{
+					vdu("vdu1", 2, "ubuntu-16.04"),
+					vdu("vdu2", 2, "ubuntu-16.04"),
+					vdu("vdu3", 2, "ubuntu-16.04"),
+				},
+			},
+		},
+		{
+			name: "requires",
+			yaml: `
+name: iperf-client
+requires:
+  server:
+    parameters: [private, 1.0]
+`,
+			want: &VirtualNetworkFunctionDescriptor{
+				Name:     "iperf-client",
+				Requires: map[string]*RequiresParameters{"server": {Parameters: []string{"private", "1.0"}}},
+			},
+		},
+		{
+			name: "empty document",
+			yaml: "",
+			want: &VirtualNetworkFunctionDescriptor{},
+		},
+		{
+			name: "invalid YAML",
+			yaml: "name: [iperf",
+			err:  "yaml",
+		},
+		{
+			name: "string into an integer",
+			yaml: "hbVersion: many",
+			err:  "hbVersion",
+		},
+		{
+			name: "infinite number",
+			yaml: "hbVersion: .inf",
+			err:  "cannot be written in JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &VirtualNetworkFunctionDescriptor{}
+			err := UnmarshalYAML([]byte(tt.yaml), got)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got the error %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				g, _ := json.Marshal(got)
+				w, _ := json.Marshal(tt.want)
+				t.Errorf("got %s, want %s", g, w)
+			}
+		})
+	}
+}
+
+func TestUnmarshalYAMLNotPointer(t *testing.T) {
+	var vnfd VirtualNetworkFunctionDescriptor
+	var nilVNFD *VirtualNetworkFunctionDescriptor
+	for _, v := range []interface{}{vnfd, nilVNFD} {
+		if err := UnmarshalYAML([]byte("name: iperf"), v); err == nil {
+			t.Errorf("UnmarshalYAML(%T) = nil, want an error", v)
+		}
+	}
+}
+
+func TestYAMLEmbedded(t *testing.T) {
+	type document struct {
+		Records []*VirtualNetworkFunctionRecord `yaml:"records"`
+	}
+
+	data := []byte(`
+records:
+  - name: iperf-server
+    version: 1.0
+    status: ACTIVE
+    lifecycle_event_history:
+      - event: INSTANTIATE
+        executedAt: 2017-12-05
+`)
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*VirtualNetworkFunctionRecord{{
+		Name:                  "iperf-server",
+		Version:               "1.0",
+		Status:                StatusActive,
+		LifecycleEventHistory: []*HistoryLifecycleEvent{{Event: "INSTANTIATE", ExecutedAt: "2017-12-05"}},
+	}}
+	if !reflect.DeepEqual(doc.Records, want) {
+		out, _ := json.Marshal(doc.Records)
+		t.Errorf("got %s", out)
+	}
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/openbaton/go-openbaton/catalogue/messages"
+	"github.com/openbaton/go-openbaton/sdk"
+)
+
+//How often tail -f checks the capture file for new records
+const tailPollInterval = 500 * time.Millisecond
+
+//The endpoint of a VNFM as registered by vnfmsdk, the endpoint defaults to the type
+func vnfmEndpoint(args []string) (*catalogue.Endpoint, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("expected <type> [endpoint]")
+	}
+	endpoint := &catalogue.Endpoint{
+		Type:         args[0],
+		Endpoint:     args[0],
+		Active:       true,
+		Enabled:      true,
+		EndpointType: "RABBIT",
+	}
+	if len(args) == 2 {
+		endpoint.Endpoint = args[1]
+	}
+	return endpoint, nil
+}
+
+//The queue of a plugin as registered by pluginsdk
+func pluginId(typ, name string) string {
+	return fmt.Sprintf("vim-drivers.%s.%s", typ, name)
+}
+
+func register(cfg *config, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("expected vnfm or plugin")
+	}
+	o := cfg.options()
+	var creds *catalogue.ManagerCredentials
+	var err error
+	switch args[0] {
+	case "vnfm":
+		endpoint, err := vnfmEndpoint(args[1:])
+		if err != nil {
+			return err
+		}
+		creds, err = sdk.RegisterVnfm(endpoint, o)
+	case "plugin":
+		if len(args) != 3 {
+			return fmt.Errorf("expected plugin <type> <name>")
+		}
+		creds, err = sdk.RegisterPlugin(pluginId(args[1], args[2]), o)
+	default:
+		return fmt.Errorf("unknown manager kind %s", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	return printJSON(creds)
+}
+
+//The credentials of the manager to unregister, from the cache or the configuration
+func managerCredentials(cfg *config) (*catalogue.ManagerCredentials, error) {
+	if cfg.CredentialsFile != "" {
+		cached, err := sdk.ReadCredentialsCache(cfg.CredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		return cached.Credentials, nil
+	}
+	if cfg.ManagerUsername == "" {
+		return nil, fmt.Errorf("either credentialsFile or managerUsername and managerPassword are required")
+	}
+	return &catalogue.ManagerCredentials{RabbitUsername: cfg.ManagerUsername, RabbitPassword: cfg.ManagerPassword}, nil
+}
+
+func unregister(cfg *config, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("expected vnfm or plugin")
+	}
+	creds, err := managerCredentials(cfg)
+	if err != nil {
+		return err
+	}
+	o := cfg.options()
+	switch args[0] {
+	case "vnfm":
+		endpoint, err := vnfmEndpoint(args[1:])
+		if err != nil {
+			return err
+		}
+		return sdk.UnregisterVnfm(endpoint, creds, o)
+	case "plugin":
+		if len(args) != 3 {
+			return fmt.Errorf("expected plugin <type> <name>")
+		}
+		return sdk.UnregisterPlugin(args[1], creds, o)
+	default:
+		return fmt.Errorf("unknown manager kind %s", args[0])
+	}
+}
+
+func credentials(cfg *config, args []string) error {
+	fs := flag.NewFlagSet("credentials", flag.ContinueOnError)
+	reveal := fs.Bool("reveal", false, "print the password too")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cfg.CredentialsFile == "" {
+		return fmt.Errorf("credentialsFile is required")
+	}
+	cached, err := sdk.ReadCredentialsCache(cfg.CredentialsFile)
+	if err != nil {
+		return err
+	}
+	if *reveal {
+		return printJSON(cached)
+	}
+	masked, err := sdk.MaskedConfig(cached)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(masked))
+	return nil
+}
+
+//Read the JSON in the file, stdin if "-", def if no file is given
+func readJSON(args []string, def string) ([]byte, error) {
+	if len(args) == 0 {
+		return []byte(def), nil
+	}
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s is not valid JSON", args[0])
+	}
+	return data, nil
+}
+
+func send(cfg *config, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("expected vnfm or plugin")
+	}
+	var queue string
+	var request json.RawMessage
+	switch args[0] {
+	case "vnfm":
+		if len(args) < 3 || len(args) > 4 {
+			return fmt.Errorf("expected vnfm <endpoint> <action> [file|-]")
+		}
+		queue = args[1]
+		content, err := readJSON(args[3:], "{}")
+		if err != nil {
+			return err
+		}
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(content, &fields); err != nil {
+			return fmt.Errorf("the content must be a JSON object: %v", err)
+		}
+		fields["action"], _ = json.Marshal(strings.ToUpper(args[2]))
+		if request, err = json.Marshal(fields); err != nil {
+			return err
+		}
+		//reject the messages the VNFM could not decode either
+		if _, err := messages.Unmarshal(request, messages.NFVO); err != nil {
+			return fmt.Errorf("invalid %s message: %v", args[2], err)
+		}
+	case "plugin":
+		if len(args) < 4 || len(args) > 5 {
+			return fmt.Errorf("expected plugin <type> <name> <method> [file|-]")
+		}
+		queue = pluginId(args[1], args[2])
+		params, err := readJSON(args[4:], "[]")
+		if err != nil {
+			return err
+		}
+		var parameters []json.RawMessage
+		if err := json.Unmarshal(params, &parameters); err != nil {
+			return fmt.Errorf("the parameters must be a JSON array: %v", err)
+		}
+		request, err = json.Marshal(map[string]interface{}{"methodName": args[3], "parameters": parameters})
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown manager kind %s", args[0])
+	}
+
+	o := cfg.options()
+	conn, err := sdk.Dial(o)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
+	reply, err := sdk.RpcContext(ctx, queue, request, conn, o.Logger)
+	if err != nil {
+		return err
+	}
+	return printReply(reply)
+}
+
+//Print the reply indented, failing if it is an error
+func printReply(reply []byte) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, reply, "", "  "); err != nil {
+		fmt.Println(string(reply))
+		return nil
+	}
+	fmt.Println(out.String())
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(reply, &fields) != nil {
+		return nil
+	}
+	if _, ok := fields["exception"]; ok {
+		return fmt.Errorf("the manager replied with an exception")
+	}
+	if action, ok := fields["action"]; ok && string(action) == `"`+string(catalogue.ActionError)+`"` {
+		return fmt.Errorf("the manager replied with an error")
+	}
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func tail(cfg *config, args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	follow := fs.Bool("f", false, "wait for new messages")
+	body := fs.Bool("body", false, "print the body of the messages")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected <capture file>")
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var line []byte
+	for {
+		chunk, err := r.ReadBytes('\n')
+		line = append(line, chunk...)
+		if err == io.EOF {
+			if !*follow {
+				return nil
+			}
+			//wait for the rest of a partially written record
+			time.Sleep(tailPollInterval)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		printRecord(line, *body)
+		line = nil
+	}
+}
+
+func printRecord(line []byte, body bool) {
+	var r sdk.CaptureRecord
+	if err := json.Unmarshal(line, &r); err != nil {
+		fmt.Fprintf(os.Stderr, "skipping invalid record: %v\n", err)
+		return
+	}
+	fmt.Printf("%s %-7s %s %s %s", r.Timestamp.Format(time.RFC3339Nano), r.Direction, r.Queue, r.Action, r.CorrelationID)
+	if r.Error != "" {
+		fmt.Printf(" error: %s", r.Error)
+	}
+	fmt.Println()
+	if !body {
+		return
+	}
+	var out bytes.Buffer
+	if r.Body != nil && json.Indent(&out, r.Body, "  ", "  ") == nil {
+		fmt.Printf("  %s\n", out.String())
+	} else {
+		fmt.Printf("  %q\n", r.Message())
+	}
+}
@@ -0,0 +1,108 @@
+//Command line client for the operations on the Open Baton managers:
+//registration, requests to a VNFM or a plugin and inspection of the captured traffic.
+//
+//	go-openbaton [flags] register vnfm <type> [endpoint] | register plugin <type> <name>
+//	go-openbaton [flags] unregister vnfm <type> [endpoint] | unregister plugin <type> <name>
+//	go-openbaton [flags] credentials [-reveal]
+//	go-openbaton [flags] send vnfm <endpoint> <action> [content.json|-]
+//	go-openbaton [flags] send plugin <type> <name> <method> [parameters.json|-]
+//	go-openbaton tail [-f] [-body] <capture file>
+//	go-openbaton scaffold [-module path] [-type type] [-force] vnfm|plugin <directory>
+//
+//The flags can also be given in a configuration file (-config) or as OPENBATON_* environment variables,
+//run go-openbaton -help to list them.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openbaton/go-openbaton/sdk"
+)
+
+//The settings of the client
+type config struct {
+	BrokerIp   string `toml:"brokerIp"`
+	BrokerPort int    `toml:"brokerPort"`
+	//User registering the managers and sending the requests
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+	//Seconds to wait for the broker and for the replies
+	Timeout  int    `toml:"timeout"`
+	LogLevel string `toml:"logLevel"`
+	//Credentials cache of the manager, written by register and read by unregister and credentials
+	CredentialsFile string `toml:"credentialsFile"`
+	//Credentials of the manager to unregister, if not cached
+	ManagerUsername string `toml:"managerUsername"`
+	ManagerPassword string `toml:"managerPassword"`
+}
+
+func (cfg *config) options() *sdk.Options {
+	return sdk.NewOptions(
+		sdk.WithBroker(cfg.BrokerIp, cfg.BrokerPort),
+		sdk.WithCredentials(cfg.Username, cfg.Password),
+		sdk.WithTimeout(cfg.Timeout),
+		//the output of the commands goes to stdout
+		sdk.WithLogConfig(sdk.LogConfig{Level: cfg.LogLevel, Output: os.Stderr}),
+		sdk.WithCredentialsCache(cfg.CredentialsFile),
+	)
+}
+
+type command func(cfg *config, args []string) error
+
+var commands = map[string]command{
+	"register":    register,
+	"unregister":  unregister,
+	"credentials": credentials,
+	"send":        send,
+	"tail":        tail,
+	"scaffold":    scaffold,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: go-openbaton [flags] <command> [arguments]
+
+commands:
+  register vnfm <type> [endpoint]           register a VNFM and print its credentials
+  register plugin <type> <name>             register a plugin and print its credentials
+  unregister vnfm <type> [endpoint]         unregister a VNFM
+  unregister plugin <type> <name>           unregister a plugin
+  credentials [-reveal]                     print the cached credentials
+  send vnfm <endpoint> <action> [file|-]    send an action with the content in file to a VNFM
+  send plugin <type> <name> <method> [file|-]
+                                            call a method with the parameters in file on a plugin
+  tail [-f] [-body] <capture file>          print the messages captured by a manager
+  scaffold [-module path] [-type type] [-force] vnfm|plugin <directory>
+                                            create a VNFM or plugin project, or stub the methods it lacks`)
+}
+
+func main() {
+	cfg := &config{
+		BrokerIp:   "localhost",
+		BrokerPort: 5672,
+		Username:   "openbaton-manager-user",
+		Password:   "openbaton",
+		Timeout:    10,
+		LogLevel:   "WARNING",
+	}
+	args, err := sdk.LoadConfigArgs(cfg, "", os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		usage()
+		os.Exit(2)
+	}
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %s\n", args[0])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd(cfg, args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+}
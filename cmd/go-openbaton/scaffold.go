@@ -0,0 +1,549 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/openbaton/go-openbaton/pluginsdk"
+	"github.com/openbaton/go-openbaton/vnfmsdk"
+)
+
+//File of the stubs of the methods added to the interface after the project was generated, rewritten at every run
+const stubsFile = "handler_gen.go"
+
+//What differs between a VNFM and a plugin project
+type scaffoldKind struct {
+	//The handler interface, whose methods are stubbed
+	iface reflect.Type
+	//e.g. "vnfmsdk.HandlerVnfm"
+	ifaceName string
+	config    interface{}
+	main      string
+	test      string
+}
+
+var scaffoldKinds = map[string]*scaffoldKind{
+	"vnfm": {
+		iface:     reflect.TypeOf((*vnfmsdk.HandlerVnfm)(nil)).Elem(),
+		ifaceName: "vnfmsdk.HandlerVnfm",
+		config:    vnfmsdk.DefaultConfig(),
+		main:      vnfmMainTemplate,
+		test:      vnfmTestTemplate,
+	},
+	"plugin": {
+		iface:     reflect.TypeOf((*pluginsdk.HandlerVim)(nil)).Elem(),
+		ifaceName: "pluginsdk.HandlerVim",
+		config:    pluginsdk.DefaultConfig(),
+		main:      pluginMainTemplate,
+		test:      pluginTestTemplate,
+	},
+}
+
+//The values of the templates
+type scaffoldData struct {
+	Type      string
+	Module    string
+	Interface string
+	//Of the methods to stub
+	Imports []string
+	Methods []stubMethod
+}
+
+type stubMethod struct {
+	Name    string
+	Params  string
+	Results string
+	Return  string
+}
+
+//Create a VNFM or plugin project in dir. The existing files are kept unless -force is given,
+//the methods missing from the handler are stubbed in handler_gen.go.
+func scaffold(cfg *config, args []string) error {
+	fs := flag.NewFlagSet("scaffold", flag.ContinueOnError)
+	module := fs.String("module", "", "module path of the project, the directory name by default")
+	typ := fs.String("type", "", "type of the manager, the directory name by default")
+	force := fs.Bool("force", false, "overwrite the existing files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("expected vnfm|plugin <directory>")
+	}
+	kind, ok := scaffoldKinds[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unknown manager kind %s", fs.Arg(0))
+	}
+	dir := fs.Arg(1)
+	base := filepath.Base(dir)
+	data := &scaffoldData{
+		Type:      *typ,
+		Module:    *module,
+		Interface: kind.ifaceName,
+	}
+	if data.Type == "" {
+		data.Type = base
+	}
+	if data.Module == "" {
+		data.Module = base
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	implemented, err := handlerMethods(dir)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(filepath.Join(dir, "handler.go"))
+	newHandler := os.IsNotExist(err) || *force
+	if newHandler {
+		implemented = nil
+	}
+	data.Methods, data.Imports = stubs(kind.iface, implemented)
+
+	configFile, err := tomlConfig(kind.config, data.Type)
+	if err != nil {
+		return err
+	}
+	files := []struct {
+		name     string
+		template string
+	}{
+		{"go.mod", goModTemplate},
+		{"main.go", kind.main},
+		{"handler_test.go", kind.test},
+		{"Dockerfile", dockerfileTemplate},
+	}
+	for _, f := range files {
+		content, err := render(f.name, f.template, data)
+		if err != nil {
+			return err
+		}
+		if err := writeScaffoldFile(dir, f.name, content, *force); err != nil {
+			return err
+		}
+	}
+	if err := writeScaffoldFile(dir, "config.toml", configFile, *force); err != nil {
+		return err
+	}
+
+	stubsPath := filepath.Join(dir, stubsFile)
+	switch {
+	case newHandler:
+		content, err := render("handler.go", handlerTemplate, data)
+		if err != nil {
+			return err
+		}
+		if err := writeScaffoldFile(dir, "handler.go", content, true); err != nil {
+			return err
+		}
+		return removeIfExists(stubsPath)
+	case len(data.Methods) == 0:
+		return removeIfExists(stubsPath)
+	default:
+		content, err := render(stubsFile, stubsTemplate, data)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: stubbed %d new methods\n", stubsPath, len(data.Methods))
+		return os.WriteFile(stubsPath, content, 0644)
+	}
+}
+
+//The names of the methods of Handler declared in the go files of dir, but the generated stubs
+func handlerMethods(dir string) (map[string]bool, error) {
+	methods := make(map[string]bool)
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return info.Name() != stubsFile && !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+					continue
+				}
+				recv := fn.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); ok && ident.Name == "Handler" {
+					methods[fn.Name.Name] = true
+				}
+			}
+		}
+	}
+	return methods, nil
+}
+
+//The stubs of the methods of iface not implemented, and the imports they need
+func stubs(iface reflect.Type, implemented map[string]bool) ([]stubMethod, []string) {
+	imports := make(map[string]bool)
+	var methods []stubMethod
+	for i := 0; i < iface.NumMethod(); i++ {
+		m := iface.Method(i)
+		if implemented[m.Name] {
+			continue
+		}
+		var params, results, values []string
+		used := make(map[string]int)
+		for j := 0; j < m.Type.NumIn(); j++ {
+			t := m.Type.In(j)
+			name := paramName(t)
+			if used[name]++; used[name] > 1 {
+				name = fmt.Sprintf("%s%d", name, used[name])
+			}
+			params = append(params, name+" "+typeString(t, imports))
+		}
+		for j := 0; j < m.Type.NumOut(); j++ {
+			t := m.Type.Out(j)
+			results = append(results, typeString(t, imports))
+			values = append(values, zeroValue(t, imports))
+		}
+		stub := stubMethod{Name: m.Name, Params: strings.Join(params, ", ")}
+		switch len(results) {
+		case 0:
+		case 1:
+			stub.Results = " " + results[0]
+		default:
+			stub.Results = " (" + strings.Join(results, ", ") + ")"
+		}
+		if len(values) > 0 {
+			stub.Return = "return " + strings.Join(values, ", ")
+		}
+		methods = append(methods, stub)
+	}
+	var paths []string
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return methods, paths
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+//The type as written in the source, recording the packages to import
+func typeString(t reflect.Type, imports map[string]bool) string {
+	if t == errorType {
+		return "error"
+	}
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		imports[t.PkgPath()] = true
+		return path.Base(t.PkgPath()) + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeString(t.Elem(), imports)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && t.Elem().Name() == "uint8" {
+			return "[]byte"
+		}
+		return "[]" + typeString(t.Elem(), imports)
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), typeString(t.Elem(), imports))
+	case reflect.Map:
+		return "map[" + typeString(t.Key(), imports) + "]" + typeString(t.Elem(), imports)
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}"
+		}
+	}
+	return t.String()
+}
+
+func zeroValue(t reflect.Type, imports map[string]bool) string {
+	if t == errorType {
+		return "errNotImplemented"
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+		return "nil"
+	case reflect.String:
+		return `""`
+	case reflect.Bool:
+		return "false"
+	case reflect.Struct, reflect.Array:
+		return typeString(t, imports) + "{}"
+	}
+	return "0"
+}
+
+//A parameter name derived from its type, e.g. vnfr for *catalogue.VirtualNetworkFunctionRecord
+//and vnfcInstance for *catalogue.VNFCInstance
+func paramName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t.Name() == "VirtualNetworkFunctionRecord":
+		return "vnfr"
+	case t.Name() != "" && t.PkgPath() != "":
+		//Lower the leading run of capitals but the one starting the next word, e.g. vnfcInstance for VNFCInstance
+		runes := []rune(strings.TrimSuffix(t.Name(), "Int"))
+		n := 0
+		for n < len(runes) && unicode.IsUpper(runes[n]) {
+			n++
+		}
+		if n > 1 && n < len(runes) {
+			n--
+		}
+		for i := 0; i < n; i++ {
+			runes[i] = unicode.ToLower(runes[i])
+		}
+		return string(runes)
+	case t.Kind() == reflect.Interface:
+		return "arg"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "data"
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Map:
+		return "values"
+	case t.Kind() == reflect.String:
+		return "s"
+	}
+	return "v"
+}
+
+//The configuration with the defaults of the manager, every field set
+func tomlConfig(defaults interface{}, typ string) ([]byte, error) {
+	cfg := reflect.New(reflect.TypeOf(defaults))
+	cfg.Elem().Set(reflect.ValueOf(defaults))
+	cfg.Elem().FieldByName("Type").SetString(typ)
+	if f := cfg.Elem().FieldByName("Endpoint"); f.IsValid() {
+		f.SetString(typ)
+	}
+	if f := cfg.Elem().FieldByName("LogLevels"); f.IsValid() {
+		f.Set(reflect.ValueOf(map[string]string{}))
+	}
+	var buf bytes.Buffer
+	buf.WriteString("# Every setting can be overridden by an OPENBATON_* environment variable, e.g. OPENBATON_BROKER_IP\n")
+	if err := toml.NewEncoder(&buf).Encode(cfg.Interface()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func render(name, text string, data *scaffoldData) ([]byte, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(name, ".go") {
+		return buf.Bytes(), nil
+	}
+	return format.Source(buf.Bytes())
+}
+
+func writeScaffoldFile(dir, name string, content []byte, force bool) error {
+	p := filepath.Join(dir, name)
+	if _, err := os.Stat(p); err == nil && !force {
+		fmt.Printf("%s: exists, kept\n", p)
+		return nil
+	}
+	fmt.Printf("%s: written\n", p)
+	return os.WriteFile(p, content, 0644)
+}
+
+func removeIfExists(p string) error {
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+const goModTemplate = `module {{.Module}}
+
+go 1.21
+`
+
+const handlerTemplate = `package main
+
+import (
+	"errors"
+
+{{range .Imports}}	"{{.}}"
+{{end}}	"github.com/openbaton/go-openbaton/sdk"
+)
+
+var errNotImplemented = errors.New("not implemented")
+
+// Handler handles the requests of the NFVO, implements {{.Interface}}
+type Handler struct {
+	// Keeps the state across the requests
+	store sdk.Store
+}
+
+func NewHandler(store sdk.Store) *Handler {
+	return &Handler{store: store}
+}
+{{range .Methods}}
+func (h *Handler) {{.Name}}({{.Params}}){{.Results}} {
+	{{.Return}}
+}
+{{end}}`
+
+const stubsTemplate = `// Code generated by go-openbaton scaffold. DO NOT EDIT.
+// Move the methods to handler.go before implementing them, this file is rewritten at every scaffold.
+
+package main
+{{if .Imports}}
+import (
+{{range .Imports}}	"{{.}}"
+{{end}})
+{{end}}{{range .Methods}}
+func (h *Handler) {{.Name}}({{.Params}}){{.Results}} {
+	{{.Return}}
+}
+{{end}}`
+
+const vnfmMainTemplate = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openbaton/go-openbaton/sdk"
+	"github.com/openbaton/go-openbaton/vnfmsdk"
+)
+
+func main() {
+	confPath := "config.toml"
+	if len(os.Args) > 1 {
+		confPath = os.Args[1]
+	}
+	store := sdk.NewMemoryStore()
+	if err := vnfmsdk.Start(confPath, NewHandler(store), "{{.Type}}", sdk.WithStore(store)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+const pluginMainTemplate = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/openbaton/go-openbaton/pluginsdk"
+	"github.com/openbaton/go-openbaton/sdk"
+)
+
+func main() {
+	confPath := "config.toml"
+	if len(os.Args) > 1 {
+		confPath = os.Args[1]
+	}
+	store := sdk.NewMemoryStore()
+	err := pluginsdk.Start(confPath, NewHandler(store), "{{.Type}}", catalogue.BaseNetwork{}, catalogue.BaseNfvImage{}, sdk.WithStore(store))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+const vnfmTestTemplate = `package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/openbaton/go-openbaton/sdk"
+	"github.com/openbaton/go-openbaton/vnfmsdk"
+)
+
+var _ vnfmsdk.HandlerVnfm = (*Handler)(nil)
+
+func TestCheckInstantiationFeasibility(t *testing.T) {
+	h := NewHandler(sdk.NewMemoryStore())
+	if err := h.CheckInstantiationFeasibility(); err != nil {
+		t.Skipf("not implemented yet: %v", err)
+	}
+}
+
+// Replay the messages captured with captureFile and copied to testdata, e.g. to reproduce a failure
+func TestReplay(t *testing.T) {
+	captures, _ := filepath.Glob(filepath.Join("testdata", "*.jsonl"))
+	for _, path := range captures {
+		results, err := vnfmsdk.Replay(context.Background(), path, NewHandler(sdk.NewMemoryStore()), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				t.Errorf("%s: %s %s: %v", path, r.Request.Action, r.Request.CorrelationID, r.Err)
+			}
+		}
+	}
+}
+`
+
+const pluginTestTemplate = `package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/openbaton/go-openbaton/pluginsdk"
+	"github.com/openbaton/go-openbaton/sdk"
+)
+
+var _ pluginsdk.HandlerVim = (*Handler)(nil)
+
+// Replay the messages captured with captureFile and copied to testdata, e.g. to reproduce a failure
+func TestReplay(t *testing.T) {
+	captures, _ := filepath.Glob(filepath.Join("testdata", "*.jsonl"))
+	for _, path := range captures {
+		h := NewHandler(sdk.NewMemoryStore())
+		results, err := pluginsdk.Replay(context.Background(), path, h, catalogue.BaseNetwork{}, catalogue.BaseNfvImage{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				t.Errorf("%s: %s %s: %v", path, r.Request.Action, r.Request.CorrelationID, r.Err)
+			}
+		}
+	}
+}
+`
+
+const dockerfileTemplate = `FROM golang:1.21 AS build
+WORKDIR /src
+COPY . .
+RUN go mod tidy && CGO_ENABLED=0 go build -o /{{.Type}} .
+
+FROM alpine
+COPY --from=build /{{.Type}} /usr/local/bin/{{.Type}}
+COPY config.toml /etc/openbaton/{{.Type}}.toml
+ENTRYPOINT ["/usr/local/bin/{{.Type}}", "/etc/openbaton/{{.Type}}.toml"]
+`
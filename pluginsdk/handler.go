@@ -1,6 +1,7 @@
 package pluginsdk
 
 import (
+	"context"
 	"errors"
 	"encoding/json"
 	"github.com/openbaton/go-openbaton/sdk"
@@ -9,17 +10,22 @@ import (
 )
 
 //Handler function for the Plugins to be passed to the sdk package
-func handlePluginRequest(bytemsg []byte, handler sdk.Handler, allocate bool, connection *amqp.Connection, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) ([]byte, error) {
+func handlePluginRequest(ctx context.Context, bytemsg []byte, handler sdk.Handler, allocate bool, connection *amqp.Connection, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) ([]byte, error) {
 	var req request
-	logger := sdk.GetLogger("handler-plugin-function", "DEBUG")
+	logger := sdk.LoggerFromContext(ctx).Module("handler")
 	if err := json.Unmarshal(bytemsg, &req); err != nil {
 		logger.Error("message unmarshaling error")
 		return nil, errors.New("message unmarshaling error")
 	}
+	sdk.SetOperation(ctx, req.MethodName)
+	sdk.AddLogFields(ctx, sdk.LogFieldMethod, req.MethodName)
+	logger = sdk.LoggerFromContext(ctx).Module("handler")
 
 	switch h := handler.(type) {
-	case HandlerVim:
+	case HandlerVim, HandlerVimV2:
+		spanCtx, span := sdk.StartSpan(ctx, "HandlerVim."+req.MethodName)
 		wk := &worker{
+			ctx: spanCtx,
 			l: logger,
 			h: h,
 			networkType:net,
@@ -28,6 +34,8 @@ func handlePluginRequest(bytemsg []byte, handler sdk.Handler, allocate bool, con
 		result, err := wk.handle(req.MethodName, req.Parameters)
 		var resp response
 		if err != nil {
+			sdk.RecordError(span, err)
+			sdk.ReportError(ctx)
 			switch err.(type) {
 
 			case plugError:
@@ -40,6 +48,7 @@ func handlePluginRequest(bytemsg []byte, handler sdk.Handler, allocate bool, con
 		} else {
 			resp.Answer = result
 		}
+		span.End()
 
 		bResp, err := json.MarshalIndent(resp,"","  ")
 		if err != nil {
@@ -54,3 +63,8 @@ func handlePluginRequest(bytemsg []byte, handler sdk.Handler, allocate bool, con
 	}
 
 }
+
+//Reply to a request the handler function failed on, e.g. because it could not be unmarshaled
+func errorReply(body []byte, err error) ([]byte, error) {
+	return json.Marshal(response{Exception: plugError{err.Error()}})
+}
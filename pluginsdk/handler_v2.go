@@ -0,0 +1,105 @@
+package pluginsdk
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openbaton/go-openbaton/sdk"
+)
+
+//The first version of the handler interface, every method must be implemented
+type HandlerVimV1 = HandlerVim
+
+//The second version of the handler interface: only Type and Refresh are mandatory,
+//the plugin implements the other methods of HandlerVim it supports, with the same signature or taking the context
+//of the request first, e.g. ListServer(ctx context.Context, vimInstance interface{}) ([]*catalogue.Server, error).
+//The calls to the methods not implemented are answered with an error, see Methods.
+type HandlerVimV2 interface {
+	Type(ctx context.Context, vimInstance interface{}) (string, error)
+
+	Refresh(ctx context.Context, vimInstance interface{}) (interface{}, error)
+}
+
+//The methods of the handler by the name used by the NFVO, the first one unless the call matches an overload
+var methodNames = map[string][]string{
+	"addFlavor":               {"AddFlavour"},
+	"addImage":                {"AddImage", "AddImageFromURL"},
+	"copyImage":               {"CopyImage"},
+	"createNetwork":           {"CreateNetwork"},
+	"createSubnet":            {"CreateSubnet"},
+	"deleteFlavor":            {"DeleteFlavour"},
+	"deleteImage":             {"DeleteImage"},
+	"deleteNetwork":           {"DeleteNetwork"},
+	"deleteServerByIdAndWait": {"DeleteServerByIDAndWait"},
+	"deleteSubnet":            {"DeleteSubnet"},
+	"getNetworkById":          {"NetworkByID"},
+	"getQuota":                {"Quota"},
+	"getSubnetsExtIds":        {"SubnetsExtIDs"},
+	"getType":                 {"Type"},
+	"launchInstance":          {"LaunchInstance"},
+	"launchInstanceAndWait":   {"LaunchInstanceAndWait", "LaunchInstanceAndWaitWithIPs"},
+	"listFlavors":             {"ListFlavours"},
+	"listImages":              {"ListImages"},
+	"listNetworks":            {"ListNetworks"},
+	"listServer":              {"ListServer"},
+	"rebuildServer":           {"RebuildServer"},
+	"refresh":                 {"Refresh"},
+	"updateFlavor":            {"UpdateFlavour"},
+	"updateImage":             {"UpdateImage"},
+	"updateNetwork":           {"UpdateNetwork"},
+	"updateSubnet":            {"UpdateSubnet"},
+}
+
+var (
+	handlerVimType = reflect.TypeOf((*HandlerVim)(nil)).Elem()
+	contextType    = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+//Number of arguments sent by the NFVO to a method of HandlerVim
+func vimMethodArgs(name string) int {
+	m, ok := handlerVimType.MethodByName(name)
+	if !ok {
+		return -1
+	}
+	return m.Type.NumIn()
+}
+
+//1 if the function takes the context of the request first, 0 otherwise
+func contextArgs(fType reflect.Type) int {
+	if fType.NumIn() > 0 && fType.In(0) == contextType {
+		return 1
+	}
+	return 0
+}
+
+//The methods of the NFVO (e.g. "listServer") h supports, sorted.
+//A method is supported if h implements one of its Go methods, with or without the context.
+func Methods(h sdk.Handler) []string {
+	v := reflect.ValueOf(h)
+	var methods []string
+	for fname, names := range methodNames {
+		for _, name := range names {
+			if v.MethodByName(name).IsValid() {
+				methods = append(methods, fname)
+				break
+			}
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+//Advertise the handler version and the supported methods in the metadata of the options, sent with the registration
+func setCapabilities(o *sdk.Options, h sdk.Handler) {
+	version := "2"
+	if _, ok := h.(HandlerVimV1); ok {
+		version = "1"
+	}
+	if o.Metadata == nil {
+		o.Metadata = make(map[string]string)
+	}
+	o.Metadata[sdk.MetadataHandlerVersion] = version
+	o.Metadata[sdk.MetadataCapabilities] = strings.Join(Methods(h), ",")
+}
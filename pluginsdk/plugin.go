@@ -2,14 +2,13 @@
 package pluginsdk
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
-	"os/signal"
+	"sync"
 
-	"github.com/BurntSushi/toml"
 	"github.com/openbaton/go-openbaton/catalogue"
 	"github.com/openbaton/go-openbaton/sdk"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // The Config struct for a plugin
@@ -22,11 +21,35 @@ type PluginConfig struct {
 	BrokerIp   string `toml:"brokerIp"`
 	BrokerPort int    `toml:"brokerPort"`
 	Timeout    int    `toml:"timeout"`
+	//Address of the http server exposing the Prometheus metrics (e.g. ":9090"), disabled if empty
+	MetricsAddress string `toml:"metricsAddress"`
+	//Address of the http server exposing the /healthz and /readyz probes, disabled if empty.
+	//Can be the same as MetricsAddress.
+	HealthAddress string `toml:"healthAddress"`
+	//Exporter of the traces: "none" (default) or "stdout"
+	TraceExporter string `toml:"traceExporter"`
+	//Level of the log records by module (the manager name, "handler", "rpc", "credentials"), overriding LogLevel
+	LogLevels map[string]string `toml:"logLevels"`
+	//Format of the log records: "text" (default) or "json"
+	LogFormat string `toml:"logFormat"`
+	//File caching the credentials returned by the registration, reused on restart if still valid. Disabled if empty.
+	CredentialsFile string `toml:"credentialsFile"`
+	//Seconds between the heartbeats published to heartbeatExchange with heartbeatRoutingKey, disabled if 0
+	HeartbeatInterval   int    `toml:"heartbeatInterval"`
+	HeartbeatExchange   string `toml:"heartbeatExchange"`
+	HeartbeatRoutingKey string `toml:"heartbeatRoutingKey"`
+	//Exchange receiving the undecodable requests, and the queue bound to it. Disabled if empty.
+	DeadLetterExchange string `toml:"deadLetterExchange"`
+	DeadLetterQueue    string `toml:"deadLetterQueue"`
+	//File the requests and replies are appended to, to replay them with Replay. Disabled if empty.
+	CaptureFile string `toml:"captureFile"`
+	//Max number of requests handled at the same time, unlimited if 0
+	MaxInFlight int `toml:"maxInFlight"`
 }
 
-// Start the plugin using the configuration file
-func Start(confPath string, h HandlerVim, name string, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) (error) {
-	cfg := PluginConfig{
+//The default plugin configuration
+func DefaultConfig() PluginConfig {
+	return PluginConfig{
 		Type:       "unknown",
 		Workers:    5,
 		Username:   "openbaton-manager-user",
@@ -36,25 +59,69 @@ func Start(confPath string, h HandlerVim, name string, net catalogue.BaseNetwork
 		BrokerPort: 5672,
 		Timeout:    2,
 	}
-	reader, err := os.Open(confPath)
-	defer reader.Close()
+}
+
+//Load the plugin configuration merging the defaults, the file at confPath (TOML, YAML or JSON),
+//the OPENBATON_* environment variables and the command line flags in args (e.g. os.Args[1:]), then validate it.
+//confPath can be empty and args nil.
+func LoadConfig(confPath string, args []string) (*PluginConfig, error) {
+	cfg := DefaultConfig()
+	if err := sdk.LoadConfig(&cfg, confPath, args); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+//Check the configuration, returning all the problems found in a *sdk.ConfigError, see sdk.Options.Validate
+func (cfg *PluginConfig) Validate() error {
+	return sdk.NewOptions(WithConfig(*cfg)).Validate()
+}
+
+// Start the plugin using the configuration file, reloaded on SIGHUP or when modified
+func Start(confPath string, h HandlerVim, name string, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt, opts ...sdk.Option) (error) {
+	cfg, err := LoadConfig(confPath, nil)
 	if err != nil {
 		return err
 	}
-	if _, err := toml.DecodeReader(reader, &cfg); err != nil {
+	return Run(*cfg, h, name, net, img, append([]sdk.Option{sdk.WithConfigFile(confPath)}, opts...)...)
+}
+
+//Start the plugin with a configuration, e.g. returned by LoadConfig
+func Run(cfg PluginConfig, h HandlerVim, name string, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt, opts ...sdk.Option) error {
+	if err := cfg.Validate(); err != nil {
 		return err
 	}
-
+	plugin, err := New(h, append([]sdk.Option{WithConfig(cfg), sdk.WithName(name), WithTypes(net, img)}, opts...)...)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: while loading config file %s: %v\n", confPath, err)
-		os.Exit(100)
+		return err
 	}
+	jsonCfg, err := sdk.MaskedConfig(cfg)
+	if err != nil {
+		return err
+	}
+	plugin.logger.Debugf("Config are %s", jsonCfg)
+	return plugin.Serve()
+}
 
-	return startWithCfg(cfg, h, name, net, img)
+//Feed the requests captured in the file at path (see sdk.WithCapture) to h, a HandlerVimV1 or HandlerVimV2,
+//in-process without broker, to reproduce a failure locally
+func Replay(ctx context.Context, path string, h sdk.Handler, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) ([]sdk.ReplayResult, error) {
+	records, err := sdk.ReadCaptureFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.Replay(ctx, records, func(ctx context.Context, body []byte) ([]byte, error) {
+		return handlePluginRequest(ctx, body, h, false, nil, net, img)
+	}), nil
 }
 
 // Start the plugin with specific configuration
-func StartWithConfig(typ, username, password, loglevel, brokerip string, workers, brokerPort, timeout int, h HandlerVim, name string, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) (error) {
+//
+//Deprecated: use New, whose options cannot be swapped silently.
+func StartWithConfig(typ, username, password, loglevel, brokerip string, workers, brokerPort, timeout int, h HandlerVim, name string, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt, opts ...sdk.Option) (error) {
 	cfg := PluginConfig{
 		Type:       typ,
 		Workers:    workers,
@@ -66,56 +133,160 @@ func StartWithConfig(typ, username, password, loglevel, brokerip string, workers
 		Timeout:    timeout,
 	}
 
-	return startWithCfg(cfg, h, name, net, img)
+	return Run(cfg, h, name, net, img, opts...)
+}
+
+//Apply all the settings of a configuration
+func WithConfig(cfg PluginConfig) sdk.Option {
+	return func(o *sdk.Options) {
+		o.Type = cfg.Type
+		o.Workers = cfg.Workers
+		o.Username = cfg.Username
+		o.Password = cfg.Password
+		o.BrokerIp = cfg.BrokerIp
+		o.BrokerPort = cfg.BrokerPort
+		o.Timeout = cfg.Timeout
+		o.MaxInFlight = cfg.MaxInFlight
+		o.MetricsAddress = cfg.MetricsAddress
+		o.HealthAddress = cfg.HealthAddress
+		o.TraceExporter = cfg.TraceExporter
+		o.CredentialsFile = cfg.CredentialsFile
+		o.HeartbeatInterval = cfg.HeartbeatInterval
+		o.HeartbeatExchange = cfg.HeartbeatExchange
+		o.HeartbeatRoutingKey = cfg.HeartbeatRoutingKey
+		o.DeadLetterExchange = cfg.DeadLetterExchange
+		o.DeadLetterQueue = cfg.DeadLetterQueue
+		o.CaptureFile = cfg.CaptureFile
+		o.LogConfig = sdk.LogConfig{Level: cfg.LogLevel, Levels: cfg.LogLevels, Format: cfg.LogFormat}
+	}
+}
+
+//Set the types the networks and images returned by the driver are decoded into
+func WithTypes(net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) sdk.Option {
+	return func(o *sdk.Options) {
+		o.Network = net
+		o.Image = img
+	}
 }
 
-func startWithCfg(cfg PluginConfig, h HandlerVim, name string, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) error {
-	pluginId := fmt.Sprintf("vim-drivers.%s.%s", cfg.Type, name)
-	logger := sdk.GetLogger(cfg.Type, cfg.LogLevel)
-	logger.Infof("Starting Plugin of type %s", cfg.Type)
-	jsonCfg, err := json.MarshalIndent(cfg, "", "  ")
+//A VIM driver plugin, registered to the NFVO by Serve
+type Plugin struct {
+	//A HandlerVimV1 or a HandlerVimV2
+	handler        sdk.Handler
+	options        *sdk.Options
+	logger         sdk.Logger
+	pluginId       string
+	tracerProvider trace.TracerProvider
+	stopTracing    func(context.Context) error
+	creds          *catalogue.ManagerCredentials
+	manager        *sdk.Manager
+	//Guards manager, set by Serve and read by the supervisor
+	mutex        sync.Mutex
+	shutdownOnce sync.Once
+}
+
+//Create a plugin handled by h, nothing is sent to the broker until Serve is called
+func New(h HandlerVim, opts ...sdk.Option) (*Plugin, error) {
+	return newPlugin(h, opts...)
+}
+
+//Create a plugin with a handler of the second version, implementing only the methods it supports
+func NewV2(h HandlerVimV2, opts ...sdk.Option) (*Plugin, error) {
+	return newPlugin(h, opts...)
+}
+
+func newPlugin(h sdk.Handler, opts ...sdk.Option) (*Plugin, error) {
+	options := sdk.NewOptions(opts...)
+	if options.ErrorReply == nil {
+		options.ErrorReply = errorReply
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if options.Network == nil || options.Image == nil {
+		return nil, sdk.NewSdkError("the network and image types are required, see WithTypes")
+	}
+	rootLogger, err := options.LoggerOrNew(options.LogConfig)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	options.Logger = rootLogger
+	setCapabilities(options, h)
+	tracerProvider, stopTracing, err := sdk.NewTracerProvider(options.TraceExporter)
+	if err != nil {
+		return nil, err
 	}
-	logger.Debugf("Config are %s", jsonCfg)
-	rabbitCredentials, err := sdk.GetPluginCreds(cfg.Username, cfg.Password, cfg.BrokerIp, cfg.BrokerPort, cfg.Timeout, pluginId, "DEBUG")
+	return &Plugin{
+		handler:        h,
+		options:        options,
+		logger:         rootLogger.Module(options.Type),
+		pluginId:       fmt.Sprintf("vim-drivers.%s.%s", options.Type, options.Name),
+		tracerProvider: tracerProvider,
+		stopTracing:    stopTracing,
+	}, nil
+}
+
+//Register the plugin and handle the requests of the NFVO, until Shutdown is called or SIGINT or SIGTERM is received
+func (plugin *Plugin) Serve() error {
+	return plugin.ServeContext(context.Background())
+}
 
+//Register the plugin and handle the requests of the NFVO, until Shutdown is called, ctx is cancelled,
+//SIGINT or SIGTERM is received or a fatal error occurs. The plugin is unregistered in all the cases.
+func (plugin *Plugin) ServeContext(ctx context.Context) error {
+	plugin.logger.Infof("Starting Plugin of type %s", plugin.options.Type)
+	rabbitCredentials, err := sdk.RegisterPlugin(plugin.pluginId, plugin.options)
 	if err != nil {
-		logger.Errorf("Error getting credentials: %v", err)
+		plugin.logger.Errorf("Error getting credentials: %v", err)
 		return err
 	}
+	plugin.creds = rabbitCredentials
 
-	manager, err := sdk.NewManager(
-		h,
-		rabbitCredentials.RabbitUsername,
-		rabbitCredentials.RabbitPassword,
-		cfg.BrokerIp,
-		cfg.BrokerPort,
-		sdk.OpenbatonExchangeName,
-		pluginId,
-		cfg.Workers,
-		false,
-		name,
-		handlePluginRequest,
-		"DEBUG",
-		net,
-		img,
-	)
+	manager, err := sdk.NewManagerWithOptions(plugin.handler, rabbitCredentials, plugin.pluginId, handlePluginRequest, plugin.options)
 	if err != nil {
 		return err
 	}
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		for range c {
-			logger.Infof("Received ctrl-c, unregistering")
-			manager.Unregister(cfg.Type, rabbitCredentials.RabbitUsername, rabbitCredentials.RabbitPassword, nil)
-			go manager.Shutdown()
-			logger.Infof("Done")
-			os.Exit(0)
-		}
-	}()
-
-	manager.Serve()
-	return nil
+	manager.SetTracerProvider(plugin.tracerProvider)
+	manager.SetRegistered(true)
+	plugin.mutex.Lock()
+	plugin.manager = manager
+	plugin.mutex.Unlock()
+	if path := plugin.options.ConfigFile; path != "" {
+		manager.WatchConfig(path, func() ([]sdk.Option, error) {
+			cfg, err := LoadConfig(path, nil)
+			if err != nil {
+				return nil, err
+			}
+			return []sdk.Option{WithConfig(*cfg)}, nil
+		})
+	}
+
+	return manager.ServeContext(ctx, func() { plugin.Shutdown() })
+}
+
+//Unregister the plugin and stop serving
+func (plugin *Plugin) Shutdown() error {
+	manager := plugin.Manager()
+	if manager == nil {
+		return nil
+	}
+	var err error
+	plugin.shutdownOnce.Do(func() {
+		manager.Unregister(plugin.options.Type, plugin.creds.RabbitUsername, plugin.creds.RabbitPassword, nil)
+		plugin.stopTracing(context.Background())
+		err = manager.Shutdown()
+	})
+	return err
+}
+
+//The manager consuming the requests, nil until Serve is called
+func (plugin *Plugin) Manager() *sdk.Manager {
+	plugin.mutex.Lock()
+	defer plugin.mutex.Unlock()
+	return plugin.manager
+}
+
+//The options the plugin was created with, to be changed only before Serve is called
+func (plugin *Plugin) Options() *sdk.Options {
+	return plugin.options
 }
@@ -21,6 +21,7 @@ import (
 )
 
 // The Handler interface defines an abstraction of the operations that a VNFM should provide.
+// A HandlerVim can also implement sdk.HealthChecker, e.g. calling Refresh, to take part in the readiness probe.
 type HandlerVim interface {
 	AddFlavour(vimInstance interface{}, deploymentFlavour *catalogue.DeploymentFlavour) (*catalogue.DeploymentFlavour, error)
 
@@ -1,12 +1,13 @@
 package pluginsdk
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"reflect"
 
-	"github.com/op/go-logging"
+	"github.com/openbaton/go-openbaton/sdk"
 )
 
 var (
@@ -15,8 +16,11 @@ var (
 
 //The worker struct allows the Plugin SDK to invoke implementation specific of Plugins
 type worker struct {
-	l           *logging.Logger
-	h           HandlerVim
+	//Passed to the methods taking a context first
+	ctx context.Context
+	l   sdk.Logger
+	//A HandlerVimV1 or a HandlerVimV2
+	h           sdk.Handler
 	imageType   interface{}
 	networkType interface{}
 }
@@ -34,9 +38,13 @@ func (w worker) handle(fname string, args []json.RawMessage) (interface{}, error
 	fType := fValue.Type()
 
 	// matchFunc ensures the length of args is correct
-	callArgs := make([]reflect.Value, len(args))
+	offset := contextArgs(fType)
+	callArgs := make([]reflect.Value, len(args)+offset)
+	if offset == 1 {
+		callArgs[0] = reflect.ValueOf(w.ctx)
+	}
 	for i, jsonArg := range args {
-		argType := fType.In(i)
+		argType := fType.In(i + offset)
 
 		kind := argType.Kind()
 		if kind == reflect.Interface && i == 0 { // special case: VimInstance at first position
@@ -45,7 +53,7 @@ func (w worker) handle(fname string, args []json.RawMessage) (interface{}, error
 				return nil, err
 			}
 
-			callArgs[i] = reflect.ValueOf(GetVimInstance(jsonArg, argValue))
+			callArgs[i+offset] = reflect.ValueOf(GetVimInstance(jsonArg, argValue))
 		} else if kind == reflect.Slice && argType.Elem().Kind() == reflect.Uint8 { // special case: argument is an array of bytes
 			var baseStr string
 			if err := json.Unmarshal(jsonArg, &baseStr); err != nil {
@@ -57,20 +65,20 @@ func (w worker) handle(fname string, args []json.RawMessage) (interface{}, error
 				return nil, plugError{"base64 decoding failed"}
 			}
 
-			callArgs[i] = reflect.ValueOf(b)
+			callArgs[i+offset] = reflect.ValueOf(b)
 		} else if fname == "createNetwork" && i == 1 {
 			argValue := map[string]interface{}{}
 			if err := json.Unmarshal(jsonArg, &argValue); err != nil {
 				return nil, err
 			}
 			network := GetConcrete(jsonArg, w.networkType)
-			callArgs[i] = network
+			callArgs[i+offset] = network
 		} else if fname == "addImageFromURL" && i == 1 {
 			argValue := map[string]interface{}{}
 			if err := json.Unmarshal(jsonArg, &argValue); err != nil {
 				return nil, err
 			}
-			callArgs[i] = reflect.ValueOf(GetConcrete(jsonArg, w.imageType))
+			callArgs[i+offset] = reflect.ValueOf(GetConcrete(jsonArg, w.imageType))
 		} else {
 			// create a new pointer to the arg type, and deserialise into it
 			// its JSON
@@ -79,7 +87,7 @@ func (w worker) handle(fname string, args []json.RawMessage) (interface{}, error
 				return nil, err
 			}
 
-			callArgs[i] = reflect.ValueOf(argValue.Elem().Interface())
+			callArgs[i+offset] = reflect.ValueOf(argValue.Elem().Interface())
 		}
 	}
 
@@ -118,116 +126,45 @@ func (w worker) handle(fname string, args []json.RawMessage) (interface{}, error
 }
 
 func (w worker) matchFunc(fname string, args []json.RawMessage) (reflect.Value, error) {
-	var fVal reflect.Value
+	names, ok := methodNames[fname]
+	if !ok {
+		return reflect.Value{}, ErrProtocolFail
+	}
+	name := names[0]
 
 	switch fname {
-	case "addFlavor":
-		fVal = reflect.ValueOf(w.h.AddFlavour)
-
-		// overloaded function
+	// overloaded function
 	case "addImage":
 		// we need to check if the last argument is a byte array or a string
-		fVal = reflect.ValueOf(w.h.AddImage)
-		fType := fVal.Type()
-		if len(args) != fType.NumIn() {
+		if len(args) != vimMethodArgs(name) {
 			break // will make the same check below and fail there
 		}
 
 		lastArg := args[len(args)-1]
 		var str string
 		if err := json.Unmarshal(lastArg, &str); err != nil {
-			return fVal, err
+			return reflect.Value{}, err
 		}
 
 		// if base64 deserialisation fails, then this is an URL string
 		if _, e := base64.StdEncoding.DecodeString(str); e != nil {
-			fVal = reflect.ValueOf(w.h.AddImageFromURL)
+			name = "AddImageFromURL"
 		}
 
-	case "copyImage":
-		fVal = reflect.ValueOf(w.h.CopyImage)
-
-	case "createNetwork":
-		fVal = reflect.ValueOf(w.h.CreateNetwork)
-
-	case "createSubnet":
-		fVal = reflect.ValueOf(w.h.CreateSubnet)
-
-	case "deleteFlavor":
-		fVal = reflect.ValueOf(w.h.DeleteFlavour)
-
-	case "deleteImage":
-		fVal = reflect.ValueOf(w.h.DeleteImage)
-
-	case "deleteNetwork":
-		fVal = reflect.ValueOf(w.h.DeleteNetwork)
-
-	case "deleteServerByIdAndWait":
-		fVal = reflect.ValueOf(w.h.DeleteServerByIDAndWait)
-
-	case "deleteSubnet":
-		fVal = reflect.ValueOf(w.h.DeleteSubnet)
-
-	case "getNetworkById":
-		fVal = reflect.ValueOf(w.h.NetworkByID)
-
-	case "getQuota":
-		fVal = reflect.ValueOf(w.h.Quota)
-
-	case "getSubnetsExtIds":
-		fVal = reflect.ValueOf(w.h.SubnetsExtIDs)
-
-	case "getType":
-		fVal = reflect.ValueOf(w.h.Type)
-
-	case "launchInstance":
-		fVal = reflect.ValueOf(w.h.LaunchInstance)
-
-		// overloaded function
+	// overloaded function
 	case "launchInstanceAndWait":
-		fVal = reflect.ValueOf(w.h.LaunchInstanceAndWait)
-
 		// check for overloaded functions
-		if len(args) == reflect.TypeOf(w.h.LaunchInstanceAndWaitWithIPs).NumIn() {
-			fVal = reflect.ValueOf(w.h.LaunchInstanceAndWaitWithIPs)
+		if len(args) == vimMethodArgs("LaunchInstanceAndWaitWithIPs") {
+			name = "LaunchInstanceAndWaitWithIPs"
 		}
-
-	case "listFlavors":
-		fVal = reflect.ValueOf(w.h.ListFlavours)
-
-	case "refresh":
-		fVal = reflect.ValueOf(w.h.Refresh)
-
-	case "listImages":
-		fVal = reflect.ValueOf(w.h.ListImages)
-
-	case "listNetworks":
-		fVal = reflect.ValueOf(w.h.ListNetworks)
-
-	case "listServer":
-		fVal = reflect.ValueOf(w.h.ListServer)
-
-	case "updateFlavor":
-		fVal = reflect.ValueOf(w.h.UpdateFlavour)
-
-	case "updateImage":
-		fVal = reflect.ValueOf(w.h.UpdateImage)
-
-	case "updateNetwork":
-		fVal = reflect.ValueOf(w.h.UpdateNetwork)
-
-	case "updateSubnet":
-		fVal = reflect.ValueOf(w.h.UpdateSubnet)
-
-	case "rebuildServer":
-		fVal = reflect.ValueOf(w.h.RebuildServer)
-
-	default:
-		return fVal, ErrProtocolFail
 	}
 
+	fVal := reflect.ValueOf(w.h).MethodByName(name)
+	if !fVal.IsValid() {
+		return fVal, plugError{fmt.Sprintf("%s is not supported by this plugin", fname)}
+	}
 	fType := fVal.Type()
-	if len(args) != fType.NumIn() {
+	if len(args) != fType.NumIn()-contextArgs(fType) {
 		return fVal, plugError{fmt.Sprintf("wrong number of arguments (%d) for function %s", len(args), fType.String())}
 	}
 
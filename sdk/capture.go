@@ -0,0 +1,226 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+//Directions of the captured messages
+const (
+	CaptureRequest = "request"
+	CaptureReply   = "reply"
+)
+
+//A message received or sent by a manager, written as one line of the capture file
+type CaptureRecord struct {
+	//CaptureRequest or CaptureReply
+	Direction string `json:"direction"`
+	//The queue of the manager
+	Queue string `json:"queue"`
+	//The action or method name of the request
+	Action        string    `json:"action,omitempty"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	ReplyTo       string    `json:"replyTo,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+	//The message with the passwords and secrets redacted, if it is JSON
+	Body json.RawMessage `json:"body,omitempty"`
+	//The message as received, if it is not JSON
+	Raw []byte `json:"raw,omitempty"`
+	//The error of the handler function, on the request as no reply is sent
+	Error string `json:"error,omitempty"`
+}
+
+//The message of the record as it can be fed to a handler function
+func (r *CaptureRecord) Message() []byte {
+	if r.Body != nil {
+		return r.Body
+	}
+	return r.Raw
+}
+
+//Writes the records of a capture, safe for concurrent use
+type Capture struct {
+	mutex  sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+//Create a capture writing to w
+func NewCapture(w io.Writer) *Capture {
+	c := &Capture{enc: json.NewEncoder(w)}
+	c.enc.SetEscapeHTML(false)
+	if closer, ok := w.(io.Closer); ok {
+		c.closer = closer
+	}
+	return c
+}
+
+//Create a capture appending to the file at path, readable only by the user as it contains the requests
+func OpenCapture(path string) (*Capture, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewCapture(f), nil
+}
+
+//Write a record
+func (c *Capture) Record(r *CaptureRecord) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.enc.Encode(r)
+}
+
+//Close the underlying writer, if it can be closed
+func (c *Capture) Close() error {
+	if c.closer == nil {
+		return nil
+	}
+	return c.closer.Close()
+}
+
+//Fill the body of the record with msg, redacted if JSON
+func (r *CaptureRecord) setMessage(msg []byte) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		r.Raw = msg
+		return
+	}
+	maskSecrets(v)
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.Raw = msg
+		return
+	}
+	r.Body = body
+}
+
+//Record a delivery and its reply, if the manager captures the messages
+func (manager *Manager) captureDelivery(d amqp.Delivery, dlv *delivery, reply []byte, handlerErr error) {
+	if manager.capture == nil {
+		return
+	}
+	req := &CaptureRecord{
+		Direction:     CaptureRequest,
+		Queue:         manager.queueName,
+		Action:        dlv.operation,
+		CorrelationID: d.CorrelationId,
+		ReplyTo:       d.ReplyTo,
+		Timestamp:     dlv.start.UTC(),
+	}
+	req.setMessage(d.Body)
+	if handlerErr != nil {
+		req.Error = handlerErr.Error()
+	}
+	if err := manager.capture.Record(req); err != nil {
+		manager.logger.Warningf("Error while capturing the request: %v", err)
+		return
+	}
+	if handlerErr != nil {
+		return
+	}
+	resp := &CaptureRecord{
+		Direction:     CaptureReply,
+		Queue:         manager.queueName,
+		Action:        dlv.operation,
+		CorrelationID: d.CorrelationId,
+		ReplyTo:       d.ReplyTo,
+		Timestamp:     time.Now().UTC(),
+	}
+	resp.setMessage(reply)
+	if err := manager.capture.Record(resp); err != nil {
+		manager.logger.Warningf("Error while capturing the reply: %v", err)
+	}
+}
+
+//Read the records of a capture
+func ReadCapture(r io.Reader) ([]CaptureRecord, error) {
+	var records []CaptureRecord
+	dec := json.NewDecoder(r)
+	for {
+		var record CaptureRecord
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+//Read the records of the capture file at path
+func ReadCaptureFile(path string) ([]CaptureRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCapture(f)
+}
+
+//The outcome of replaying a captured request
+type ReplayResult struct {
+	Request CaptureRecord
+	//The reply captured for the request, nil if none
+	Recorded *CaptureRecord
+	//The reply of the replayed request, redacted as the captured ones
+	Reply json.RawMessage
+	Err   error
+}
+
+//Whether the replayed request got the same reply, or error, as the captured one.
+//Replies containing generated ids or timestamps never match.
+func (r *ReplayResult) Matches() bool {
+	if r.Err != nil || r.Recorded == nil {
+		return r.Recorded == nil && (r.Err != nil) == (r.Request.Error != "")
+	}
+	var recorded, replayed interface{}
+	if json.Unmarshal(r.Recorded.Message(), &recorded) != nil || json.Unmarshal(r.Reply, &replayed) != nil {
+		return bytes.Equal(r.Recorded.Message(), r.Reply)
+	}
+	return reflect.DeepEqual(recorded, replayed)
+}
+
+//Feed the captured requests, in order, to invoke, e.g. a handler function called in-process without broker.
+//The requests carry the redacted passwords, the handler must not rely on them.
+func Replay(ctx context.Context, records []CaptureRecord, invoke Invoker) []ReplayResult {
+	replies := make(map[string]*CaptureRecord)
+	for i := range records {
+		if r := &records[i]; r.Direction == CaptureReply && r.CorrelationID != "" {
+			replies[r.CorrelationID] = r
+		}
+	}
+	var results []ReplayResult
+	for _, r := range records {
+		if r.Direction != CaptureRequest {
+			continue
+		}
+		res := ReplayResult{Request: r}
+		if r.CorrelationID != "" {
+			res.Recorded = replies[r.CorrelationID]
+		}
+		//a panic of the handler, e.g. lacking the broker, fails only its request
+		reply, err := RecoverInterceptor(ctx, r.Message(), invoke)
+		if err != nil {
+			res.Err = err
+		} else {
+			var redacted CaptureRecord
+			redacted.setMessage(reply)
+			res.Reply = redacted.Message()
+		}
+		results = append(results, res)
+	}
+	return results
+}
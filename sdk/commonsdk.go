@@ -3,45 +3,125 @@
 package sdk
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
-	"github.com/op/go-logging"
 	"github.com/openbaton/go-openbaton/catalogue"
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //The plugin or vnfm Handler interface
 type Handler interface{}
 
 // Handler function to be implemented by the vnfm package and by the pluginsdk package that will be called while serving
-type handlerFunction func(bytemsg []byte, handlerVnfm Handler, allocate bool, connection *amqp.Connection, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) ([]byte, error)
+type handlerFunction func(ctx context.Context, bytemsg []byte, handlerVnfm Handler, allocate bool, connection *amqp.Connection, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) ([]byte, error)
 
 //Function to retrieve the private amqp credentials for a VNFM
 func GetVnfmCreds(username string, password string, brokerIp string, brokerPort, timeout int, vnfmEndpoint *catalogue.Endpoint, logLevel string) (*catalogue.ManagerCredentials, error) {
+	return RegisterVnfm(vnfmEndpoint, legacyOptions(username, password, brokerIp, brokerPort, timeout, logLevel))
+}
+
+//Function to retrieve the private amqp credentials for a Plugin
+func GetPluginCreds(username string, password string, brokerIp string, brokerPort, timeout int, pluginType string, logLevel string) (*catalogue.ManagerCredentials, error) {
+	return RegisterPlugin(pluginType, legacyOptions(username, password, brokerIp, brokerPort, timeout, logLevel))
+}
+
+func legacyOptions(username string, password string, brokerIp string, brokerPort, timeout int, logLevel string) *Options {
+	return NewOptions(
+		WithCredentials(username, password),
+		WithBroker(brokerIp, brokerPort),
+		WithTimeout(timeout),
+		WithLogger(levelLogger(logLevel)),
+	)
+}
+
+//Register the VNFM to the NFVO, returning its private amqp credentials.
+//The credentials cached in o.CredentialsFile are reused if still valid.
+func RegisterVnfm(vnfmEndpoint *catalogue.Endpoint, o *Options) (*catalogue.ManagerCredentials, error) {
 	registerMessage := catalogue.VnfmRegisterMessage{}
 	registerMessage.Action = "register"
 	registerMessage.Endpoint = vnfmEndpoint
 	registerMessage.Type = vnfmEndpoint.Type
-	return getCreds(username, password, brokerIp, brokerPort, timeout, registerMessage, logLevel)
+	return register(vnfmEndpoint.Endpoint, registerMessage, o)
 }
 
-//Function to retrieve the private amqp credentials for a Plugin
-func GetPluginCreds(username string, password string, brokerIp string, brokerPort, timeout int, pluginType string, logLevel string) (*catalogue.ManagerCredentials, error) {
+//Register the plugin with the given id (e.g. vim-drivers.openstack.name) to the NFVO, returning its private amqp credentials.
+//The metadata of o, e.g. the capabilities set by pluginsdk, are sent in the registration message.
+func RegisterPlugin(pluginId string, o *Options) (*catalogue.ManagerCredentials, error) {
 	registerMessage := catalogue.PluginRegisterMessage{}
 	registerMessage.Action = "register"
-	registerMessage.Type = pluginType
-	return getCreds(username, password, brokerIp, brokerPort, timeout, registerMessage, logLevel)
+	registerMessage.Type = pluginId
+	registerMessage.Metadata = o.Metadata
+	return register(pluginId, registerMessage, o)
+}
+
+//Unregister a VNFM endpoint without a Manager, e.g. one left registered by a manager that crashed.
+//creds are the ones returned by the registration.
+func UnregisterVnfm(vnfmEndpoint *catalogue.Endpoint, creds *catalogue.ManagerCredentials, o *Options) error {
+	return sendUnregister(catalogue.VnfmManagerUnregisterMessage{
+		Type:     vnfmEndpoint.Type,
+		Action:   "unregister",
+		Username: creds.RabbitUsername,
+		Password: creds.RabbitPassword,
+		Endpoint: vnfmEndpoint,
+	}, creds, o)
+}
+
+//Unregister a plugin of the given type without a Manager, creds are the ones returned by the registration
+func UnregisterPlugin(typ string, creds *catalogue.ManagerCredentials, o *Options) error {
+	return sendUnregister(catalogue.ManagerUnregisterMessage{
+		Type:     typ,
+		Action:   "unregister",
+		Username: creds.RabbitUsername,
+		Password: creds.RabbitPassword,
+	}, creds, o)
+}
+
+//Send the unregister message on a connection of the manager user and remove the credentials cache
+func sendUnregister(msg interface{}, creds *catalogue.ManagerCredentials, o *Options) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	uri := getAmqpUri(creds.RabbitUsername, creds.RabbitPassword, o.BrokerIp, o.BrokerPort, o.TLS != nil)
+	conn, err := amqpDial(uri, time.Duration(o.Timeout)*time.Second, o.TLS)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	channel, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer channel.Close()
+	if err := SendMessage(nfvoManagerHandling, msgBytes, channel, o.logger()); err != nil {
+		return err
+	}
+	return forgetCredentials(o)
 }
 
-func getCreds(username string, password string, brokerIp string, brokerPort, timeout int, msg interface{}, logLevel string) (*catalogue.ManagerCredentials, error) {
-	amqpUri := getAmqpUri(username, password, brokerIp, brokerPort)
-	logger := GetLogger("common.sdk", logLevel)
-	logger.Debugf("Dialing %s. Timeout: %d", amqpUri, timeout)
+func getCreds(msg interface{}, o *Options) (*catalogue.ManagerCredentials, error) {
+	amqpUri := getAmqpUri(o.Username, o.Password, o.BrokerIp, o.BrokerPort, o.TLS != nil)
+	timeout := o.Timeout
+	logger := o.logger().Module("credentials")
+	logger.Debugf("Dialing %s. Timeout: %d", maskedAmqpUri(amqpUri), timeout)
 
-	conn, err := amqpDial(amqpUri, time.Duration(timeout)*time.Second)
+	conn, err := amqpDial(amqpUri, time.Duration(timeout)*time.Second, o.TLS)
 
 	if err != nil {
 		return nil, err
@@ -119,27 +199,81 @@ func getCreds(username string, password string, brokerIp string, brokerPort, tim
 	}
 }
 
-func getAmqpUri(username string, password string, brokerIp string, brokerPort int) string {
-	return fmt.Sprintf("amqp://%s:%s@%s:%d/", username, password, brokerIp, brokerPort)
+func getAmqpUri(username string, password string, brokerIp string, brokerPort int, secure bool) string {
+	scheme := "amqp"
+	if secure {
+		scheme = "amqps"
+	}
+	u := url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword(username, password),
+		Host:   fmt.Sprintf("%s:%d", brokerIp, brokerPort),
+		Path:   "/",
+	}
+	return u.String()
+}
+
+//The uri without the password, for logging
+func maskedAmqpUri(amqpUri string) string {
+	u, err := url.Parse(amqpUri)
+	if err != nil {
+		return amqpUri
+	}
+	return u.Redacted()
 }
 
 //The generic Manager struct
 type Manager struct {
+	//Replaced when reconnecting, guarded by mutex
 	Connection *amqp.Connection
 	Channel    *amqp.Channel
 	//Number of listeners
 	workers int
+	//Tags of the running consumers, one per worker
+	consumerTags []string
+	//Seconds to wait for the broker when (re)connecting
+	timeout int
+	//The options the manager was created with, updated by Reload
+	options *Options
+	//Guards Connection, Channel, workers, consumerTags, timeout and options
+	mutex sync.Mutex
 	//define whenever the VNFM must allocate resources
 	allocate bool
 	//The name of the queue the manager is consuming on
 	queueName       string
 	errorChan       chan error
-	logger          *logging.Logger
+	logger          Logger
 	deliveries      <-chan amqp.Delivery
 	handlerFunction handlerFunction
 	handler         Handler
 	image           catalogue.BaseImageInt
 	network         catalogue.BaseNetworkInt
+	//Used to reconnect when the connection to the broker is lost
+	amqpURI   string
+	exchange  string
+	closeChan chan *amqp.Error
+	metrics   *Metrics
+	//The http servers started by the manager, by address, shut down with the manager
+	httpServers map[string]*http.Server
+	httpMutex   sync.Mutex
+	health    health
+	tracer    trace.Tracer
+	tls       *tls.Config
+	//Whether Connection is shared with other managers, see Supervisor
+	shared bool
+	store  Store
+	//The handler function wrapped by the interceptors
+	invoker Invoker
+	//Limits the requests handled at the same time
+	inFlight *limiter
+	//Records the deliveries and replies, nil if disabled
+	capture *Capture
+	//Closed by Shutdown, makes Serve return
+	done     chan struct{}
+	doneOnce sync.Once
+	//Receives the error that prevents the manager from going on
+	failed  chan error
+	started time.Time
 }
 
 // Instantiate a new Manager struct
@@ -158,49 +292,122 @@ func NewManager(h Handler,
 	net catalogue.BaseNetworkInt,
 	img catalogue.BaseImageInt) (*Manager, error) {
 
+	o := NewOptions(
+		WithBroker(brokerIp, brokerPort),
+		WithWorkers(workers),
+		WithName(managerName),
+		WithLogger(levelLogger(logLevel)),
+	)
+	o.Allocate = allocate
+	o.Network = net
+	o.Image = img
+	return newManager(h, username, password, exchange, queueName, handleFunction, o)
+}
+
+//Instantiate a new Manager consuming on queueName with the credentials returned by the registration.
+//The metrics and health servers, the store and the interceptors are taken from the options.
+func NewManagerWithOptions(h Handler, creds *catalogue.ManagerCredentials, queueName string, handleFunction handlerFunction, o *Options) (*Manager, error) {
+	manager, err := newManager(h, creds.RabbitUsername, creds.RabbitPassword, OpenbatonExchangeName, queueName, handleFunction, o)
+	if err != nil {
+		return nil, err
+	}
+	if o.MetricsAddress != "" {
+		manager.ServeMetrics(o.MetricsAddress)
+	}
+	if o.HealthAddress != "" {
+		manager.ServeHealth(o.HealthAddress)
+	}
+	return manager, nil
+}
+
+func newManager(h Handler, username, password, exchange, queueName string, handleFunction handlerFunction, o *Options) (*Manager, error) {
 	manager := &Manager{
-		Connection:      nil,
 		Channel:         nil,
-		allocate:        allocate,
-		workers:         workers,
+		allocate:        o.Allocate,
+		workers:         o.Workers,
+		timeout:         o.Timeout,
 		errorChan:       make(chan error),
-		logger:          GetLogger(managerName, logLevel),
+		logger:          o.logger().Module(o.Name),
 		handlerFunction: handleFunction,
 		handler:         h,
-		image:           img,
-		network:         net,
+		image:           o.Image,
+		network:         o.Network,
+		amqpURI:         getAmqpUri(username, password, o.BrokerIp, o.BrokerPort, o.TLS != nil),
+		exchange:        exchange,
+		queueName:       queueName,
+		httpServers:     make(map[string]*http.Server),
+		tracer:          otel.GetTracerProvider().Tracer(tracerName),
+		tls:             o.TLS,
+		Connection:      o.Connection,
+		shared:          o.Connection != nil,
+		store:           o.Store,
+		inFlight:        newLimiter(o.MaxInFlight),
+		done:            make(chan struct{}),
+		failed:          make(chan error, 1),
+		started:         time.Now(),
+	}
+	options := *o
+	manager.options = &options
+	if manager.store == nil {
+		manager.store = NewMemoryStore()
+	}
+	if o.CaptureFile != "" {
+		capture, err := OpenCapture(o.CaptureFile)
+		if err != nil {
+			return nil, err
+		}
+		manager.capture = capture
+		manager.logger.Warningf("Capturing the messages to %s", o.CaptureFile)
+	}
+	manager.invoker = chainInterceptors(o.Interceptors, manager.invoke)
+	manager.metrics = newMetrics(queueName, manager.queueDepth)
+	if checker, ok := h.(HealthChecker); ok {
+		manager.AddHealthCheck("handler", checker.HealthCheck)
 	}
 
-	err := setupManager(username, password, brokerIp, brokerPort, manager, exchange, queueName)
+	err := manager.connect()
 	if err != nil {
 		manager.logger.Errorf("Error while setup the amqp thing: %v", err)
+		if manager.capture != nil {
+			manager.capture.Close()
+		}
 		return nil, err
 	}
-	manager.queueName = queueName
 	return manager, nil
 }
 
-//Configure the manager and start consuming
-func setupManager(username string, password string, brokerIp string, brokerPort int, manager *Manager, exchange string, queueName string) error {
-	amqpURI := getAmqpUri(username, password, brokerIp, brokerPort)
-	manager.logger.Debugf("dialing %s", amqpURI)
-	var err error
-	manager.Connection, err = amqp.Dial(amqpURI)
-	if err != nil {
-		return err
+//Connect to the broker, declare the manager queue and bind it to the exchange
+//A shared connection is not dialled, only a channel is opened on it.
+func (manager *Manager) connect() error {
+	conn := manager.connection()
+	if !manager.shared {
+		manager.logger.Debugf("dialing %s", maskedAmqpUri(manager.amqpURI))
+		var err error
+		conn, err = amqpDial(manager.amqpURI, manager.timeoutDuration(), manager.tls)
+		if err != nil {
+			return err
+		}
+		manager.closeChan = conn.NotifyClose(make(chan *amqp.Error, 1))
 	}
 
 	manager.logger.Debugf("got Connection, getting Channel")
-	manager.Channel, err = manager.Connection.Channel()
+	channel, err := conn.Channel()
 	if err != nil {
+		if !manager.shared {
+			conn.Close()
+		}
 		return err
 	}
+	manager.mutex.Lock()
+	manager.Connection = conn
+	manager.Channel = channel
+	manager.mutex.Unlock()
 
-	manager.logger.Debugf("got Channel, declaring Exchange (%q)", exchange)
+	manager.logger.Debugf("got Channel, declaring Exchange (%q)", manager.exchange)
 
-	manager.logger.Debugf("declared Exchange, declaring Queue %q", queueName)
-	queue, err := manager.Channel.QueueDeclare(
-		queueName,
+	manager.logger.Debugf("declared Exchange, declaring Queue %q", manager.queueName)
+	queue, err := channel.QueueDeclare(
+		manager.queueName,
 		true,
 		true,
 		false,
@@ -214,34 +421,165 @@ func setupManager(username string, password string, brokerIp string, brokerPort
 	manager.logger.Debugf("declared Queue (%q, %d messages, %d consumers), binding to Exchange",
 		queue.Name, queue.Messages, queue.Consumers)
 
-	if err = manager.Channel.QueueBind(
-		queue.Name, // name of the queue
-		queue.Name, // bindingKey
-		exchange,   // sourceExchange
-		false,      // noWait
-		nil,        // arguments
+	if err = channel.QueueBind(
+		queue.Name,       // name of the queue
+		queue.Name,       // bindingKey
+		manager.exchange, // sourceExchange
+		false,            // noWait
+		nil,              // arguments
 	); err != nil {
 		return err
 	}
 
+	if err = manager.declareDeadLetter(); err != nil {
+		return err
+	}
+
+	if manager.shared {
+		if err = manager.checkConsume(); err != nil {
+			return err
+		}
+	}
+
 	manager.logger.Debug("Queue bound to Exchange, starting Consume")
 	return nil
 }
 
-//Shutdown the manager
+//Check that the user of the shared connection can consume on the manager queue, so that a missing
+//permission is reported when the manager starts rather than by its consumers. The messages delivered
+//to the probe consumer are requeued when its channel is closed.
+func (manager *Manager) checkConsume() error {
+	channel, err := manager.connection().Channel()
+	if err != nil {
+		return err
+	}
+	defer channel.Close()
+	tag := fmt.Sprintf("%s-probe-%s", manager.queueName, randomString(8))
+	if _, err := channel.Consume(manager.queueName, tag, false, false, false, false, nil); err != nil {
+		return NewSdkError(fmt.Sprintf("the broker user of the shared connection cannot consume on %s: %v", manager.queueName, err))
+	}
+	return channel.Cancel(tag, false)
+}
+
+//The connection to the broker, replaced when reconnecting
+func (manager *Manager) connection() *amqp.Connection {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	return manager.Connection
+}
+
+//The channel of the manager, replaced when reconnecting
+func (manager *Manager) channel() *amqp.Channel {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	return manager.Channel
+}
+
+//Set the logger of the manager, also passed to the handler function of every delivery
+func (manager *Manager) SetLogger(l Logger) {
+	manager.logger = l
+}
+
+//The logger of the manager
+func (manager *Manager) Logger() Logger {
+	return manager.logger
+}
+
+//The persistence available to the manager and its handler
+func (manager *Manager) Store() Store {
+	return manager.store
+}
+
+//The metrics collected by the manager
+func (manager *Manager) Metrics() *Metrics {
+	return manager.metrics
+}
+
+//Expose the manager metrics on http://addr/metrics
+func (manager *Manager) ServeMetrics(addr string) {
+	manager.handleHTTP(addr, "/metrics", manager.metrics.Handler())
+}
+
+//Register an http handler on the server listening on addr, starting the server if needed
+func (manager *Manager) handleHTTP(addr, pattern string, handler http.Handler) {
+	manager.httpMutex.Lock()
+	defer manager.httpMutex.Unlock()
+	server, ok := manager.httpServers[addr]
+	if !ok {
+		server = &http.Server{Addr: addr, Handler: http.NewServeMux()}
+		manager.httpServers[addr] = server
+		go serveHTTP(server, manager.logger)
+	}
+	server.Handler.(*http.ServeMux).Handle(pattern, handler)
+}
+
+//Shut down the http servers started by the manager, waiting for the requests being served
+func (manager *Manager) stopHTTP() {
+	manager.httpMutex.Lock()
+	defer manager.httpMutex.Unlock()
+	for addr, server := range manager.httpServers {
+		shutdownHTTP(server, manager.logger)
+		delete(manager.httpServers, addr)
+	}
+}
+
+//Time given to the requests being served when an http server is shut down
+const httpShutdownTimeout = 5 * time.Second
+
+func serveHTTP(server *http.Server, logger Logger) {
+	logger.Infof("Listening for http requests on %s", server.Addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Errorf("Http server on %s stopped: %v", server.Addr, err)
+	}
+}
+
+func shutdownHTTP(server *http.Server, logger Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Errorf("Error while shutting down the http server on %s: %v", server.Addr, err)
+	}
+}
+
+//Number of messages waiting in the manager queue, -1 if it cannot be inspected
+func (manager *Manager) queueDepth() float64 {
+	channel, err := manager.connection().Channel()
+	if err != nil {
+		return -1
+	}
+	defer channel.Close()
+	queue, err := channel.QueueInspect(manager.queueName)
+	if err != nil {
+		return -1
+	}
+	return float64(queue.Messages)
+}
+
+//Shutdown the manager, making Serve return
 func (manager *Manager) Shutdown() error {
-	if err := manager.Connection.Close(); err != nil {
+	manager.doneOnce.Do(func() {
+		close(manager.done)
+		manager.stopHTTP()
+		if manager.capture != nil {
+			manager.capture.Close()
+		}
+	})
+	if manager.shared {
+		//the connection belongs to the supervisor
+		return manager.channel().Close()
+	}
+	if err := manager.connection().Close(); err != nil {
 		manager.logger.Errorf("AMQP connection close error: %s", err)
 		return err
 	}
 
-	defer manager.logger.Debugf("AMQP shutdown OK")
-
-	return <-manager.errorChan
+	manager.logger.Debugf("AMQP shutdown OK")
+	return nil
 }
 
 //Unregister function for Managers
 func (manager *Manager) Unregister(typ, username, password string, vnfmEndpoint *catalogue.Endpoint) {
+	manager.SetRegistered(false)
 	if vnfmEndpoint == nil {
 		manager.unregisterPlugin(typ, username, password)
 		return
@@ -275,68 +613,314 @@ func (manager *Manager) unregister(msg interface{}) {
 		manager.logger.Errorf("Error while marshalling unregister message: %v", err)
 		return
 	}
-	err = SendMsg(nfvoManagerHandling, msgBytes, manager.Channel, manager.logger)
+	err = SendMessage(nfvoManagerHandling, msgBytes, manager.channel(), manager.logger)
 	if err != nil {
 		manager.logger.Errorf("Error unregistering: %v", err)
 		return
 	}
+	if err := forgetCredentials(manager.options); err != nil {
+		manager.logger.Errorf("Error while removing the credentials cache: %v", err)
+	}
 }
 
-//Serve function for Manager. Start consuming.
+//Serve function for Manager. Start consuming, until Shutdown is called.
 func (manager *Manager) Serve() {
-	forever := make(chan bool)
-
-	for x := 0; x < manager.workers; x++ {
-
-		go func() {
-
-			deliveries, err := manager.Channel.Consume(
-				manager.queueName,
-				"",
-				false,
-				false,
-				false,
-				false,
-				nil,
-			)
-			if err != nil {
-				manager.logger.Errorf("Error while consuming: %v", err)
+	manager.consume()
+	go manager.watchConnection()
+	go manager.publishHeartbeats()
+	go func() {
+		for {
+			select {
+			case err := <-manager.errorChan:
+				manager.logger.Error(fmt.Sprintf("Got error while handling rabbitmq: %q", err))
+			case <-manager.done:
 				return
 			}
+		}
+	}()
+	<-manager.done
+}
 
-			manager.deliveries = deliveries
-			for d := range manager.deliveries {
-				d1 := d
-				go func() {
-					byteRes, err := manager.handlerFunction(d1.Body, manager.handler, manager.allocate, manager.Connection, manager.network, manager.image)
-					if err != nil {
-						manager.logger.Errorf("Error while executing handler function: %v", err)
-						return
-					}
-					err = manager.Channel.Publish(
-						"",
-						d1.ReplyTo,
-						false,
-						false,
-						amqp.Publishing{
-							ContentType:   AmqpContentType,
-							CorrelationId: d1.CorrelationId,
-							Body:          byteRes,
-						})
-					if err != nil {
-						manager.errorChan <- err
-						return
-					}
-				}()
-
-				d.Ack(false)
-			}
-		}()
+//Serve until ctx is cancelled, SIGINT or SIGTERM is received (unless disabled in the options), Fail is called or the manager is shut down.
+//Except in the last case stop is called, which must unregister and shut down the manager,
+//so that the registration is not leaked. Returns the error reported by Fail, if any.
+func (manager *Manager) ServeContext(ctx context.Context, stop func()) error {
+	signals := make(chan os.Signal, 1)
+	if manager.options.HandleSignals {
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
 	}
+
+	go manager.Serve()
+
+	var err error
+	select {
+	case <-manager.done:
+		return nil
+	case <-ctx.Done():
+		manager.logger.Infof("Context done, unregistering")
+	case sig := <-signals:
+		manager.logger.Infof("Received %s, unregistering", sig)
+	case err = <-manager.failed:
+		manager.logger.Errorf("Fatal error, unregistering: %v", err)
+	}
+	stop()
+	return err
+}
+
+//Start the workers consuming on the manager queue
+func (manager *Manager) consume() {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.consumerTags = nil
+	for x := 0; x < manager.workers; x++ {
+		manager.startConsumer()
+	}
+}
+
+//Start a worker consuming on the manager queue until its consumer is cancelled, must hold the mutex
+func (manager *Manager) startConsumer() {
+	tag := fmt.Sprintf("%s-%s", manager.queueName, randomString(8))
+	manager.consumerTags = append(manager.consumerTags, tag)
+	channel := manager.Channel
+
 	go func() {
-		for {
-			manager.logger.Error(fmt.Sprintf("Got error while handling rabbitmq: %q", <-manager.errorChan))
+
+		deliveries, err := channel.Consume(
+			manager.queueName,
+			tag,
+			false,
+			false,
+			false,
+			false,
+			nil,
+		)
+		if err != nil {
+			manager.logger.Errorf("Error while consuming: %v", err)
+			manager.removeConsumer(tag)
+			return
+		}
+
+		atomic.AddInt32(&manager.health.consumers, 1)
+		defer atomic.AddInt32(&manager.health.consumers, -1)
+
+		manager.deliveries = deliveries
+		for d := range deliveries {
+			d1 := d
+			manager.inFlight.acquire()
+			go func() {
+				defer manager.inFlight.release()
+				manager.handleDelivery(d1)
+				manager.ack(d1)
+			}()
 		}
 	}()
-	<-forever
+}
+
+func (manager *Manager) removeConsumer(tag string) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	for i, t := range manager.consumerTags {
+		if t == tag {
+			manager.consumerTags = append(manager.consumerTags[:i], manager.consumerTags[i+1:]...)
+			return
+		}
+	}
+}
+
+//Number of workers the manager should be running
+func (manager *Manager) Workers() int {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	return manager.workers
+}
+
+//Change the number of workers while serving, starting or cancelling consumers.
+//The requests being handled by a cancelled consumer are completed.
+func (manager *Manager) SetWorkers(workers int) error {
+	if workers <= 0 {
+		return NewSdkError(fmt.Sprintf("workers must be greater than 0, got %d", workers))
+	}
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.workers = workers
+	if manager.Channel == nil {
+		return nil
+	}
+	for len(manager.consumerTags) < workers {
+		manager.startConsumer()
+	}
+	for len(manager.consumerTags) > workers {
+		tag := manager.consumerTags[len(manager.consumerTags)-1]
+		manager.consumerTags = manager.consumerTags[:len(manager.consumerTags)-1]
+		if err := manager.Channel.Cancel(tag, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Change the max number of requests handled at the same time, 0 means unlimited
+func (manager *Manager) SetMaxInFlight(max int) {
+	manager.inFlight.setLimit(max)
+}
+
+//Change the seconds to wait for the broker when reconnecting and in the RPCs of the requests handled from now on
+func (manager *Manager) SetTimeout(seconds int) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.timeout = seconds
+}
+
+func (manager *Manager) timeoutDuration() time.Duration {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	return time.Duration(manager.timeout) * time.Second
+}
+
+//Acknowledge a delivery once it is handled, it is delivered again if the manager stops before
+func (manager *Manager) ack(d amqp.Delivery) {
+	if err := d.Ack(false); err != nil {
+		manager.logger.Errorf("Error while acknowledging the delivery: %v", err)
+	}
+}
+
+//Execute the interceptors chain, turning a panic not recovered by an interceptor such as RecoverInterceptor
+//into an error: the delivery is dead-lettered, answered and acknowledged like any failed one and the manager keeps serving
+func (manager *Manager) invokeDelivery(ctx context.Context, body []byte) (res []byte, err error) {
+	defer manager.recoverDelivery(&err)
+	return manager.invoker(ctx, body)
+}
+
+//Recover a panic of the handler, setting err and counting it in the metrics
+func (manager *Manager) recoverDelivery(err *error) {
+	if r := recover(); r != nil {
+		manager.logger.Errorf("Panic while handling a delivery: %v\n%s", r, debug.Stack())
+		manager.metrics.panics.Inc()
+		*err = NewSdkError(fmt.Sprintf("panic while handling a delivery: %v", r))
+	}
+}
+
+//Report an error that prevents the manager from going on, the first one is delivered by Failed
+func (manager *Manager) Fail(err error) {
+	select {
+	case manager.failed <- err:
+	default:
+	}
+}
+
+//Delivers the error reported by Fail, after which the manager should be unregistered and shut down
+func (manager *Manager) Failed() <-chan error {
+	return manager.failed
+}
+
+//Closed when the manager is shut down
+func (manager *Manager) Done() <-chan struct{} {
+	return manager.done
+}
+
+//Execute the handler function on a delivery and publish the reply
+func (manager *Manager) handleDelivery(d amqp.Delivery) {
+	ctx, span := manager.startDeliverySpan(context.Background(), d)
+	defer span.End()
+	dlv := &delivery{
+		metrics: manager.metrics,
+		start:   time.Now(),
+		timeout: manager.timeoutDuration(),
+		logger:  manager.logger.With(LogFieldCorrelationID, d.CorrelationId),
+	}
+	ctx = withDelivery(ctx, dlv)
+	byteRes, err := manager.invokeDelivery(ctx, d.Body)
+	dlv.done(err)
+	manager.captureDelivery(d, dlv, byteRes, err)
+	if err != nil {
+		RecordError(span, err)
+		manager.logger.Errorf("Error while executing handler function: %v", err)
+		manager.rejectDelivery(ctx, d, dlv.operation, err)
+		return
+	}
+	if dlv.failed {
+		span.SetStatus(codes.Error, "operation failed")
+	}
+	err = manager.channel().Publish(
+		"",
+		d.ReplyTo,
+		false,
+		false,
+		amqp.Publishing{
+			Headers:       traceHeaders(ctx),
+			ContentType:   AmqpContentType,
+			CorrelationId: d.CorrelationId,
+			Body:          byteRes,
+		})
+	if err != nil {
+		manager.errorChan <- err
+		return
+	}
+}
+
+//Execute the handler function, innermost invoker of the interceptors chain
+func (manager *Manager) invoke(ctx context.Context, body []byte) ([]byte, error) {
+	return manager.handlerFunction(ctx, body, manager.handler, manager.allocate, manager.connection(), manager.network, manager.image)
+}
+
+//Resume consuming on a shared connection re-established by the supervisor
+func (manager *Manager) reattach(conn *amqp.Connection) error {
+	manager.mutex.Lock()
+	manager.Connection = conn
+	manager.mutex.Unlock()
+	if err := manager.connect(); err != nil {
+		return err
+	}
+	manager.metrics.reconnects.Inc()
+	manager.consume()
+	return nil
+}
+
+//Wait for the connection to the broker to be lost and re-establish it.
+//Returns when the connection is closed by Shutdown, a shared connection is watched by its supervisor.
+func (manager *Manager) watchConnection() {
+	if manager.shared {
+		return
+	}
+	for {
+		select {
+		case <-manager.done:
+			return
+		case amqpErr, ok := <-manager.closeChan:
+			if !ok || amqpErr == nil {
+				return
+			}
+			manager.logger.Errorf("Connection to the broker lost: %v", amqpErr)
+		}
+		backoff := time.Second
+		for {
+			err := manager.connect()
+			if err == nil {
+				break
+			}
+			manager.logger.Errorf("Error while reconnecting, retrying in %s: %v", backoff, err)
+			if conn := manager.connection(); conn != nil {
+				conn.Close()
+			}
+			select {
+			case <-manager.done:
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+		}
+		select {
+		case <-manager.done:
+			//Shut down while reconnecting, the new connection was not closed by Shutdown
+			manager.connection().Close()
+			return
+		default:
+		}
+		manager.metrics.reconnects.Inc()
+		manager.logger.Infof("Reconnected to the broker")
+		manager.consume()
+	}
 }
@@ -0,0 +1,310 @@
+package sdk
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+//Prefix of the environment variables overriding the configuration, e.g. OPENBATON_BROKER_IP for brokerIp
+const ConfigEnvPrefix = "OPENBATON_"
+
+//Name of the command line flag selecting the configuration file
+const ConfigFileFlag = "config"
+
+//Load a configuration struct merging, in increasing priority:
+//the values already in cfg (the defaults), the file at path (TOML, YAML or JSON, by extension),
+//the OPENBATON_* environment variables and the command line flags in args.
+//The keys of the file, the environment variables and the flags are derived from the toml tags of the fields.
+//An empty path skips the file unless -config is given in args, nil args skip the flags.
+//All the invalid values are reported together in a *ConfigError.
+func LoadConfig(cfg interface{}, path string, args []string) error {
+	_, err := LoadConfigArgs(cfg, path, args)
+	return err
+}
+
+//Like LoadConfig, also returning the arguments following the flags, e.g. the command of a command line tool
+func LoadConfigArgs(cfg interface{}, path string, args []string) ([]string, error) {
+	fields, err := configFields(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var flagValues []*fieldValue
+	var rest []string
+	if args != nil {
+		fs := flag.NewFlagSet("openbaton", flag.ContinueOnError)
+		fs.StringVar(&path, ConfigFileFlag, path, "configuration file (TOML, YAML or JSON)")
+		for _, f := range fields {
+			v := &fieldValue{field: f}
+			fs.Var(v, f.key, fmt.Sprintf("%s (env %s)", f.key, f.env))
+		}
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest = fs.Args()
+		fs.Visit(func(fl *flag.Flag) {
+			if v, ok := fl.Value.(*fieldValue); ok {
+				flagValues = append(flagValues, v)
+			}
+		})
+	}
+
+	if path != "" {
+		if err := decodeConfigFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	cfgErr := &ConfigError{}
+	for _, f := range fields {
+		if s, ok := os.LookupEnv(f.env); ok {
+			if err := f.set(s); err != nil {
+				cfgErr.Add("%s: %v", f.env, err)
+			}
+		}
+	}
+	for _, v := range flagValues {
+		for _, s := range v.values {
+			if err := v.field.set(s); err != nil {
+				cfgErr.Add("-%s: %v", v.field.key, err)
+			}
+		}
+	}
+	return rest, cfgErr.Err()
+}
+
+//Decode the configuration file according to its extension
+func decodeConfigFile(path string, cfg interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml", "":
+		_, err = toml.Decode(string(data), cfg)
+	case ".json":
+		err = json.Unmarshal(data, cfg)
+	case ".yaml", ".yml":
+		var m map[string]interface{}
+		if err = yaml.Unmarshal(data, &m); err != nil {
+			break
+		}
+		if data, err = json.Marshal(m); err != nil {
+			break
+		}
+		//the json keys are matched case-insensitively against the field names, so the toml keys work too
+		err = json.Unmarshal(data, cfg)
+	default:
+		return NewSdkError(fmt.Sprintf("unknown format of the configuration file %s", path))
+	}
+	if err != nil {
+		return fmt.Errorf("error while loading config file %s: %v", path, err)
+	}
+	return nil
+}
+
+//A field of a configuration struct that can be set from a string
+type configField struct {
+	key   string
+	env   string
+	value reflect.Value
+}
+
+func configFields(cfg interface{}) ([]*configField, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, NewSdkError("the configuration must be a pointer to a struct")
+	}
+	v = v.Elem()
+	var fields []*configField
+	for i := 0; i < v.NumField(); i++ {
+		key := strings.Split(v.Type().Field(i).Tag.Get("toml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		fields = append(fields, &configField{key: key, env: ConfigEnvPrefix + envName(key), value: v.Field(i)})
+	}
+	return fields, nil
+}
+
+//brokerIp -> BROKER_IP
+func envName(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+//Set the field parsing s. Maps are given as "key=value,key2=value2" and are merged with the current content.
+func (f *configField) set(s string) error {
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", s)
+		}
+		f.value.SetInt(i)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", s)
+		}
+		f.value.SetBool(b)
+	case reflect.Map:
+		if f.value.Type().Key().Kind() != reflect.String || f.value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", f.value.Type())
+		}
+		if f.value.IsNil() {
+			f.value.Set(reflect.MakeMap(f.value.Type()))
+		}
+		for _, pair := range strings.Split(s, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("%q is not in the key=value format", pair)
+			}
+			f.value.SetMapIndex(reflect.ValueOf(strings.TrimSpace(kv[0])), reflect.ValueOf(strings.TrimSpace(kv[1])))
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", f.value.Type())
+	}
+	return nil
+}
+
+//Flag recording its values, applied once the file and the environment have been loaded
+type fieldValue struct {
+	field  *configField
+	values []string
+}
+
+func (v *fieldValue) String() string {
+	if v == nil || v.field == nil {
+		return ""
+	}
+	return fmt.Sprint(v.field.value.Interface())
+}
+
+func (v *fieldValue) Set(s string) error {
+	v.values = append(v.values, s)
+	return nil
+}
+
+func (v *fieldValue) IsBoolFlag() bool {
+	return v.field != nil && v.field.value.Kind() == reflect.Bool
+}
+
+//All the problems found while loading or validating a configuration
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+//Add a problem
+func (e *ConfigError) Add(format string, args ...interface{}) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+//Add a problem if ok is false
+func (e *ConfigError) Check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		e.Add(format, args...)
+	}
+}
+
+//Check the settings common to all the managers
+func (e *ConfigError) CheckManager(workers, brokerPort, timeout int, logLevel string, logLevels map[string]string, logFormat, traceExporter string) {
+	e.Check(workers > 0, "workers must be greater than 0, got %d", workers)
+	e.Check(brokerPort > 0 && brokerPort < 65536, "brokerPort must be between 1 and 65535, got %d", brokerPort)
+	e.Check(timeout > 0, "timeout must be greater than 0, got %d", timeout)
+	if logLevel != "" {
+		if _, err := ParseLevel(logLevel); err != nil {
+			e.Add("logLevel: %v", err)
+		}
+	}
+	modules := make([]string, 0, len(logLevels))
+	for module := range logLevels {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		if _, err := ParseLevel(logLevels[module]); err != nil {
+			e.Add("logLevels.%s: %v", module, err)
+		}
+	}
+	switch strings.ToLower(logFormat) {
+	case "", LogFormatText, LogFormatJson:
+	default:
+		e.Add("logFormat: unknown log format %q", logFormat)
+	}
+	switch strings.ToLower(traceExporter) {
+	case "", TraceExporterNone, TraceExporterStdout:
+	default:
+		e.Add("traceExporter: unknown trace exporter %q", traceExporter)
+	}
+}
+
+//The error, nil if there are no problems
+func (e *ConfigError) Err() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+const maskedValue = "****"
+
+//Marshal the configuration as indented JSON, hiding the values of the keys containing "password" or "secret"
+func MaskedConfig(cfg interface{}) ([]byte, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	maskSecrets(m)
+	return json.MarshalIndent(m, "", "  ")
+}
+
+func maskSecrets(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			lk := strings.ToLower(k)
+			if strings.Contains(lk, "password") || strings.Contains(lk, "secret") {
+				if s, ok := sub.(string); !ok || s != "" {
+					v[k] = maskedValue
+				}
+				continue
+			}
+			maskSecrets(sub)
+		}
+	case []interface{}:
+		for _, sub := range v {
+			maskSecrets(sub)
+		}
+	}
+}
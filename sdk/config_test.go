@@ -0,0 +1,279 @@
+package sdk
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testConfig struct {
+	Type       string            `toml:"type"`
+	BrokerIp   string            `toml:"brokerIp"`
+	BrokerPort int               `toml:"brokerPort"`
+	Allocate   bool              `toml:"allocate"`
+	Password   string            `toml:"password"`
+	LogLevels  map[string]string `toml:"logLevels"`
+	Ignored    string            `toml:"-"`
+	Untagged   string
+}
+
+func defaultTestConfig() testConfig {
+	return testConfig{Type: "unknown", BrokerIp: "localhost", BrokerPort: 5672}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		// the configuration files, by name
+		files map[string]string
+		path  string
+		env   map[string]string
+		args  []string
+		want  testConfig
+		rest  []string
+		// substrings of the error
+		err []string
+	}{
+		{
+			name: "defaults",
+			want: defaultTestConfig(),
+		},
+		{
+			name:  "toml",
+			files: map[string]string{"cfg.toml": "type = \"dummy\"\nbrokerPort = 5673\n[logLevels]\nrpc = \"INFO\"\n"},
+			path:  "cfg.toml",
+			want:  testConfig{Type: "dummy", BrokerIp: "localhost", BrokerPort: 5673, LogLevels: map[string]string{"rpc": "INFO"}},
+		},
+		{
+			name:  "yaml",
+			files: map[string]string{"cfg.yaml": "type: dummy\nbrokerIp: 10.0.0.1\nallocate: true\n"},
+			path:  "cfg.yaml",
+			want:  testConfig{Type: "dummy", BrokerIp: "10.0.0.1", BrokerPort: 5672, Allocate: true},
+		},
+		{
+			name:  "json",
+			files: map[string]string{"cfg.json": `{"type": "dummy", "brokerPort": 5673}`},
+			path:  "cfg.json",
+			want:  testConfig{Type: "dummy", BrokerIp: "localhost", BrokerPort: 5673},
+		},
+		{
+			name:  "environment over the file",
+			files: map[string]string{"cfg.toml": "type = \"dummy\"\nbrokerIp = \"10.0.0.1\"\n"},
+			path:  "cfg.toml",
+			env:   map[string]string{"OPENBATON_BROKER_IP": "10.0.0.2", "OPENBATON_LOG_LEVELS": "rpc=INFO, handler=DEBUG"},
+			want: testConfig{Type: "dummy", BrokerIp: "10.0.0.2", BrokerPort: 5672,
+				LogLevels: map[string]string{"rpc": "INFO", "handler": "DEBUG"}},
+		},
+		{
+			name:  "flags over the environment",
+			files: map[string]string{"cfg.toml": "type = \"dummy\"\n[logLevels]\nrpc = \"INFO\"\n"},
+			env:   map[string]string{"OPENBATON_TYPE": "env", "OPENBATON_BROKER_PORT": "5673"},
+			args:  []string{"-config", "cfg.toml", "-type", "flag", "-allocate", "-logLevels", "handler=WARNING", "cmd", "arg"},
+			want: testConfig{Type: "flag", BrokerIp: "localhost", BrokerPort: 5673, Allocate: true,
+				LogLevels: map[string]string{"rpc": "INFO", "handler": "WARNING"}},
+			rest: []string{"cmd", "arg"},
+		},
+		{
+			name: "flags without file",
+			args: []string{},
+			want: defaultTestConfig(),
+			rest: []string{},
+		},
+		{
+			name: "invalid values",
+			env:  map[string]string{"OPENBATON_BROKER_PORT": "port", "OPENBATON_ALLOCATE": "maybe"},
+			args: []string{"-logLevels", "rpc"},
+			err: []string{
+				`OPENBATON_BROKER_PORT: "port" is not an integer`,
+				`OPENBATON_ALLOCATE: "maybe" is not a boolean`,
+				`-logLevels: "rpc" is not in the key=value format`,
+			},
+		},
+		{
+			name:  "unknown format",
+			files: map[string]string{"cfg.ini": "type=dummy"},
+			path:  "cfg.ini",
+			err:   []string{"unknown format"},
+		},
+		{
+			name:  "invalid file",
+			files: map[string]string{"cfg.json": `{"brokerPort": "port"}`},
+			path:  "cfg.json",
+			err:   []string{"cfg.json"},
+		},
+		{
+			name: "missing file",
+			path: "missing.toml",
+			err:  []string{"missing.toml"},
+		},
+		{
+			name: "unknown flag",
+			args: []string{"-unknown"},
+			err:  []string{"unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			path := tt.path
+			if path != "" {
+				path = filepath.Join(dir, path)
+			}
+			var args []string
+			if tt.args != nil {
+				args = append([]string{}, tt.args...)
+			}
+			for i := range args {
+				if i > 0 && args[i-1] == "-config" {
+					args[i] = filepath.Join(dir, args[i])
+				}
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg := defaultTestConfig()
+			rest, err := LoadConfigArgs(&cfg, path, args)
+			if len(tt.err) > 0 {
+				if err == nil {
+					t.Fatalf("got no error, want %q", tt.err)
+				}
+				for _, want := range tt.err {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("got the error %q, want it to contain %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg, tt.want) {
+				t.Errorf("got %+v, want %+v", cfg, tt.want)
+			}
+			if !reflect.DeepEqual(rest, tt.rest) {
+				t.Errorf("got the arguments %q, want %q", rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestLoadConfigNotStruct(t *testing.T) {
+	cfg := defaultTestConfig()
+	for _, v := range []interface{}{cfg, new(string), nil} {
+		if err := LoadConfig(v, "", nil); err == nil {
+			t.Errorf("LoadConfig(%T) = nil, want an error", v)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"type":              "TYPE",
+		"brokerIp":          "BROKER_IP",
+		"heartbeatInterval": "HEARTBEAT_INTERVAL",
+		"logLevels":         "LOG_LEVELS",
+	}
+	for key, want := range tests {
+		if got := envName(key); got != want {
+			t.Errorf("envName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestCheckManager(t *testing.T) {
+	tests := []struct {
+		name          string
+		workers       int
+		brokerPort    int
+		timeout       int
+		logLevel      string
+		logLevels     map[string]string
+		logFormat     string
+		traceExporter string
+		problems      []string
+	}{
+		{
+			name: "valid", workers: 5, brokerPort: 5672, timeout: 2, logLevel: "DEBUG",
+			logLevels: map[string]string{"rpc": "INFO"}, logFormat: "JSON", traceExporter: "stdout",
+			problems: nil,
+		},
+		{
+			name: "defaults", workers: 1, brokerPort: 65535, timeout: 1,
+			problems: nil,
+		},
+		{
+			name: "invalid", workers: 0, brokerPort: 65536, timeout: 0, logLevel: "LOUD",
+			logLevels: map[string]string{"rpc": "INFO", "b": "x", "a": "y"}, logFormat: "xml", traceExporter: "jaeger",
+			problems: []string{
+				"workers must be greater than 0, got 0",
+				"brokerPort must be between 1 and 65535, got 65536",
+				"timeout must be greater than 0, got 0",
+				"logLevel: ",
+				"logLevels.a: ",
+				"logLevels.b: ",
+				`logFormat: unknown log format "xml"`,
+				`traceExporter: unknown trace exporter "jaeger"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgErr := &ConfigError{}
+			cfgErr.CheckManager(tt.workers, tt.brokerPort, tt.timeout, tt.logLevel, tt.logLevels, tt.logFormat, tt.traceExporter)
+
+			if len(cfgErr.Problems) != len(tt.problems) {
+				t.Fatalf("got the problems %q, want %q", cfgErr.Problems, tt.problems)
+			}
+			for i, want := range tt.problems {
+				if !strings.HasPrefix(cfgErr.Problems[i], want) {
+					t.Errorf("got the problem %q, want %q", cfgErr.Problems[i], want)
+				}
+			}
+			if (cfgErr.Err() == nil) != (len(tt.problems) == 0) {
+				t.Errorf("Err() = %v", cfgErr.Err())
+			}
+		})
+	}
+}
+
+func TestMaskedConfig(t *testing.T) {
+	cfg := struct {
+		Username string            `json:"username"`
+		Password string            `json:"password"`
+		Empty    string            `json:"emptyPassword"`
+		Nested   map[string]string `json:"nested"`
+	}{
+		Username: "admin",
+		Password: "openbaton",
+		Nested:   map[string]string{"clientSecret": "s3cr3t", "url": "http://localhost"},
+	}
+
+	data, err := MaskedConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"username":      "admin",
+		"password":      maskedValue,
+		"emptyPassword": "",
+		"nested":        map[string]interface{}{"clientSecret": maskedValue, "url": "http://localhost"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s", data)
+	}
+}
@@ -0,0 +1,112 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+)
+
+//The content of the credentials cache file
+type CachedCredentials struct {
+	//The VNFM endpoint or the plugin id the credentials were issued to
+	Id          string                        `json:"id"`
+	BrokerIp    string                        `json:"brokerIp"`
+	BrokerPort  int                           `json:"brokerPort"`
+	Credentials *catalogue.ManagerCredentials `json:"credentials"`
+}
+
+//Use the credentials cached in o.CredentialsFile if issued to the same manager and still accepted by the broker,
+//otherwise register sending msg and cache the new credentials.
+func register(id string, msg interface{}, o *Options) (*catalogue.ManagerCredentials, error) {
+	logger := o.logger().Module("credentials")
+	if o.CredentialsFile == "" {
+		return getCreds(msg, o)
+	}
+	if creds := loadCredentials(id, o, logger); creds != nil {
+		logger.Infof("Reusing the credentials cached in %s", o.CredentialsFile)
+		return creds, nil
+	}
+	creds, err := getCreds(msg, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveCredentials(id, creds, o); err != nil {
+		logger.Warningf("Error while caching the credentials in %s: %v", o.CredentialsFile, err)
+	}
+	return creds, nil
+}
+
+//The cached credentials, nil if missing, issued to another manager or rejected by the broker
+func loadCredentials(id string, o *Options, logger Logger) *catalogue.ManagerCredentials {
+	data, err := os.ReadFile(o.CredentialsFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warningf("Error while reading the credentials cache %s: %v", o.CredentialsFile, err)
+		}
+		return nil
+	}
+	cached := &CachedCredentials{}
+	if err := json.Unmarshal(data, cached); err != nil || cached.Credentials == nil {
+		logger.Warningf("Ignoring the invalid credentials cache %s", o.CredentialsFile)
+		return nil
+	}
+	if cached.Id != id || cached.BrokerIp != o.BrokerIp || cached.BrokerPort != o.BrokerPort {
+		logger.Infof("The cached credentials were issued to %s on %s:%d, registering again", cached.Id, cached.BrokerIp, cached.BrokerPort)
+		return nil
+	}
+	uri := getAmqpUri(cached.Credentials.RabbitUsername, cached.Credentials.RabbitPassword, o.BrokerIp, o.BrokerPort, o.TLS != nil)
+	conn, err := amqpDial(uri, time.Duration(o.Timeout)*time.Second, o.TLS)
+	if err != nil {
+		logger.Infof("The cached credentials are not valid anymore, registering again: %v", err)
+		return nil
+	}
+	conn.Close()
+	return cached.Credentials
+}
+
+//Write the cache readable only by the owner, through a temporary file so it is never partially written
+func saveCredentials(id string, creds *catalogue.ManagerCredentials, o *Options) error {
+	data, err := json.Marshal(&CachedCredentials{Id: id, BrokerIp: o.BrokerIp, BrokerPort: o.BrokerPort, Credentials: creds})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(o.CredentialsFile), 0700); err != nil {
+		return err
+	}
+	tmp := o.CredentialsFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, o.CredentialsFile)
+}
+
+//Read the credentials cache file at path, see WithCredentialsCache
+func ReadCredentialsCache(path string) (*CachedCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cached := &CachedCredentials{}
+	if err := json.Unmarshal(data, cached); err != nil {
+		return nil, err
+	}
+	if cached.Credentials == nil {
+		return nil, NewSdkError(fmt.Sprintf("no credentials in %s", path))
+	}
+	return cached, nil
+}
+
+//Remove the credentials cache once the manager is unregistered
+func forgetCredentials(o *Options) error {
+	if o.CredentialsFile == "" {
+		return nil
+	}
+	if err := os.Remove(o.CredentialsFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+//Headers added to the messages published to the dead-letter exchange
+const (
+	HeaderError         = "x-openbaton-error"
+	HeaderQueue         = "x-openbaton-queue"
+	HeaderOperation     = "x-openbaton-operation"
+	HeaderCorrelationID = "x-openbaton-correlation-id"
+	HeaderReplyTo       = "x-openbaton-reply-to"
+	HeaderFailedAt      = "x-openbaton-failed-at"
+)
+
+//Builds the reply sent to the caller when the handler function fails, e.g. because the request cannot be decoded.
+//Set by vnfmsdk and pluginsdk to the error message format their callers expect.
+type ErrorReplyFunc func(body []byte, err error) ([]byte, error)
+
+//Declare the dead-letter exchange (fanout, durable) and its queue, must be idempotent as several managers can share them
+func (manager *Manager) declareDeadLetter() error {
+	manager.mutex.Lock()
+	exchange, queue := manager.options.DeadLetterExchange, manager.options.DeadLetterQueue
+	manager.mutex.Unlock()
+	if exchange == "" {
+		return nil
+	}
+	channel := manager.channel()
+	if err := channel.ExchangeDeclare(exchange, amqp.ExchangeFanout, true, false, false, false, nil); err != nil {
+		return err
+	}
+	if queue == "" {
+		return nil
+	}
+	if _, err := channel.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		return err
+	}
+	return channel.QueueBind(queue, "", exchange, false, nil)
+}
+
+//Handle a delivery the handler function failed on: publish it to the dead-letter exchange, if any,
+//and send an error reply so the caller fails fast instead of timing out
+func (manager *Manager) rejectDelivery(ctx context.Context, d amqp.Delivery, operation string, handlerErr error) {
+	manager.mutex.Lock()
+	exchange := manager.options.DeadLetterExchange
+	errorReply := manager.options.ErrorReply
+	manager.mutex.Unlock()
+
+	if exchange != "" {
+		err := manager.channel().Publish(exchange, manager.queueName, false, false, amqp.Publishing{
+			Headers: amqp.Table{
+				HeaderError:         handlerErr.Error(),
+				HeaderQueue:         manager.queueName,
+				HeaderOperation:     operation,
+				HeaderCorrelationID: d.CorrelationId,
+				HeaderReplyTo:       d.ReplyTo,
+				HeaderFailedAt:      time.Now().UTC().Format(time.RFC3339),
+			},
+			ContentType:   d.ContentType,
+			CorrelationId: d.CorrelationId,
+			DeliveryMode:  amqp.Persistent,
+			Body:          d.Body,
+		})
+		if err != nil {
+			manager.logger.Errorf("Error while dead-lettering the message: %v", err)
+		}
+	}
+
+	if d.ReplyTo == "" || errorReply == nil {
+		return
+	}
+	body, err := errorReply(d.Body, handlerErr)
+	if err != nil {
+		manager.logger.Errorf("Error while building the error reply: %v", err)
+		return
+	}
+	err = manager.channel().Publish("", d.ReplyTo, false, false, amqp.Publishing{
+		Headers:       traceHeaders(ctx),
+		ContentType:   AmqpContentType,
+		CorrelationId: d.CorrelationId,
+		Body:          body,
+	})
+	if err != nil {
+		manager.logger.Errorf("Error while sending the error reply: %v", err)
+	}
+}
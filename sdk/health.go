@@ -0,0 +1,154 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+//A check taking part in the readiness probe of a Manager, returns nil if healthy
+type HealthCheck func() error
+
+//Optional interface a Handler can implement to take part in the readiness probe of its Manager,
+//e.g. a VIM driver verifying that the VIM is reachable.
+type HealthChecker interface {
+	HealthCheck() error
+}
+
+const (
+	healthStatusOk   = "ok"
+	healthStatusFail = "fail"
+)
+
+type checkResult struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type healthReport struct {
+	Status string                  `json:"status"`
+	Checks map[string]*checkResult `json:"checks,omitempty"`
+}
+
+//State of the manager exposed by the health endpoints
+type health struct {
+	mutex      sync.RWMutex
+	registered bool
+	consumers  int32
+	checks     map[string]HealthCheck
+}
+
+//Mark whether the manager is registered to the NFVO.
+func (manager *Manager) SetRegistered(registered bool) {
+	manager.health.mutex.Lock()
+	defer manager.health.mutex.Unlock()
+	manager.health.registered = registered
+}
+
+//Add a check to the readiness probe of the manager
+func (manager *Manager) AddHealthCheck(name string, check HealthCheck) {
+	manager.health.mutex.Lock()
+	defer manager.health.mutex.Unlock()
+	if manager.health.checks == nil {
+		manager.health.checks = make(map[string]HealthCheck)
+	}
+	manager.health.checks[name] = check
+}
+
+//Number of workers currently consuming on the manager queue
+func (manager *Manager) Consumers() int {
+	return int(atomic.LoadInt32(&manager.health.consumers))
+}
+
+//Expose the liveness and readiness probes of the manager on http://addr/healthz and http://addr/readyz
+func (manager *Manager) ServeHealth(addr string) {
+	manager.handleHTTP(addr, "/healthz", http.HandlerFunc(manager.serveLiveness))
+	manager.handleHTTP(addr, "/readyz", http.HandlerFunc(manager.serveReadiness))
+}
+
+//The process is alive as long as it answers: the report describes only the local state of the manager,
+//the checks added with AddHealthCheck are run by the readiness probe only
+func (manager *Manager) serveLiveness(w http.ResponseWriter, r *http.Request) {
+	report := newHealthReport()
+	manager.addState(report)
+	report.Status = healthStatusOk
+	writeHealthReport(w, report)
+}
+
+func (manager *Manager) serveReadiness(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, manager.readiness())
+}
+
+func newHealthReport() *healthReport {
+	return &healthReport{Status: healthStatusOk, Checks: make(map[string]*checkResult)}
+}
+
+//Add the result of a check, failing the report if err is not nil
+func (report *healthReport) add(name string, err error, detail string) {
+	res := &checkResult{Status: healthStatusOk, Detail: detail}
+	if err != nil {
+		res.Status = healthStatusFail
+		res.Detail = err.Error()
+		report.Status = healthStatusFail
+	}
+	report.Checks[name] = res
+}
+
+//Run all the checks, the manager is ready if it is connected, registered, consuming and all user checks pass
+func (manager *Manager) readiness() *healthReport {
+	report := newHealthReport()
+	manager.addState(report)
+
+	manager.health.mutex.RLock()
+	names := make([]string, 0, len(manager.health.checks))
+	for name := range manager.health.checks {
+		names = append(names, name)
+	}
+	checks := make([]HealthCheck, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		checks[i] = manager.health.checks[name]
+	}
+	manager.health.mutex.RUnlock()
+
+	for i, name := range names {
+		report.add(name, checks[i](), "")
+	}
+	return report
+}
+
+//Add the checks of the local state of the manager, cheap enough for the liveness probe
+func (manager *Manager) addState(report *healthReport) {
+	var brokerErr error
+	if conn := manager.connection(); conn == nil || conn.IsClosed() {
+		brokerErr = NewSdkError("not connected to the broker")
+	}
+	report.add("broker", brokerErr, "")
+
+	manager.health.mutex.RLock()
+	registered := manager.health.registered
+	manager.health.mutex.RUnlock()
+	var registrationErr error
+	if !registered {
+		registrationErr = NewSdkError("not registered to the NFVO")
+	}
+	report.add("registration", registrationErr, "")
+
+	consumers, workers := manager.Consumers(), manager.Workers()
+	var consumersErr error
+	if consumers < workers {
+		consumersErr = NewSdkError(fmt.Sprintf("%d/%d consumers", consumers, workers))
+	}
+	report.add("consumers", consumersErr, fmt.Sprintf("%d/%d consumers", consumers, workers))
+}
+
+func writeHealthReport(w http.ResponseWriter, report *healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != healthStatusOk {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
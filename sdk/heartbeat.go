@@ -0,0 +1,196 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+//Routing key of the heartbeats unless configured otherwise
+const DefaultHeartbeatRoutingKey = "openbaton.manager.heartbeat"
+
+//Metadata keys set by the SDK, see Options.Metadata
+const (
+	//Version of the handler interface implemented by the manager, e.g. "2"
+	MetadataHandlerVersion = "handlerVersion"
+	//Comma separated actions (VNFM) or methods (plugin) the handler supports
+	MetadataCapabilities = "capabilities"
+)
+
+//The periodic message a Manager publishes to report that it is alive
+type Heartbeat struct {
+	//Type of the manager, e.g. "dummy" or "openstack"
+	Type string `json:"type"`
+	//The queue of the manager, i.e. the endpoint registered to the NFVO
+	Endpoint string `json:"endpoint"`
+	Name     string `json:"name"`
+	//Version of the manager, see WithVersion
+	Version string `json:"version,omitempty"`
+	//Number of requests being handled
+	InFlight int `json:"inFlight"`
+	//Seconds since the manager was created
+	Uptime    int64     `json:"uptime"`
+	Timestamp time.Time `json:"timestamp"`
+	//Seconds until the next heartbeat
+	Interval int `json:"interval"`
+	//See Options.Metadata
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+//Number of requests currently being handled by the manager
+func (manager *Manager) InFlight() int {
+	return manager.inFlight.inUse()
+}
+
+func (manager *Manager) heartbeat() *Heartbeat {
+	manager.mutex.Lock()
+	o := *manager.options
+	manager.mutex.Unlock()
+	return &Heartbeat{
+		Type:      o.Type,
+		Endpoint:  manager.queueName,
+		Name:      o.Name,
+		Version:   o.Version,
+		InFlight:  manager.InFlight(),
+		Uptime:    int64(time.Since(manager.started).Seconds()),
+		Timestamp: time.Now().UTC(),
+		Interval:  o.HeartbeatInterval,
+		Metadata:  o.Metadata,
+	}
+}
+
+//Publish a heartbeat every HeartbeatInterval seconds until the manager is shut down, disabled if 0
+func (manager *Manager) publishHeartbeats() {
+	manager.mutex.Lock()
+	interval := manager.options.HeartbeatInterval
+	exchange, routingKey := manager.options.HeartbeatExchange, manager.options.HeartbeatRoutingKey
+	manager.mutex.Unlock()
+	if interval <= 0 {
+		return
+	}
+	if exchange == "" {
+		exchange = OpenbatonExchangeName
+	}
+	if routingKey == "" {
+		routingKey = DefaultHeartbeatRoutingKey
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		body, err := json.Marshal(manager.heartbeat())
+		if err == nil {
+			err = manager.channel().Publish(exchange, routingKey, false, false, amqp.Publishing{
+				ContentType: AmqpContentType,
+				//a heartbeat older than its interval is useless
+				Expiration: strconv.Itoa(interval * 1000),
+				Body:       body,
+			})
+		}
+		if err != nil {
+			manager.logger.Warningf("Error while publishing the heartbeat: %v", err)
+		}
+		select {
+		case <-manager.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+//Collects the heartbeats of the managers, to detect the ones that stopped without unregistering
+type HeartbeatMonitor struct {
+	mutex sync.RWMutex
+	last  map[string]*Heartbeat
+}
+
+//Consume the heartbeats published on exchange with routingKey (the defaults if empty) until ctx is done
+func WatchHeartbeats(ctx context.Context, conn *amqp.Connection, exchange, routingKey string) (*HeartbeatMonitor, error) {
+	if exchange == "" {
+		exchange = OpenbatonExchangeName
+	}
+	if routingKey == "" {
+		routingKey = DefaultHeartbeatRoutingKey
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	q, err := channel.QueueDeclare("", false, true, true, false, nil)
+	if err == nil {
+		err = channel.QueueBind(q.Name, routingKey, exchange, false, nil)
+	}
+	var deliveries <-chan amqp.Delivery
+	if err == nil {
+		deliveries, err = channel.Consume(q.Name, "", true, true, false, false, nil)
+	}
+	if err != nil {
+		channel.Close()
+		return nil, err
+	}
+
+	m := &HeartbeatMonitor{last: make(map[string]*Heartbeat)}
+	go func() {
+		defer channel.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case d, ok := <-deliveries:
+				if !ok {
+					return
+				}
+				hb := &Heartbeat{}
+				if err := json.Unmarshal(d.Body, hb); err != nil || hb.Endpoint == "" {
+					continue
+				}
+				m.Record(hb)
+			}
+		}
+	}()
+	return m, nil
+}
+
+//Record a heartbeat, replacing the previous one of the same endpoint
+func (m *HeartbeatMonitor) Record(hb *Heartbeat) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.last[hb.Endpoint] = hb
+}
+
+//Forget an endpoint, e.g. once it has been unregistered
+func (m *HeartbeatMonitor) Forget(endpoint string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.last, endpoint)
+}
+
+//The last heartbeat of every endpoint, sorted by endpoint
+func (m *HeartbeatMonitor) Heartbeats() []Heartbeat {
+	return m.filter(func(*Heartbeat) bool { return true })
+}
+
+//The last heartbeat of the endpoints that missed at least missed heartbeats as of now,
+//i.e. whose registration is likely stale. The endpoints without interval are never stale.
+func (m *HeartbeatMonitor) Stale(now time.Time, missed int) []Heartbeat {
+	return m.filter(func(hb *Heartbeat) bool {
+		return hb.Interval > 0 && now.Sub(hb.Timestamp) > time.Duration(missed*hb.Interval)*time.Second
+	})
+}
+
+func (m *HeartbeatMonitor) filter(keep func(*Heartbeat) bool) []Heartbeat {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	res := make([]Heartbeat, 0, len(m.last))
+	for _, hb := range m.last {
+		if keep(hb) {
+			res = append(res, *hb)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Endpoint < res[j].Endpoint })
+	return res
+}
@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+//Handles the body of a request, returning the body of the reply
+type Invoker func(ctx context.Context, body []byte) ([]byte, error)
+
+//Intercepts the handling of every request of a manager, e.g. for auditing or access control.
+//It must call next to continue the handling, unless it replies by itself.
+type Interceptor func(ctx context.Context, body []byte, next Invoker) ([]byte, error)
+
+//Chain the interceptors around invoker, the first one is the outermost
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, body []byte) ([]byte, error) {
+			return interceptor(ctx, body, next)
+		}
+	}
+	return invoker
+}
+
+//Interceptor turning a panic of the handler into an error, so the other requests are not affected
+func RecoverInterceptor(ctx context.Context, body []byte, next Invoker) (res []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			LoggerFromContext(ctx).Errorf("Panic while handling the request: %v\n%s", r, debug.Stack())
+			err = NewSdkError(fmt.Sprintf("panic while handling the request: %v", r))
+		}
+	}()
+	return next(ctx, body)
+}
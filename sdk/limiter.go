@@ -0,0 +1,50 @@
+package sdk
+
+import "sync"
+
+//Limits the number of requests handled at the same time, the limit can be changed while in use
+type limiter struct {
+	mutex sync.Mutex
+	cond  *sync.Cond
+	//0 means unlimited
+	limit int
+	used  int
+}
+
+func newLimiter(limit int) *limiter {
+	l := &limiter{limit: limit}
+	l.cond = sync.NewCond(&l.mutex)
+	return l
+}
+
+//Wait for a free slot and take it
+func (l *limiter) acquire() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for l.limit > 0 && l.used >= l.limit {
+		l.cond.Wait()
+	}
+	l.used++
+}
+
+func (l *limiter) release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.used--
+	l.cond.Signal()
+}
+
+//Lowering the limit does not interrupt the requests being handled, the new ones wait for them
+func (l *limiter) setLimit(limit int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.limit = limit
+	l.cond.Broadcast()
+}
+
+//Number of slots taken
+func (l *limiter) inUse() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.used
+}
@@ -0,0 +1,396 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/op/go-logging"
+)
+
+//The logger used by the SDK and passed to the handlers.
+//Its logging methods match the ones of go-logging.
+type Logger interface {
+	Debug(args ...interface{})
+	Debugf(format string, args ...interface{})
+	Info(args ...interface{})
+	Infof(format string, args ...interface{})
+	Warning(args ...interface{})
+	Warningf(format string, args ...interface{})
+	Error(args ...interface{})
+	Errorf(format string, args ...interface{})
+	Panic(args ...interface{})
+	Panicf(format string, args ...interface{})
+
+	//Returns a logger for a component, having its own level
+	Module(name string) Logger
+	//Returns a logger adding the given key/value pairs to every record
+	With(keyValues ...interface{}) Logger
+}
+
+//Log level
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+	LevelCritical
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarning:
+		return "WARNING"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "CRITICAL"
+	}
+}
+
+//Parse a level name as used in the configuration files
+func ParseLevel(lvlStr string) (Level, error) {
+	switch strings.ToUpper(lvlStr) {
+	case "DEBUG":
+		return LevelDebug, nil
+	case "INFO":
+		return LevelInfo, nil
+	case "WARN", "WARNING":
+		return LevelWarning, nil
+	case "ERROR":
+		return LevelError, nil
+	case "FATAL", "CRITICAL", "PANIC":
+		return LevelCritical, nil
+	default:
+		return LevelDebug, NewSdkError(fmt.Sprintf("unknown log level %q", lvlStr))
+	}
+}
+
+//Log output formats
+const (
+	LogFormatText = "text"
+	LogFormatJson = "json"
+)
+
+//Configuration of the loggers created by NewLogger
+type LogConfig struct {
+	//Default level, DEBUG if empty
+	Level string
+	//Level by module, overriding the default one
+	Levels map[string]string
+	//"text" (coloured, default) or "json"
+	Format string
+	//Where to write the records, stdout if nil
+	Output io.Writer
+}
+
+//The levels of a logger tree, can be changed while the loggers are in use
+type Levels struct {
+	mutex    sync.RWMutex
+	level    Level
+	byModule map[string]Level
+}
+
+//Create the levels with a default level
+func NewLevels(level Level) *Levels {
+	return &Levels{level: level, byModule: make(map[string]Level)}
+}
+
+//Set the default level
+func (lv *Levels) SetDefault(level Level) {
+	lv.mutex.Lock()
+	defer lv.mutex.Unlock()
+	lv.level = level
+}
+
+//Set the level of a module
+func (lv *Levels) Set(module string, level Level) {
+	lv.mutex.Lock()
+	defer lv.mutex.Unlock()
+	lv.byModule[module] = level
+}
+
+//Remove all the module levels
+func (lv *Levels) Reset() {
+	lv.mutex.Lock()
+	defer lv.mutex.Unlock()
+	lv.byModule = make(map[string]Level)
+}
+
+func (lv *Levels) enabled(module string, level Level) bool {
+	lv.mutex.RLock()
+	defer lv.mutex.RUnlock()
+	min, ok := lv.byModule[module]
+	if !ok {
+		min = lv.level
+	}
+	return level >= min
+}
+
+//Destination of the log records
+type sink interface {
+	write(level Level, module, msg string, keyValues []interface{})
+}
+
+type logger struct {
+	module    string
+	keyValues []interface{}
+	levels    *Levels
+	sink      sink
+}
+
+//Create a logger from the configuration, returns an error if a level or the format is unknown
+func NewLogger(cfg LogConfig) (Logger, error) {
+	levels := NewLevels(LevelDebug)
+	if cfg.Level != "" {
+		lvl, err := ParseLevel(cfg.Level)
+		if err != nil {
+			return nil, err
+		}
+		levels.SetDefault(lvl)
+	}
+	for module, lvlStr := range cfg.Levels {
+		lvl, err := ParseLevel(lvlStr)
+		if err != nil {
+			return nil, err
+		}
+		levels.Set(module, lvl)
+	}
+	out := cfg.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	var s sink
+	switch strings.ToLower(cfg.Format) {
+	case "", LogFormatText:
+		s = &textSink{out: out}
+	case LogFormatJson:
+		s = &slogSink{slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	default:
+		return nil, NewSdkError(fmt.Sprintf("unknown log format %q", cfg.Format))
+	}
+	return &logger{levels: levels, sink: s}, nil
+}
+
+//Adapt a log/slog logger, the module becomes the "module" attribute.
+//The levels are applied before calling slog, whose handler can filter further.
+func NewSlogLogger(l *slog.Logger, levels *Levels) Logger {
+	if levels == nil {
+		levels = NewLevels(LevelDebug)
+	}
+	return &logger{levels: levels, sink: &slogSink{l}}
+}
+
+//The levels of a logger created by NewLogger or NewSlogLogger, nil for other implementations
+func LevelsOf(l Logger) *Levels {
+	if lg, ok := l.(*logger); ok {
+		return lg.levels
+	}
+	return nil
+}
+
+func (l *logger) Module(name string) Logger {
+	return &logger{module: name, keyValues: l.keyValues, levels: l.levels, sink: l.sink}
+}
+
+func (l *logger) With(keyValues ...interface{}) Logger {
+	kv := make([]interface{}, 0, len(l.keyValues)+len(keyValues))
+	kv = append(kv, l.keyValues...)
+	kv = append(kv, keyValues...)
+	return &logger{module: l.module, keyValues: kv, levels: l.levels, sink: l.sink}
+}
+
+func (l *logger) log(level Level, msg func() string) {
+	if l.levels.enabled(l.module, level) {
+		l.sink.write(level, l.module, msg(), l.keyValues)
+	}
+}
+
+// use Sprintln to make sure we always get space between arguments, as go-logging does
+func sprint(args []interface{}) func() string {
+	return func() string { return strings.TrimSuffix(fmt.Sprintln(args...), "\n") }
+}
+
+func sprintf(format string, args []interface{}) func() string {
+	return func() string { return fmt.Sprintf(format, args...) }
+}
+
+func (l *logger) Debug(args ...interface{}) { l.log(LevelDebug, sprint(args)) }
+
+func (l *logger) Debugf(format string, args ...interface{}) { l.log(LevelDebug, sprintf(format, args)) }
+
+func (l *logger) Info(args ...interface{}) { l.log(LevelInfo, sprint(args)) }
+
+func (l *logger) Infof(format string, args ...interface{}) { l.log(LevelInfo, sprintf(format, args)) }
+
+func (l *logger) Warning(args ...interface{}) { l.log(LevelWarning, sprint(args)) }
+
+func (l *logger) Warningf(format string, args ...interface{}) {
+	l.log(LevelWarning, sprintf(format, args))
+}
+
+func (l *logger) Error(args ...interface{}) { l.log(LevelError, sprint(args)) }
+
+func (l *logger) Errorf(format string, args ...interface{}) { l.log(LevelError, sprintf(format, args)) }
+
+func (l *logger) Panic(args ...interface{}) {
+	msg := sprint(args)()
+	l.log(LevelCritical, func() string { return msg })
+	panic(msg)
+}
+
+func (l *logger) Panicf(format string, args ...interface{}) {
+	msg := sprintf(format, args)()
+	l.log(LevelCritical, func() string { return msg })
+	panic(msg)
+}
+
+var levelColors = map[Level]int{
+	LevelDebug:    36,
+	LevelInfo:     37,
+	LevelWarning:  33,
+	LevelError:    31,
+	LevelCritical: 35,
+}
+
+//Coloured text records, in the format historically used by the SDK
+type textSink struct {
+	mutex sync.Mutex
+	out   io.Writer
+}
+
+func (s *textSink) write(level Level, module, msg string, keyValues []interface{}) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "\033[%dm%s [%.4s] %6.10s ▶\033[0m %s", levelColors[level], time.Now().Format("15:04:05"), level, module, msg)
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		fmt.Fprintf(&buf, " %v=%v", keyValues[i], keyValues[i+1])
+	}
+	buf.WriteByte('\n')
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.out.Write(buf.Bytes())
+}
+
+var slogLevels = map[Level]slog.Level{
+	LevelDebug:    slog.LevelDebug,
+	LevelInfo:     slog.LevelInfo,
+	LevelWarning:  slog.LevelWarn,
+	LevelError:    slog.LevelError,
+	LevelCritical: slog.LevelError + 4,
+}
+
+type slogSink struct {
+	l *slog.Logger
+}
+
+func (s *slogSink) write(level Level, module, msg string, keyValues []interface{}) {
+	args := make([]interface{}, 0, len(keyValues)+2)
+	if module != "" {
+		args = append(args, "module", module)
+	}
+	args = append(args, keyValues...)
+	s.l.Log(context.Background(), slogLevels[level], msg, args...)
+}
+
+//Adapt a go-logging logger, e.g. one returned by GetLogger, to the Logger interface.
+//The modules are go-logging modules, the key/value pairs are appended to the messages.
+func NewGoLoggingLogger(l *logging.Logger) Logger {
+	if l == nil {
+		return defaultLogger
+	}
+	return &goLogger{l: l}
+}
+
+type goLogger struct {
+	l         *logging.Logger
+	keyValues []interface{}
+}
+
+func (g *goLogger) Module(name string) Logger {
+	return &goLogger{l: logging.MustGetLogger(name), keyValues: g.keyValues}
+}
+
+func (g *goLogger) With(keyValues ...interface{}) Logger {
+	kv := make([]interface{}, 0, len(g.keyValues)+len(keyValues))
+	kv = append(kv, g.keyValues...)
+	kv = append(kv, keyValues...)
+	return &goLogger{l: g.l, keyValues: kv}
+}
+
+func (g *goLogger) fields(msg string) string {
+	var buf bytes.Buffer
+	buf.WriteString(msg)
+	for i := 0; i+1 < len(g.keyValues); i += 2 {
+		fmt.Fprintf(&buf, " %v=%v", g.keyValues[i], g.keyValues[i+1])
+	}
+	return buf.String()
+}
+
+func (g *goLogger) Debug(args ...interface{}) { g.l.Debug(g.fields(sprint(args)())) }
+
+func (g *goLogger) Debugf(format string, args ...interface{}) {
+	g.l.Debug(g.fields(sprintf(format, args)()))
+}
+
+func (g *goLogger) Info(args ...interface{}) { g.l.Info(g.fields(sprint(args)())) }
+
+func (g *goLogger) Infof(format string, args ...interface{}) {
+	g.l.Info(g.fields(sprintf(format, args)()))
+}
+
+func (g *goLogger) Warning(args ...interface{}) { g.l.Warning(g.fields(sprint(args)())) }
+
+func (g *goLogger) Warningf(format string, args ...interface{}) {
+	g.l.Warning(g.fields(sprintf(format, args)()))
+}
+
+func (g *goLogger) Error(args ...interface{}) { g.l.Error(g.fields(sprint(args)())) }
+
+func (g *goLogger) Errorf(format string, args ...interface{}) {
+	g.l.Error(g.fields(sprintf(format, args)()))
+}
+
+func (g *goLogger) Panic(args ...interface{}) { g.l.Panic(g.fields(sprint(args)())) }
+
+func (g *goLogger) Panicf(format string, args ...interface{}) {
+	g.l.Panic(g.fields(sprintf(format, args)()))
+}
+
+//The logger used when none is configured
+var defaultLogger, _ = NewLogger(LogConfig{})
+
+//Context fields attached to the records logged while handling a delivery
+const (
+	LogFieldAction        = "action"
+	LogFieldVnfrID        = "vnfr_id"
+	LogFieldNsrID         = "nsr_id"
+	LogFieldCorrelationID = "correlation_id"
+	LogFieldMethod        = "method"
+)
+
+//The logger of the delivery being handled in ctx, including its context fields
+func LoggerFromContext(ctx context.Context) Logger {
+	if d := deliveryFrom(ctx); d != nil && d.logger != nil {
+		return d.logger
+	}
+	return defaultLogger
+}
+
+//Add context fields to the logger of the delivery being handled in ctx
+func AddLogFields(ctx context.Context, keyValues ...interface{}) {
+	if d := deliveryFrom(ctx); d != nil && d.logger != nil {
+		d.logger = d.logger.With(keyValues...)
+	}
+}
@@ -0,0 +1,201 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	metricsNamespace = "openbaton"
+	metricsSubsystem = "manager"
+	// Label used for operations that could not be identified, e.g. undecodable messages
+	unknownOperation = "unknown"
+)
+
+//Go runtime and process metrics, shared by all the managers of the process
+var processRegistry = prometheus.NewRegistry()
+
+func init() {
+	processRegistry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+}
+
+//Buckets for the duration histograms, lifecycle operations can take several minutes
+var durationBuckets = []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 120, 300, 600}
+
+//Prometheus metrics collected by a Manager.
+//Operations are VNFM actions (INSTANTIATE, SCALE_OUT...) or plugin methods (launchInstanceAndWait...).
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	inFlight        *prometheus.GaugeVec
+	handlerDuration *prometheus.HistogramVec
+	rpcDuration     *prometheus.HistogramVec
+	reconnects      prometheus.Counter
+	panics          prometheus.Counter
+}
+
+//Create the metrics of the manager consuming on queueName. queueDepth is invoked on every scrape.
+func newMetrics(queueName string, queueDepth func() float64) *Metrics {
+	constLabels := prometheus.Labels{"manager": queueName}
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "requests_total",
+			Help:        "Number of requests handled, by operation.",
+			ConstLabels: constLabels,
+		}, []string{"operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "errors_total",
+			Help:        "Number of requests that ended with an error, by operation.",
+			ConstLabels: constLabels,
+		}, []string{"operation"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "in_flight_requests",
+			Help:        "Number of requests currently being handled, by operation.",
+			ConstLabels: constLabels,
+		}, []string{"operation"}),
+		handlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "handler_duration_seconds",
+			Help:        "Time spent handling a request, by operation.",
+			ConstLabels: constLabels,
+			Buckets:     durationBuckets,
+		}, []string{"operation"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "rpc_duration_seconds",
+			Help:        "Time waiting for the reply of an RPC to the NFVO, by target queue and operation.",
+			ConstLabels: constLabels,
+			Buckets:     durationBuckets,
+		}, []string{"queue", "operation"}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "reconnects_total",
+			Help:        "Number of times the connection to the broker was re-established.",
+			ConstLabels: constLabels,
+		}),
+		panics: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "panics_total",
+			Help:        "Number of panics of the handler recovered by the manager.",
+			ConstLabels: constLabels,
+		}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.errors,
+		m.inFlight,
+		m.handlerDuration,
+		m.rpcDuration,
+		m.reconnects,
+		m.panics,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   metricsSubsystem,
+			Name:        "queue_depth",
+			Help:        "Number of messages waiting in the manager queue.",
+			ConstLabels: constLabels,
+		}, queueDepth),
+	)
+	return m
+}
+
+//The Prometheus registry holding the manager metrics, allows to add custom collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+//The http handler exposing the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(prometheus.Gatherers{processRegistry, m.registry}, promhttp.HandlerOpts{})
+}
+
+//Per delivery state shared between the Manager and the handler function through the context.
+type delivery struct {
+	metrics   *Metrics
+	logger    Logger
+	operation string
+	start     time.Time
+	failed    bool
+	//Time to wait for the broker in the RPCs, the timeout of the manager when the delivery was received
+	timeout time.Duration
+}
+
+type deliveryKey struct{}
+
+func withDelivery(ctx context.Context, d *delivery) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, d)
+}
+
+func deliveryFrom(ctx context.Context) *delivery {
+	if ctx == nil {
+		return nil
+	}
+	d, _ := ctx.Value(deliveryKey{}).(*delivery)
+	return d
+}
+
+//Set the operation (action or method name) of the request being handled.
+//Must be called by the handler function as soon as the request is decoded.
+func SetOperation(ctx context.Context, operation string) {
+	SetAttribute(ctx, AttributeOperation, operation)
+	d := deliveryFrom(ctx)
+	if d == nil || d.operation != "" {
+		return
+	}
+	d.operation = operation
+	d.metrics.inFlight.WithLabelValues(operation).Inc()
+}
+
+//Mark the request being handled as failed, even though a reply is sent back.
+func ReportError(ctx context.Context) {
+	if d := deliveryFrom(ctx); d != nil {
+		d.failed = true
+	}
+}
+
+//Record the outcome of a delivery once the handler function returned.
+func (d *delivery) done(err error) {
+	operation := d.operation
+	if operation == "" {
+		operation = unknownOperation
+	} else {
+		d.metrics.inFlight.WithLabelValues(operation).Dec()
+	}
+	d.metrics.requests.WithLabelValues(operation).Inc()
+	if err != nil || d.failed {
+		d.metrics.errors.WithLabelValues(operation).Inc()
+	}
+	d.metrics.handlerDuration.WithLabelValues(operation).Observe(time.Since(d.start).Seconds())
+}
+
+//Record the duration of an RPC executed while handling the delivery in ctx.
+func observeRpc(ctx context.Context, queue string, start time.Time) {
+	d := deliveryFrom(ctx)
+	if d == nil {
+		return
+	}
+	operation := d.operation
+	if operation == "" {
+		operation = unknownOperation
+	}
+	d.metrics.rpcDuration.WithLabelValues(queue, operation).Observe(time.Since(start).Seconds())
+}
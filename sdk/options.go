@@ -0,0 +1,333 @@
+package sdk
+
+import (
+	"crypto/tls"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/streadway/amqp"
+)
+
+//Option configuring how a manager is started
+type Option func(*Options)
+
+//The options a manager is started with, see vnfmsdk.New and pluginsdk.New
+type Options struct {
+	//Name of the manager instance, used for the logger and the plugin id
+	Name string
+	//Type of the manager, e.g. "dummy" or "openstack"
+	Type string
+	//Address of the broker
+	BrokerIp   string
+	BrokerPort int
+	//Credentials used to register to the NFVO
+	Username string
+	Password string
+	//Connect to the broker with amqps if not nil
+	TLS *tls.Config
+	//Seconds to wait for the broker and the NFVO while registering, and for the broker when dialling and in the RPCs.
+	//The replies of the NFVO to the RPCs are awaited until the request is cancelled.
+	Timeout int
+	//Number of consumers on the manager queue
+	Workers int
+	//Max number of requests handled at the same time, unlimited if 0
+	MaxInFlight int
+	//The logger of the manager, created from LogConfig if nil
+	Logger    Logger
+	LogConfig LogConfig
+	//Address of the http server exposing the Prometheus metrics, disabled if empty
+	MetricsAddress string
+	//Address of the http server exposing the health probes, disabled if empty
+	HealthAddress string
+	//Exporter of the traces: "none" (default) or "stdout"
+	TraceExporter string
+	//Persistence available to the manager and its handler, in memory if nil
+	Store Store
+	//Interceptors of the requests, the first one is the outermost
+	Interceptors []Interceptor
+	//File caching the credentials returned by the registration, reused on restart if still valid.
+	//Disabled if empty.
+	CredentialsFile string
+	//Version of the manager, reported in the heartbeats
+	Version string
+	//Describes the manager to the NFVO and the tools, in the registration message (the Endpoint of a VNFM) and in the heartbeats.
+	//The SDK sets MetadataHandlerVersion and MetadataCapabilities.
+	Metadata map[string]string
+	//Seconds between the heartbeats published by the manager, disabled if 0
+	HeartbeatInterval int
+	//Exchange and routing key of the heartbeats, OpenbatonExchangeName and DefaultHeartbeatRoutingKey if empty
+	HeartbeatExchange   string
+	HeartbeatRoutingKey string
+	//Exchange receiving the requests the handler failed on (e.g. undecodable), with diagnostic headers.
+	//Declared as a durable fanout exchange, disabled if empty.
+	DeadLetterExchange string
+	//Durable queue bound to DeadLetterExchange, not declared if empty
+	DeadLetterQueue string
+	//Builds the reply sent when the handler fails, set by vnfmsdk and pluginsdk
+	ErrorReply ErrorReplyFunc
+	//File the deliveries and replies are appended to, with the passwords redacted, see Replay. Disabled if empty.
+	CaptureFile string
+	//Connection shared with other managers, dialled by the manager if nil.
+	//The manager consumes with the broker user of this connection, not with its own credentials.
+	Connection *amqp.Connection
+	//Supervisor only: whether the managers consume on the connection of the supervisor, see WithSharedConnection
+	SharedConnection bool
+	//Whether the manager unregisters on SIGINT and SIGTERM, true by default
+	HandleSignals bool
+	//The configuration file, reloaded on SIGHUP or when modified if not empty
+	ConfigFile string
+
+	//VNFM only: the endpoint (queue) of the VNFM, the type if empty
+	Endpoint    string
+	Description string
+	//VNFM only: whether the VNFM allocates the resources
+	Allocate bool
+	//VNFM only: whether the changes of status of the VNFRs returned by the handler are checked
+	//against catalogue.VNFRStates and catalogue.VNFCStates and appended to their lifecycle event history
+	CheckTransitions bool
+
+	//Plugin only: the types the network and image replies are decoded into
+	Network catalogue.BaseNetworkInt
+	Image   catalogue.BaseImageInt
+}
+
+//Create the options with the defaults and apply opts, in order
+func NewOptions(opts ...Option) *Options {
+	o := &Options{
+		Type:       "unknown",
+		BrokerIp:   "localhost",
+		BrokerPort: 5672,
+		Username:   "openbaton-manager-user",
+		Password:   "openbaton",
+		Timeout:    2,
+		Workers:    5,
+		LogConfig:  LogConfig{Level: "DEBUG"},
+
+		HandleSignals: true,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+//Check the options, returning all the problems found in a *ConfigError
+func (o *Options) Validate() error {
+	cfgErr := &ConfigError{}
+	cfgErr.Check(o.Type != "", "type must not be empty")
+	cfgErr.Check(o.BrokerIp != "", "brokerIp must not be empty")
+	cfgErr.Check(o.MaxInFlight >= 0, "maxInFlight must not be negative, got %d", o.MaxInFlight)
+	cfgErr.Check(o.HeartbeatInterval >= 0, "heartbeatInterval must not be negative, got %d", o.HeartbeatInterval)
+	cfgErr.CheckManager(o.Workers, o.BrokerPort, o.Timeout, o.LogConfig.Level, o.LogConfig.Levels, o.LogConfig.Format, o.TraceExporter)
+	return cfgErr.Err()
+}
+
+//Set the name of the manager instance
+func WithName(name string) Option {
+	return func(o *Options) {
+		o.Name = name
+	}
+}
+
+//Set the type of the manager
+func WithType(typ string) Option {
+	return func(o *Options) {
+		o.Type = typ
+	}
+}
+
+//Set the address of the broker
+func WithBroker(ip string, port int) Option {
+	return func(o *Options) {
+		o.BrokerIp = ip
+		o.BrokerPort = port
+	}
+}
+
+//Set the credentials used to register to the NFVO
+func WithCredentials(username, password string) Option {
+	return func(o *Options) {
+		o.Username = username
+		o.Password = password
+	}
+}
+
+//Connect to the broker with amqps using the given configuration
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *Options) {
+		o.TLS = cfg
+	}
+}
+
+//Set the seconds to wait for the broker and the NFVO while registering, and for the broker when dialling and in the RPCs
+func WithTimeout(seconds int) Option {
+	return func(o *Options) {
+		o.Timeout = seconds
+	}
+}
+
+//Set the number of consumers on the manager queue
+func WithWorkers(workers int) Option {
+	return func(o *Options) {
+		o.Workers = workers
+	}
+}
+
+//Limit the number of requests handled at the same time, 0 means unlimited
+func WithMaxInFlight(max int) Option {
+	return func(o *Options) {
+		o.MaxInFlight = max
+	}
+}
+
+//Use the given logger instead of the one created from the configuration
+func WithLogger(l Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+//Configure the logger created when none is given with WithLogger
+func WithLogConfig(cfg LogConfig) Option {
+	return func(o *Options) {
+		o.LogConfig = cfg
+	}
+}
+
+//Expose the Prometheus metrics on http://addr/metrics
+func WithMetrics(addr string) Option {
+	return func(o *Options) {
+		o.MetricsAddress = addr
+	}
+}
+
+//Expose the health probes on http://addr/healthz and http://addr/readyz
+func WithHealth(addr string) Option {
+	return func(o *Options) {
+		o.HealthAddress = addr
+	}
+}
+
+//Set the exporter of the traces: "none" or "stdout"
+func WithTraceExporter(exporter string) Option {
+	return func(o *Options) {
+		o.TraceExporter = exporter
+	}
+}
+
+//Set the persistence available to the manager and its handler
+func WithStore(s Store) Option {
+	return func(o *Options) {
+		o.Store = s
+	}
+}
+
+//Add interceptors around the handling of every request
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(o *Options) {
+		o.Interceptors = append(o.Interceptors, interceptors...)
+	}
+}
+
+//Set the version of the manager reported in the heartbeats
+func WithVersion(version string) Option {
+	return func(o *Options) {
+		o.Version = version
+	}
+}
+
+//Add metadata describing the manager, see Options.Metadata
+func WithMetadata(key, value string) Option {
+	return func(o *Options) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		o.Metadata[key] = value
+	}
+}
+
+//Publish a heartbeat every interval seconds, on the default exchange and routing key if empty
+func WithHeartbeat(interval int, exchange, routingKey string) Option {
+	return func(o *Options) {
+		o.HeartbeatInterval = interval
+		o.HeartbeatExchange = exchange
+		o.HeartbeatRoutingKey = routingKey
+	}
+}
+
+//Publish the requests the handler failed on to exchange, and declare queue bound to it if not empty
+func WithDeadLetter(exchange, queue string) Option {
+	return func(o *Options) {
+		o.DeadLetterExchange = exchange
+		o.DeadLetterQueue = queue
+	}
+}
+
+//Replace the reply sent when the handler fails
+func WithErrorReply(f ErrorReplyFunc) Option {
+	return func(o *Options) {
+		o.ErrorReply = f
+	}
+}
+
+//Consume on a connection shared with other managers instead of dialling one with the credentials returned
+//by the registration. The broker user of conn must be allowed to consume on the manager queue.
+func WithConnection(conn *amqp.Connection) Option {
+	return func(o *Options) {
+		o.Connection = conn
+	}
+}
+
+//Supervisor only: make all the managers consume on one connection of the supervisor broker user, instead of
+//one connection each with the private credentials returned by their registration. The managers are not
+//isolated from each other anymore: the user must be granted the configure, write and read permissions
+//on the queues of all the managers. Disabled by default.
+func WithSharedConnection(share bool) Option {
+	return func(o *Options) {
+		o.SharedConnection = share
+	}
+}
+
+//Set whether the manager unregisters on SIGINT and SIGTERM, disable it when the application handles the signals
+func WithSignalHandling(handle bool) Option {
+	return func(o *Options) {
+		o.HandleSignals = handle
+	}
+}
+
+//Cache the credentials returned by the registration in path, so a restarted manager does not register again
+func WithCredentialsCache(path string) Option {
+	return func(o *Options) {
+		o.CredentialsFile = path
+	}
+}
+
+//Append the deliveries and replies to the file at path, to replay them later
+func WithCapture(path string) Option {
+	return func(o *Options) {
+		o.CaptureFile = path
+	}
+}
+
+//Reload the settings of the configuration file on SIGHUP or when it is modified, see Manager.Reload
+func WithConfigFile(path string) Option {
+	return func(o *Options) {
+		o.ConfigFile = path
+	}
+}
+
+//The logger of the options, or a new one created from cfg if none was given
+func (o *Options) LoggerOrNew(cfg LogConfig) (Logger, error) {
+	if o.Logger != nil {
+		return o.Logger, nil
+	}
+	return NewLogger(cfg)
+}
+
+//The logger of the options, the default one if it cannot be created
+func (o *Options) logger() Logger {
+	l, err := o.LoggerOrNew(o.LogConfig)
+	if err != nil {
+		return defaultLogger
+	}
+	return l
+}
@@ -0,0 +1,177 @@
+package sdk
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+//How often the configuration file is checked for modifications
+const configPollInterval = 5 * time.Second
+
+//The outcome of a Reload, settings are named as the keys of the configuration file
+type ReloadReport struct {
+	//Settings changed and applied while serving
+	Applied []string
+	//Settings changed but applied only after a restart.
+	//The timeout is in both lists: the dials and RPCs use the new one, the registration done at start the old one.
+	RestartRequired []string
+}
+
+//Apply the settings of o that can change while serving: log levels, workers, maxInFlight and timeout.
+//The other settings that differ from the current ones are reported as requiring a restart.
+//Nothing is applied if o is not valid.
+func (manager *Manager) Reload(o *Options) (*ReloadReport, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	manager.mutex.Lock()
+	current := *manager.options
+	manager.mutex.Unlock()
+	report := &ReloadReport{}
+
+	restart := func(name string, changed bool) {
+		if changed {
+			report.RestartRequired = append(report.RestartRequired, name)
+		}
+	}
+	restart("type", o.Type != current.Type)
+	restart("brokerIp", o.BrokerIp != current.BrokerIp)
+	restart("brokerPort", o.BrokerPort != current.BrokerPort)
+	restart("username", o.Username != current.Username)
+	restart("password", o.Password != current.Password)
+	restart("endpoint", o.Endpoint != current.Endpoint)
+	restart("description", o.Description != current.Description)
+	restart("allocate", o.Allocate != current.Allocate)
+	restart("checkTransitions", o.CheckTransitions != current.CheckTransitions)
+	restart("logFormat", o.LogConfig.Format != current.LogConfig.Format)
+	restart("metricsAddress", o.MetricsAddress != current.MetricsAddress)
+	restart("healthAddress", o.HealthAddress != current.HealthAddress)
+	restart("traceExporter", o.TraceExporter != current.TraceExporter)
+	restart("credentialsFile", o.CredentialsFile != current.CredentialsFile)
+	restart("heartbeatInterval", o.HeartbeatInterval != current.HeartbeatInterval)
+	restart("heartbeatExchange", o.HeartbeatExchange != current.HeartbeatExchange)
+	restart("heartbeatRoutingKey", o.HeartbeatRoutingKey != current.HeartbeatRoutingKey)
+	restart("deadLetterExchange", o.DeadLetterExchange != current.DeadLetterExchange)
+	restart("deadLetterQueue", o.DeadLetterQueue != current.DeadLetterQueue)
+	restart("captureFile", o.CaptureFile != current.CaptureFile)
+
+	levelChanged := o.LogConfig.Level != current.LogConfig.Level
+	levelsChanged := !reflect.DeepEqual(o.LogConfig.Levels, current.LogConfig.Levels) &&
+		(len(o.LogConfig.Levels) > 0 || len(current.LogConfig.Levels) > 0)
+	if levels := LevelsOf(manager.logger); levels != nil {
+		if levelChanged {
+			lvl, _ := ParseLevel(o.LogConfig.Level)
+			if o.LogConfig.Level == "" {
+				lvl = LevelDebug
+			}
+			levels.SetDefault(lvl)
+			report.Applied = append(report.Applied, "logLevel")
+		}
+		if levelsChanged {
+			levels.Reset()
+			for module, lvlStr := range o.LogConfig.Levels {
+				lvl, _ := ParseLevel(lvlStr)
+				levels.Set(module, lvl)
+			}
+			report.Applied = append(report.Applied, "logLevels")
+		}
+	} else {
+		//the levels of a custom logger are not managed by the SDK
+		restart("logLevel", levelChanged)
+		restart("logLevels", levelsChanged)
+	}
+
+	if o.Workers != current.Workers {
+		if err := manager.SetWorkers(o.Workers); err != nil {
+			return report, err
+		}
+		report.Applied = append(report.Applied, "workers")
+	}
+	if o.MaxInFlight != current.MaxInFlight {
+		manager.SetMaxInFlight(o.MaxInFlight)
+		report.Applied = append(report.Applied, "maxInFlight")
+	}
+	if o.Timeout != current.Timeout {
+		manager.SetTimeout(o.Timeout)
+		report.Applied = append(report.Applied, "timeout")
+		//also used by the registration, which is done once at start
+		restart("timeout", true)
+	}
+
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.options.LogConfig.Level = o.LogConfig.Level
+	manager.options.LogConfig.Levels = o.LogConfig.Levels
+	manager.options.Workers = o.Workers
+	manager.options.MaxInFlight = o.MaxInFlight
+	manager.options.Timeout = o.Timeout
+	return report, nil
+}
+
+//Reload the configuration on SIGHUP and whenever the file at path is modified, until the manager is shut down.
+//load returns the options of the new configuration, applied over the current ones.
+func (manager *Manager) WatchConfig(path string, load func() ([]Option, error)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	ticker := time.NewTicker(configPollInterval)
+	modTime := fileModTime(path)
+
+	go func() {
+		defer signal.Stop(hup)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-manager.done:
+				return
+			case <-hup:
+				manager.logger.Infof("Received SIGHUP, reloading %s", path)
+			case <-ticker.C:
+				t := fileModTime(path)
+				if t.Equal(modTime) {
+					continue
+				}
+				manager.logger.Infof("%s modified, reloading", path)
+			}
+			modTime = fileModTime(path)
+			manager.reloadWith(load)
+		}
+	}()
+}
+
+func (manager *Manager) reloadWith(load func() ([]Option, error)) {
+	opts, err := load()
+	if err != nil {
+		manager.logger.Errorf("Error while reloading the configuration, keeping the current one: %v", err)
+		return
+	}
+	manager.mutex.Lock()
+	o := *manager.options
+	manager.mutex.Unlock()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	report, err := manager.Reload(&o)
+	if err != nil {
+		manager.logger.Errorf("Error while reloading the configuration: %v", err)
+	}
+	if report == nil {
+		return
+	}
+	if len(report.Applied) > 0 {
+		manager.logger.Infof("Configuration reloaded, applied %v", report.Applied)
+	}
+	if len(report.RestartRequired) > 0 {
+		manager.logger.Warningf("Changes to %v are applied only after a restart", report.RestartRequired)
+	}
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
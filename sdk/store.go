@@ -0,0 +1,123 @@
+package sdk
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+//Returned by Store.Get when the key does not exist
+var ErrNotFound = errors.New("not found")
+
+//Key/value persistence available to a manager and its handler
+type Store interface {
+	//The value of key, ErrNotFound if it does not exist
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	//Remove the key, no error if it does not exist
+	Delete(key string) error
+}
+
+//A Store keeping the values in memory, lost when the process ends
+type MemoryStore struct {
+	mutex  sync.RWMutex
+	values map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	value, ok := s.values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+func (s *MemoryStore) Put(key string, value []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+//A Store keeping every value in a file of a directory, readable only by the owner
+type FileStore struct {
+	mutex sync.Mutex
+	dir   string
+}
+
+//Create the store, creating dir if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+//Keys can contain slashes, e.g. "credentials/vnfm-dummy", but cannot leave the directory
+func (s *FileStore) path(key string) (string, error) {
+	p := filepath.Join(s.dir, filepath.FromSlash(key))
+	if key == "" || !strings.HasPrefix(p, filepath.Clean(s.dir)+string(filepath.Separator)) {
+		return "", NewSdkError("invalid store key " + key)
+	}
+	return p, nil
+}
+
+func (s *FileStore) Get(key string) ([]byte, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	value, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+//Write the value to a temporary file renamed over the old one, so readers never see a partial value
+func (s *FileStore) Put(key string, value []byte) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, value, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func (s *FileStore) Delete(key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/streadway/amqp"
+)
+
+//A manager run by a Supervisor, implemented by *vnfmsdk.Vnfm and *pluginsdk.Plugin
+type Service interface {
+	//The options the service was created with, the supervisor sets the shared connection in them if enabled
+	Options() *Options
+	//Register and serve until ctx is cancelled, unregistering in any case
+	ServeContext(ctx context.Context) error
+	//The manager consuming the requests, nil until registered
+	Manager() *Manager
+}
+
+type supervised struct {
+	service Service
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+}
+
+//Runs several managers in one process, e.g. two VIM drivers or a VNFM and its plugin.
+//Each manager has its own queue, logger and lifecycle: a fatal error or Stop unregisters only the concerned manager.
+//By default each manager consumes on its own connection, with the private credentials returned by its registration.
+//With WithSharedConnection they share one connection of the supervisor broker user instead, each with its own
+//channel: a manager whose queue that user cannot consume on fails when it starts.
+type Supervisor struct {
+	options *Options
+	logger  Logger
+	//The connection shared by the managers, nil unless enabled
+	conn     *amqp.Connection
+	mutex    sync.Mutex
+	services map[string]*supervised
+	//Serves the metrics of all the managers, nil if disabled
+	metricsServer *http.Server
+	//Set by Serve, the services added later are started at once
+	ctx context.Context
+	wg  sync.WaitGroup
+}
+
+//Create a supervisor connected to the broker given in the options
+func NewSupervisor(opts ...Option) (*Supervisor, error) {
+	o := NewOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Supervisor{
+		options:  o,
+		logger:   o.logger().Module("supervisor"),
+		services: make(map[string]*supervised),
+	}
+	if o.SharedConnection {
+		conn, err := s.dial()
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+	}
+	if o.MetricsAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", s.MetricsHandler())
+		s.metricsServer = &http.Server{Addr: o.MetricsAddress, Handler: mux}
+		go serveHTTP(s.metricsServer, s.logger)
+	}
+	return s, nil
+}
+
+func (s *Supervisor) dial() (*amqp.Connection, error) {
+	return Dial(s.options)
+}
+
+//The connection shared by the managers, nil unless enabled with WithSharedConnection
+func (s *Supervisor) Connection() *amqp.Connection {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.conn
+}
+
+//Add a service with a unique name, started at once if the supervisor is serving
+func (s *Supervisor) Add(name string, service Service) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.services[name]; ok {
+		return NewSdkError(fmt.Sprintf("service %s already added", name))
+	}
+	o := service.Options()
+	if s.conn != nil {
+		o.Connection = s.conn
+	}
+	o.HandleSignals = false
+	sv := &supervised{service: service}
+	s.services[name] = sv
+	if s.ctx != nil {
+		s.start(name, sv)
+	}
+	return nil
+}
+
+//Must hold the mutex
+func (s *Supervisor) start(name string, sv *supervised) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	sv.cancel = cancel
+	sv.done = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(sv.done)
+		sv.err = sv.service.ServeContext(ctx)
+		if sv.err != nil {
+			s.logger.Errorf("Service %s stopped: %v", name, sv.err)
+		} else {
+			s.logger.Infof("Service %s stopped", name)
+		}
+	}()
+}
+
+//Unregister and stop a service, the other ones keep running
+func (s *Supervisor) Stop(name string) error {
+	s.mutex.Lock()
+	sv, ok := s.services[name]
+	var cancel context.CancelFunc
+	var done chan struct{}
+	if ok {
+		cancel, done = sv.cancel, sv.done
+	}
+	s.mutex.Unlock()
+	if !ok {
+		return NewSdkError(fmt.Sprintf("unknown service %s", name))
+	}
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	<-done
+	//Set before done is closed
+	return sv.err
+}
+
+//Run all the services until ctx is cancelled or SIGINT or SIGTERM is received, then unregister them.
+//Returns when all the services have stopped, with the errors of the ones that failed.
+func (s *Supervisor) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	if s.options.HandleSignals {
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+	}
+	go func() {
+		select {
+		case sig := <-signals:
+			s.logger.Infof("Received %s, stopping all the services", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	s.mutex.Lock()
+	s.ctx = ctx
+	names := make([]string, 0, len(s.services))
+	for name := range s.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.start(name, s.services[name])
+	}
+	s.mutex.Unlock()
+
+	if s.conn != nil {
+		go s.watchConnection(ctx)
+	}
+	s.wg.Wait()
+	if conn := s.Connection(); conn != nil {
+		conn.Close()
+	}
+	if s.metricsServer != nil {
+		shutdownHTTP(s.metricsServer, s.logger)
+	}
+
+	var failed []string
+	for _, name := range names {
+		if err := s.services[name].err; err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(failed) > 0 {
+		return NewSdkError(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+//Re-establish the shared connection when lost and make the managers consume again
+func (s *Supervisor) watchConnection(ctx context.Context) {
+	for {
+		closeChan := s.Connection().NotifyClose(make(chan *amqp.Error, 1))
+		select {
+		case <-ctx.Done():
+			return
+		case amqpErr, ok := <-closeChan:
+			if !ok || amqpErr == nil {
+				return
+			}
+			s.logger.Errorf("Connection to the broker lost: %v", amqpErr)
+		}
+		backoff := time.Second
+		var conn *amqp.Connection
+		for {
+			var err error
+			if conn, err = s.dial(); err == nil {
+				break
+			}
+			s.logger.Errorf("Error while reconnecting, retrying in %s: %v", backoff, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+		}
+		//Reattaching redeclares the queues on the broker, not done while holding the mutex
+		s.mutex.Lock()
+		s.conn = conn
+		managers := make(map[string]*Manager)
+		for name, sv := range s.services {
+			sv.service.Options().Connection = conn
+			if m := sv.service.Manager(); m != nil {
+				managers[name] = m
+			}
+		}
+		s.mutex.Unlock()
+		for name, m := range managers {
+			if err := m.reattach(conn); err != nil {
+				s.logger.Errorf("Error while reattaching %s: %v", name, err)
+				m.Fail(err)
+			}
+		}
+		s.logger.Infof("Reconnected to the broker")
+	}
+}
+
+//The http handler exposing the metrics of all the managers, distinguished by the "manager" label
+func (s *Supervisor) MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(prometheus.GathererFunc(s.gather), promhttp.HandlerOpts{})
+}
+
+func (s *Supervisor) gather() ([]*dto.MetricFamily, error) {
+	gatherers := prometheus.Gatherers{processRegistry}
+	s.mutex.Lock()
+	for _, sv := range s.services {
+		if m := sv.service.Manager(); m != nil {
+			gatherers = append(gatherers, m.metrics.registry)
+		}
+	}
+	s.mutex.Unlock()
+	return gatherers.Gather()
+}
@@ -0,0 +1,141 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/openbaton/go-openbaton/sdk"
+
+// Span attributes set by the SDK
+const (
+	AttributeOperation     = "openbaton.operation"
+	AttributeVnfrID        = "openbaton.vnfr.id"
+	AttributeNsrID         = "openbaton.nsr.id"
+	AttributeCorrelationID = "messaging.message.conversation_id"
+	AttributeQueue         = "messaging.destination.name"
+)
+
+// Trace exporters selectable from the configuration
+const (
+	TraceExporterNone   = "none"
+	TraceExporterStdout = "stdout"
+)
+
+//The trace context is propagated in the AMQP headers using the W3C Trace Context format
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+//Adapts the AMQP headers of a message to an OpenTelemetry carrier
+type headersCarrier amqp.Table
+
+func (c headersCarrier) Get(key string) string {
+	if v, ok := c[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func (c headersCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+//Create the TracerProvider for the given exporter name, the returned function flushes and stops it.
+//The "none" exporter (or an empty name) keeps the global provider, a no-op unless set by the application.
+func NewTracerProvider(exporter string) (trace.TracerProvider, func(context.Context) error, error) {
+	switch strings.ToLower(exporter) {
+	case "", TraceExporterNone:
+		return otel.GetTracerProvider(), func(context.Context) error { return nil }, nil
+	case TraceExporterStdout:
+		tp, err := NewStdoutTracerProvider(os.Stdout)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tp, tp.Shutdown, nil
+	default:
+		return nil, nil, NewSdkError(fmt.Sprintf("unknown trace exporter %q", exporter))
+	}
+}
+
+//Create a TracerProvider writing the spans as JSON to w, usable without any collector
+func NewStdoutTracerProvider(w io.Writer) (*sdktrace.TracerProvider, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), nil
+}
+
+//Set the TracerProvider used by the manager to create the spans of the deliveries
+func (manager *Manager) SetTracerProvider(tp trace.TracerProvider) {
+	manager.tracer = tp.Tracer(tracerName)
+}
+
+//Start a span child of the one in ctx, e.g. around a handler invocation
+func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, name)
+}
+
+//Set a string attribute on the span of ctx, ignored if empty
+func SetAttribute(ctx context.Context, key, value string) {
+	if value == "" {
+		return
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(key, value))
+}
+
+//Record err on the span and mark it as failed
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+//Start the span of a delivery, continuing the trace of the sender if any
+func (manager *Manager) startDeliverySpan(ctx context.Context, d amqp.Delivery) (context.Context, trace.Span) {
+	if d.Headers != nil {
+		ctx = propagator.Extract(ctx, headersCarrier(d.Headers))
+	}
+	return manager.tracer.Start(ctx, "handle "+manager.queueName,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String(AttributeQueue, manager.queueName),
+			attribute.String(AttributeCorrelationID, d.CorrelationId),
+		))
+}
+
+//Start the span of an RPC and inject its context in the headers of the outgoing message
+func startRpcSpan(ctx context.Context, queue, corrId string) (context.Context, trace.Span, amqp.Table) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, "rpc "+queue,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String(AttributeQueue, queue),
+			attribute.String(AttributeCorrelationID, corrId),
+		))
+	return ctx, span, traceHeaders(ctx)
+}
+
+//The AMQP headers carrying the trace context of ctx
+func traceHeaders(ctx context.Context) amqp.Table {
+	headers := amqp.Table{}
+	propagator.Inject(ctx, headersCarrier(headers))
+	return headers
+}
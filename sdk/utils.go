@@ -1,6 +1,8 @@
 package sdk
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -8,6 +10,7 @@ import (
 	"os"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/op/go-logging"
@@ -16,7 +19,8 @@ import (
 	"github.com/streadway/amqp"
 )
 
-var log *logging.Logger
+//Configures the go-logging backend the first time GetLogger is called
+var goLoggingSetup sync.Once
 
 type SdkError struct {
 	err error
@@ -30,22 +34,29 @@ func NewSdkError(msg string) *SdkError {
 	return &SdkError{errors.New(msg)}
 }
 
-//Obtain the Logger pre-formatted
-func GetLogger(name string, levelStr string) (*logging.Logger) {
-	if log != nil {
-		return log
-	}
-	level := toLogLevel(levelStr)
-	log = logging.MustGetLogger(name)
-	var format = logging.MustStringFormatter(
-		`%{color}%{time:15:04:05} [%{level:.4s}] %{module:6.10s} -> %{longfunc:10.10s} ▶ %{color:reset} %{message}`,
-	)
-	backend := logging.NewLogBackend(os.Stdout, "", 0)
-	backendFormatter := logging.NewBackendFormatter(backend, format)
-	logging.SetLevel(level, "")
-	logging.SetBackend(backendFormatter)
+//Obtain the go-logging Logger pre-formatted for the module name.
+//
+//Deprecated: use NewLogger or LoggerFromContext, which support per module levels and JSON output.
+func GetLogger(name string, levelStr string) *logging.Logger {
+	goLoggingSetup.Do(func() {
+		var format = logging.MustStringFormatter(
+			`%{color}%{time:15:04:05} [%{level:.4s}] %{module:6.10s} -> %{longfunc:10.10s} ▶ %{color:reset} %{message}`,
+		)
+		backend := logging.NewLogBackend(os.Stdout, "", 0)
+		backendFormatter := logging.NewBackendFormatter(backend, format)
+		logging.SetBackend(backendFormatter)
+	})
+	logging.SetLevel(toLogLevel(levelStr), name)
+	return logging.MustGetLogger(name)
+}
 
-	return log
+//Create a logger with the given default level, falling back to DEBUG if the level is unknown
+func levelLogger(levelStr string) Logger {
+	l, err := NewLogger(LogConfig{Level: levelStr})
+	if err != nil {
+		return defaultLogger
+	}
+	return l
 }
 
 func toLogLevel(lvlStr string) (lvl logging.Level) {
@@ -62,9 +73,7 @@ func toLogLevel(lvlStr string) (lvl logging.Level) {
 	case "ERROR":
 		lvl = logging.ERROR
 
-	case "FATAL":
-	case "CRITICAL":
-	case "PANIC":
+	case "FATAL", "CRITICAL", "PANIC":
 		lvl = logging.CRITICAL
 
 	default:
@@ -88,48 +97,21 @@ func randInt(min int, max int) int {
 
 // Execute a AMQP RPC call to a specific queue
 func Rpc(queue string, message interface{}, conn *amqp.Connection, l *logging.Logger) ([]byte, error) {
+	return RpcContext(context.Background(), queue, message, conn, NewGoLoggingLogger(l))
+}
 
-	l.Info("Executing RPC to queue: %s", queue)
-	l.Debug("Getting Channel for RPC")
-	channel, err := conn.Channel()
-	defer channel.Close()
-	l.Debug("Got Channel for RPC")
-
-	var q amqp.Queue
-	var msgs <-chan amqp.Delivery
+// Execute a AMQP RPC call to a specific queue on behalf of the request being handled in ctx, giving up when ctx is done
+func RpcContext(ctx context.Context, queue string, message interface{}, conn *amqp.Connection, l Logger) ([]byte, error) {
 
-	l.Debug("Declaring Queue for RPC")
-	q, err = channel.QueueDeclare(
-		"",
-		false,
-		false,
-		true,
-		false,
-		nil,
-	)
-	if err != nil {
-		debug.PrintStack()
-		l.Errorf("Failed to declare a queue: %v", err)
-		return nil, err
-	}
-	l.Debug("Declared Queue for RPC")
-	l.Debug("Registering consumer for RPC")
-	msgs, err = channel.Consume(
-		q.Name,
-		"",
-		true,
-		false,
-		false,
-		false,
-		nil,
-	)
+	l = l.Module("rpc")
+	l.Infof("Executing RPC to queue: %s", queue)
+	r, err := openRpc(ctx, conn, l)
 	if err != nil {
-		debug.PrintStack()
-		l.Errorf("Failed to register a consumer: %v", err)
 		return nil, err
 	}
+	channel, q, msgs := r.channel, r.queue, r.msgs
+	defer channel.Close()
 
-	l.Debug("Registered consumer for RPC")
 	corrId := randomString(32)
 
 	mrs, err := json.Marshal(message)
@@ -137,13 +119,16 @@ func Rpc(queue string, message interface{}, conn *amqp.Connection, l *logging.Lo
 		l.Errorf("Error while marshaling: %v", err)
 		return nil, err
 	}
-	l.Debug("Publishing message to queue %s", queue)
+	_, span, headers := startRpcSpan(ctx, queue, corrId)
+	defer span.End()
+	l.Debugf("Publishing message to queue %s", queue)
 	err = channel.Publish(
 		OpenbatonExchangeName, // exchange
 		queue,                 // routing key
 		false,                 // mandatory
 		false,                 // immediate
 		amqp.Publishing{
+			Headers:       headers,
 			ContentType:   AmqpContentType,
 			CorrelationId: corrId,
 			ReplyTo:       q.Name,
@@ -151,22 +136,125 @@ func Rpc(queue string, message interface{}, conn *amqp.Connection, l *logging.Lo
 		})
 
 	if err != nil {
+		RecordError(span, err)
 		l.Errorf("Failed to publish a message")
 		return nil, err
 	}
 	l.Debugf("Published message to queue %s", queue)
+	start := time.Now()
+	defer observeRpc(ctx, queue, start)
 
-	for d := range msgs {
-		if corrId == d.CorrelationId {
-			l.Debug("Received Response")
-			return d.Body, nil
+	for {
+		select {
+		case d, ok := <-msgs:
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("Not found message with correlationId [%s]", corrId))
+			}
+			if corrId == d.CorrelationId {
+				l.Debug("Received Response")
+				return d.Body, nil
+			}
+		case <-ctx.Done():
+			RecordError(span, ctx.Err())
+			return nil, ctx.Err()
 		}
 	}
-	return nil, errors.New(fmt.Sprintf("Not found message with correlationId [%s]", corrId))
+}
+
+//The channel, reply queue and consumer of an RPC
+type rpcChannel struct {
+	channel *amqp.Channel
+	queue   amqp.Queue
+	msgs    <-chan amqp.Delivery
+	err     error
+}
+
+//Open the channel of an RPC, waiting for the broker at most the timeout of the delivery being handled in ctx, if any
+func openRpc(ctx context.Context, conn *amqp.Connection, l Logger) (*rpcChannel, error) {
+	opened := make(chan *rpcChannel, 1)
+	go func() {
+		opened <- declareRpc(conn, l)
+	}()
+
+	var timeout <-chan time.Time
+	if d := deliveryFrom(ctx); d != nil && d.timeout > 0 {
+		timer := time.NewTimer(d.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error
+	select {
+	case r := <-opened:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return r, nil
+	case <-timeout:
+		err = errors.New(fmt.Sprintf("no answer from the broker after %s", deliveryFrom(ctx).timeout))
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	//the channel opened too late is not used
+	go func() {
+		if r := <-opened; r.channel != nil {
+			r.channel.Close()
+		}
+	}()
+	return nil, err
+}
+
+func declareRpc(conn *amqp.Connection, l Logger) *rpcChannel {
+	l.Debug("Getting Channel for RPC")
+	channel, err := conn.Channel()
+	if err != nil {
+		l.Errorf("Failed to open a channel: %v", err)
+		return &rpcChannel{err: err}
+	}
+	l.Debug("Got Channel for RPC")
+
+	l.Debug("Declaring Queue for RPC")
+	q, err := channel.QueueDeclare(
+		"",
+		false,
+		false,
+		true,
+		false,
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		l.Errorf("Failed to declare a queue: %v", err)
+		channel.Close()
+		return &rpcChannel{err: err}
+	}
+	l.Debug("Declared Queue for RPC")
+	l.Debug("Registering consumer for RPC")
+	msgs, err := channel.Consume(
+		q.Name,
+		"",
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		l.Errorf("Failed to register a consumer: %v", err)
+		channel.Close()
+		return &rpcChannel{err: err}
+	}
+	l.Debug("Registered consumer for RPC")
+	return &rpcChannel{channel: channel, queue: q, msgs: msgs}
 }
 
 // Send message to a specific queue
 func SendMsg(queue string, message []byte, channel *amqp.Channel, logger *logging.Logger) (error) {
+	return SendMessage(queue, message, channel, NewGoLoggingLogger(logger))
+}
+
+// Send message to a specific queue, logging the failures with a Logger
+func SendMessage(queue string, message []byte, channel *amqp.Channel, logger Logger) (error) {
 	err := channel.Publish(
 		OpenbatonExchangeName,
 		queue,
@@ -184,7 +272,14 @@ func SendMsg(queue string, message []byte, channel *amqp.Channel, logger *loggin
 	return nil
 }
 
-func amqpDial(amqpUri string, timeout time.Duration) (*amqp.Connection, error) {
+//Connect to the broker of the options with their user, e.g. to send requests to the managers
+func Dial(o *Options) (*amqp.Connection, error) {
+	uri := getAmqpUri(o.Username, o.Password, o.BrokerIp, o.BrokerPort, o.TLS != nil)
+	o.logger().Debugf("dialing %s", maskedAmqpUri(uri))
+	return amqpDial(uri, time.Duration(o.Timeout)*time.Second, o.TLS)
+}
+
+func amqpDial(amqpUri string, timeout time.Duration, tlsConfig *tls.Config) (*amqp.Connection, error) {
 	conn := make(chan *amqp.Connection)
 	err := make(chan error)
 	go func() {
@@ -192,6 +287,7 @@ func amqpDial(amqpUri string, timeout time.Duration) (*amqp.Connection, error) {
 			Dial: func(network, addr string) (net.Conn, error) {
 				return net.DialTimeout(network, addr, timeout)
 			},
+			TLSClientConfig: tlsConfig,
 		})
 		if e != nil {
 			err <- e
@@ -206,7 +302,7 @@ func amqpDial(amqpUri string, timeout time.Duration) (*amqp.Connection, error) {
 	case e := <-err:
 		return nil, e
 	case <-time.After(timeout):
-		return nil, errors.New(fmt.Sprintf("timeout dialing %s", amqpUri))
+		return nil, errors.New(fmt.Sprintf("timeout dialing %s", maskedAmqpUri(amqpUri)))
 	}
 }
 
@@ -1,6 +1,7 @@
 package vnfmsdk
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -11,34 +12,99 @@ import (
 )
 
 //Handler function for the VNFMs to be passed to the sdk package
-func handleNfvMessage(bytemsg []byte, handlerVnfm sdk.Handler, allocate bool, connection *amqp.Connection, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) ([]byte, error) {
-	logger := sdk.GetLogger("handler-function", "DEBUG")
+func handleNfvMessage(ctx context.Context, bytemsg []byte, handlerVnfm sdk.Handler, allocate, checkTransitions bool, connection *amqp.Connection, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) ([]byte, error) {
+	logger := sdk.LoggerFromContext(ctx).Module("handler")
 	n, err := messages.Unmarshal(bytemsg, messages.NFVO)
 	if err != nil {
 		logger.Errorf("Error while unmarshaling nfv message: %v", err)
 		err := sdk.NewSdkError(fmt.Sprintf("Error while unmarshaling nfv message: %v", err))
 		return nil, err
 	}
+	sdk.SetOperation(ctx, string(n.Action()))
+	annotate(ctx, n)
+	logger = sdk.LoggerFromContext(ctx).Module("handler")
 	logger.Debugf("Received Message %s", n.Action())
-	switch h := handlerVnfm.(type) {
+	ctx = withSteps(ctx)
+	var h HandlerVnfm
+	switch handler := handlerVnfm.(type) {
+	case HandlerVnfmV2:
+		h = &v2Handler{ctx: ctx, h: handler}
 	case HandlerVnfm:
-		wk := &worker{
-			l:          logger,
-			handler:    h,
-			Allocate:   allocate,
-			Connection: connection,
-		}
-		response := handleMessage(n, wk)
-		var byteRes []byte
-		resp, err := json.Marshal(response)
-		if err != nil {
-			logger.Errorf("Error while marshaling response: %v", err)
-			return nil, err
-		}
-		byteRes = []byte(resp)
-		return byteRes, nil
+		h = handler
+	default:
+		return nil, sdk.NewSdkError("Not a HandlerVnfmV1 or HandlerVnfmV2 implementation")
+	}
+	wk := &worker{
+		ctx:              ctx,
+		l:                logger,
+		handler:          &tracingHandler{ctx: ctx, h: h},
+		Allocate:         allocate,
+		CheckTransitions: checkTransitions,
+		Connection:       connection,
+	}
+	response := handleMessage(n, wk)
+	var byteRes []byte
+	resp, err := json.Marshal(response)
+	if err != nil {
+		logger.Errorf("Error while marshaling response: %v", err)
+		return nil, err
+	}
+	byteRes = []byte(resp)
+	return byteRes, nil
+}
+
+//Set the IDs of the VNFR and NSR the message refers to on the span and logger of the delivery
+func annotate(ctx context.Context, msg messages.NFVMessage) {
+	var vnfrID, nsrID string
+	if vnfr := recordOf(msg.Content()); vnfr != nil {
+		vnfrID, nsrID = vnfr.ID, vnfr.ParentNsID
+	} else if instantiate, ok := msg.Content().(*messages.OrInstantiate); ok {
+		nsrID = instantiate.Extension["nsr-id"]
+	}
+	sdk.SetAttribute(ctx, sdk.AttributeVnfrID, vnfrID)
+	sdk.SetAttribute(ctx, sdk.AttributeNsrID, nsrID)
+	sdk.AddLogFields(ctx, sdk.LogFieldAction, msg.Action(), sdk.LogFieldVnfrID, vnfrID, sdk.LogFieldNsrID, nsrID)
+}
+
+//Find the VNFR a message from the NFVO refers to, nil if none
+func recordOf(content interface{}) *catalogue.VirtualNetworkFunctionRecord {
+	switch c := content.(type) {
+	case *messages.OrError:
+		return c.VNFR
+	case *messages.OrGeneric:
+		return c.VNFR
+	case *messages.OrGrantLifecycleOperation:
+		return c.VNFR
+	case *messages.OrHealVNFRequest:
+		return c.VNFR
+	case *messages.OrInstantiate:
+		return c.VNFR
+	case *messages.OrScaling:
+		return c.VNFR
+	case *messages.OrStartStop:
+		return c.VNFR
+	case *messages.OrUpdate:
+		return c.VNFR
+	default:
+		return nil
+	}
+}
+
+//Find the VNFR a reply to the NFVO carries, nil if none
+func replyRecordOf(content interface{}) *catalogue.VirtualNetworkFunctionRecord {
+	switch c := content.(type) {
+	case *messages.VNFMGeneric:
+		return c.VNFR
+	case *messages.VNFMHealed:
+		return c.VNFR
+	case *messages.VNFMInstantiate:
+		return c.VNFR
+	case *messages.VNFMScaled:
+		return c.VNFR
+	case *messages.VNFMStartStop:
+		return c.VNFR
 	default:
-		return nil, sdk.NewSdkError("Not a HandlerVnfm implementation")
+		return nil
 	}
 }
 
@@ -48,6 +114,12 @@ func handleMessage(nfvMessage messages.NFVMessage, worker *worker) messages.NFVM
 	var reply messages.NFVMessage
 	var err *vnfmError
 
+	//the handlers may modify the VNFR they are given
+	var before *catalogue.VirtualNetworkFunctionRecord
+	if worker.CheckTransitions {
+		before = recordOf(content).DeepCopy()
+	}
+
 	switch nfvMessage.Action() {
 
 	case catalogue.ActionConfigure:
@@ -58,9 +130,9 @@ func handleMessage(nfvMessage messages.NFVMessage, worker *worker) messages.NFVM
 		errorMessage := content.(*messages.OrError)
 		err = worker.handleError(errorMessage)
 
-	// case catalogue.ActionHeal:
-	// 	healMessage := content.(*messages.OrHealVNFRequest)
-	// 	reply, err = worker.handleHeal(healMessage)
+	case catalogue.ActionHeal:
+		healMessage := content.(*messages.OrHealVNFRequest)
+		reply, err = worker.handleHeal(healMessage)
 
 	case catalogue.ActionInstantiate:
 		instantiateMessage := content.(*messages.OrInstantiate)
@@ -98,19 +170,32 @@ func handleMessage(nfvMessage messages.NFVMessage, worker *worker) messages.NFVM
 		genericMessage := content.(*messages.OrGeneric)
 		reply, err = worker.handleReleaseResources(genericMessage)
 
-	// case catalogue.ActionResume:
-	// 	genericMessage := content.(*messages.OrGeneric)
-	// 	reply, err = worker.handleResume(genericMessage)
+	case catalogue.ActionResume:
+		genericMessage := content.(*messages.OrGeneric)
+		reply, err = worker.handleResume(genericMessage)
 
-	// case catalogue.ActionUpdate:
-	// 	updateMessage := content.(*messages.OrUpdate)
-	// 	reply, err = worker.handleUpdate(updateMessage)
+	case catalogue.ActionUpdate:
+		updateMessage := content.(*messages.OrUpdate)
+		reply, err = worker.handleUpdate(updateMessage)
 
 	default:
 		worker.l.Warning("received unsupported action")
 	}
+	if err == nil && reply != nil && worker.CheckTransitions {
+		if after := replyRecordOf(reply.Content()); after != nil {
+			if transitionErr := catalogue.RecordTransitions(before, after); transitionErr != nil {
+				err = &vnfmError{transitionErr.Error(), after, after.ParentNsID}
+			}
+		}
+	}
+	if err != nil {
+		worker.recordHistory(nfvMessage.Action(), err.vnfr, err.msg)
+	} else if reply != nil {
+		worker.recordHistory(nfvMessage.Action(), replyRecordOf(reply.Content()), "")
+	}
 	if err != nil {
 		worker.l.Errorf("%v", err)
+		sdk.ReportError(worker.ctx)
 		errorMsg, err := messages.New(catalogue.ActionError, &messages.VNFMError{
 			Exception: messages.JavaException{
 				DetailMessage:        err.msg,
@@ -134,3 +219,23 @@ func handleMessage(nfvMessage messages.NFVMessage, worker *worker) messages.NFVM
 
 	return reply
 }
+
+//Reply to a request the handler function failed on, e.g. because it could not be unmarshaled
+func errorReply(body []byte, handlerErr error) ([]byte, error) {
+	msg, err := messages.New(catalogue.ActionError, &messages.VNFMError{
+		Exception: messages.JavaException{
+			DetailMessage:        handlerErr.Error(),
+			StackTrace:           make([]messages.Trace, 0),
+			SuppressedExceptions: make([]string, 0),
+			InternalCause: messages.Cause{
+				DetailMessage:        handlerErr.Error(),
+				StackTrace:           make([]messages.Trace, 0),
+				SuppressedExceptions: make([]string, 0),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(msg)
+}
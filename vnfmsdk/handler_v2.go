@@ -0,0 +1,304 @@
+package vnfmsdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/openbaton/go-openbaton/sdk"
+)
+
+//The first version of the handler interface, every method must be implemented
+type HandlerVnfmV1 = HandlerVnfm
+
+//The second version of the handler interface: only Instantiate and Terminate are mandatory,
+//the other operations are optional capabilities (Configurer, Scaler, Starter...) the VNFM implements if it supports them.
+//The requests of the capabilities not implemented are answered with an error.
+//New operations are added as new capabilities, so they do not break the existing VNFMs.
+type HandlerVnfmV2 interface {
+	Instantiate(ctx context.Context, req *InstantiateRequest) (*catalogue.VirtualNetworkFunctionRecord, error)
+
+	//Terminate the VNF instance, releasing its resources
+	Terminate(ctx context.Context, vnfr *catalogue.VirtualNetworkFunctionRecord) (*catalogue.VirtualNetworkFunctionRecord, error)
+}
+
+type InstantiateRequest struct {
+	VNFR *catalogue.VirtualNetworkFunctionRecord
+	//The scripts of the VNF package, or the link to them
+	Scripts interface{}
+	//The VIM instances by virtual deployment unit
+	VimInstances map[string][]interface{}
+}
+
+//Configure a VNF instance once its dependencies are known
+type Configurer interface {
+	Configure(ctx context.Context, vnfr *catalogue.VirtualNetworkFunctionRecord) (*catalogue.VirtualNetworkFunctionRecord, error)
+}
+
+//Handle an error the NFVO reports on a VNF instance
+type ErrorHandler interface {
+	HandleError(ctx context.Context, vnfr *catalogue.VirtualNetworkFunctionRecord) error
+}
+
+//Make structural changes (e.g. configuration, topology, behaviour, redundancy model) to a VNF instance
+type Modifier interface {
+	Modify(ctx context.Context, vnfr *catalogue.VirtualNetworkFunctionRecord, dependency *catalogue.VNFRecordDependency) (*catalogue.VirtualNetworkFunctionRecord, error)
+}
+
+//Scale a VNF instance in or out
+type Scaler interface {
+	Scale(ctx context.Context, req *ScaleRequest) (*ScaleResult, error)
+}
+
+type ScaleRequest struct {
+	//catalogue.ActionScaleIn or catalogue.ActionScaleOut
+	Action catalogue.Action
+	VNFR   *catalogue.VirtualNetworkFunctionRecord
+	//The VNFC instance to remove, or the component to add
+	Component   catalogue.Component
+	VimInstance interface{}
+	Scripts     interface{}
+	Dependency  *catalogue.VNFRecordDependency
+}
+
+type ScaleResult struct {
+	VNFR *catalogue.VirtualNetworkFunctionRecord
+	//The VNFC instance added, nil when scaling in
+	VNFCInstance *catalogue.VNFCInstance
+}
+
+//A VNF instance, or one of its VNFC instances if VNFCInstance is not nil
+type StartStopRequest struct {
+	VNFR         *catalogue.VirtualNetworkFunctionRecord
+	VNFCInstance *catalogue.VNFCInstance
+}
+
+type Starter interface {
+	Start(ctx context.Context, req *StartStopRequest) (*catalogue.VirtualNetworkFunctionRecord, error)
+}
+
+type Stopper interface {
+	Stop(ctx context.Context, req *StartStopRequest) (*catalogue.VirtualNetworkFunctionRecord, error)
+}
+
+type HealRequest struct {
+	VNFR         *catalogue.VirtualNetworkFunctionRecord
+	VNFCInstance *catalogue.VNFCInstance
+	Cause        string
+}
+
+type Healer interface {
+	Heal(ctx context.Context, req *HealRequest) (*catalogue.VirtualNetworkFunctionRecord, error)
+}
+
+type ResumeRequest struct {
+	VNFR         *catalogue.VirtualNetworkFunctionRecord
+	VNFCInstance *catalogue.VNFCInstance
+	Dependency   *catalogue.VNFRecordDependency
+}
+
+//Resume a VNF instance, ActionForResume returns catalogue.NoActionSpecified if there is nothing to resume
+type Resumer interface {
+	ActionForResume(ctx context.Context, req *ResumeRequest) catalogue.Action
+	Resume(ctx context.Context, req *ResumeRequest) (*catalogue.VirtualNetworkFunctionRecord, error)
+}
+
+//Apply a minor software update (e.g. a patch) to a VNF instance
+type SoftwareUpdater interface {
+	UpdateSoftware(ctx context.Context, script *catalogue.Script, vnfr *catalogue.VirtualNetworkFunctionRecord) (*catalogue.VirtualNetworkFunctionRecord, error)
+}
+
+//Verify that the instantiation is possible, assumed if not implemented
+type FeasibilityChecker interface {
+	CheckInstantiationFeasibility(ctx context.Context) error
+}
+
+//The user data of the VNFC instances created by the NFVO, empty if not implemented
+type UserDataProvider interface {
+	UserData(ctx context.Context) string
+}
+
+//The actions dispatched to the handler and whether a handler supports them
+var actionCapabilities = []struct {
+	action    catalogue.Action
+	supported func(h HandlerVnfmV2) bool
+}{
+	{catalogue.ActionConfigure, func(h HandlerVnfmV2) bool { _, ok := h.(Configurer); return ok }},
+	{catalogue.ActionError, func(h HandlerVnfmV2) bool { _, ok := h.(ErrorHandler); return ok }},
+	{catalogue.ActionHeal, func(h HandlerVnfmV2) bool { _, ok := h.(Healer); return ok }},
+	{catalogue.ActionInstantiate, func(h HandlerVnfmV2) bool { return true }},
+	{catalogue.ActionModify, func(h HandlerVnfmV2) bool { _, ok := h.(Modifier); return ok }},
+	{catalogue.ActionReleaseResources, func(h HandlerVnfmV2) bool { return true }},
+	{catalogue.ActionResume, func(h HandlerVnfmV2) bool { _, ok := h.(Resumer); return ok }},
+	{catalogue.ActionScaleIn, func(h HandlerVnfmV2) bool { _, ok := h.(Scaler); return ok }},
+	{catalogue.ActionScaleOut, func(h HandlerVnfmV2) bool { _, ok := h.(Scaler); return ok }},
+	{catalogue.ActionStart, func(h HandlerVnfmV2) bool { _, ok := h.(Starter); return ok }},
+	{catalogue.ActionStop, func(h HandlerVnfmV2) bool { _, ok := h.(Stopper); return ok }},
+	{catalogue.ActionUpdate, func(h HandlerVnfmV2) bool { _, ok := h.(SoftwareUpdater); return ok }},
+}
+
+//The version of the handler interface h implements (1 or 2, 0 if none) and the actions it supports
+func Capabilities(h sdk.Handler) (int, []catalogue.Action) {
+	var actions []catalogue.Action
+	switch h := h.(type) {
+	case HandlerVnfmV2:
+		for _, c := range actionCapabilities {
+			if c.supported(h) {
+				actions = append(actions, c.action)
+			}
+		}
+		return 2, actions
+	case HandlerVnfmV1:
+		for _, c := range actionCapabilities {
+			actions = append(actions, c.action)
+		}
+		return 1, actions
+	default:
+		return 0, nil
+	}
+}
+
+//Advertise the handler version and capabilities in the metadata of the options
+func setCapabilities(o *sdk.Options, h sdk.Handler) {
+	version, actions := Capabilities(h)
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = string(a)
+	}
+	if o.Metadata == nil {
+		o.Metadata = make(map[string]string)
+	}
+	o.Metadata[sdk.MetadataHandlerVersion] = fmt.Sprint(version)
+	o.Metadata[sdk.MetadataCapabilities] = strings.Join(names, ",")
+}
+
+func unsupported(operation string) error {
+	return sdk.NewSdkError(fmt.Sprintf("%s is not supported by this VNFM", operation))
+}
+
+//Adapts a HandlerVnfmV2 to the first version used by the worker, for the request being handled in ctx
+type v2Handler struct {
+	ctx context.Context
+	h   HandlerVnfmV2
+}
+
+func (v *v2Handler) ActionForResume(vnfr *catalogue.VirtualNetworkFunctionRecord, vnfcInstance *catalogue.VNFCInstance) catalogue.Action {
+	if r, ok := v.h.(Resumer); ok {
+		return r.ActionForResume(v.ctx, &ResumeRequest{VNFR: vnfr, VNFCInstance: vnfcInstance})
+	}
+	return catalogue.NoActionSpecified
+}
+
+func (v *v2Handler) CheckInstantiationFeasibility() error {
+	if c, ok := v.h.(FeasibilityChecker); ok {
+		return c.CheckInstantiationFeasibility(v.ctx)
+	}
+	return nil
+}
+
+func (v *v2Handler) Configure(vnfr *catalogue.VirtualNetworkFunctionRecord) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	if c, ok := v.h.(Configurer); ok {
+		return c.Configure(v.ctx, vnfr)
+	}
+	return nil, unsupported("Configure")
+}
+
+func (v *v2Handler) HandleError(vnfr *catalogue.VirtualNetworkFunctionRecord) error {
+	if e, ok := v.h.(ErrorHandler); ok {
+		return e.HandleError(v.ctx, vnfr)
+	}
+	return unsupported("HandleError")
+}
+
+func (v *v2Handler) Heal(vnfr *catalogue.VirtualNetworkFunctionRecord, component *catalogue.VNFCInstance, cause string) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	if h, ok := v.h.(Healer); ok {
+		return h.Heal(v.ctx, &HealRequest{VNFR: vnfr, VNFCInstance: component, Cause: cause})
+	}
+	return nil, unsupported("Heal")
+}
+
+func (v *v2Handler) Instantiate(vnfr *catalogue.VirtualNetworkFunctionRecord, scripts interface{}, vimInstances map[string][]interface{}) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	return v.h.Instantiate(v.ctx, &InstantiateRequest{VNFR: vnfr, Scripts: scripts, VimInstances: vimInstances})
+}
+
+func (v *v2Handler) Modify(vnfr *catalogue.VirtualNetworkFunctionRecord, dependency *catalogue.VNFRecordDependency) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	if m, ok := v.h.(Modifier); ok {
+		return m.Modify(v.ctx, vnfr, dependency)
+	}
+	return nil, unsupported("Modify")
+}
+
+func (v *v2Handler) Query() error {
+	return unsupported("Query")
+}
+
+func (v *v2Handler) Resume(vnfr *catalogue.VirtualNetworkFunctionRecord, vnfcInstance *catalogue.VNFCInstance, dependency *catalogue.VNFRecordDependency) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	if r, ok := v.h.(Resumer); ok {
+		return r.Resume(v.ctx, &ResumeRequest{VNFR: vnfr, VNFCInstance: vnfcInstance, Dependency: dependency})
+	}
+	return nil, unsupported("Resume")
+}
+
+func (v *v2Handler) Scale(chosenVimInstance interface{}, scaleInOrOut catalogue.Action, vnfr *catalogue.VirtualNetworkFunctionRecord, component catalogue.Component, scripts interface{}, dependency *catalogue.VNFRecordDependency) (*catalogue.VirtualNetworkFunctionRecord, *catalogue.VNFCInstance, error) {
+	s, ok := v.h.(Scaler)
+	if !ok {
+		return nil, nil, unsupported("Scale")
+	}
+	res, err := s.Scale(v.ctx, &ScaleRequest{
+		Action:      scaleInOrOut,
+		VNFR:        vnfr,
+		Component:   component,
+		VimInstance: chosenVimInstance,
+		Scripts:     scripts,
+		Dependency:  dependency,
+	})
+	if err != nil || res == nil {
+		return nil, nil, err
+	}
+	return res.VNFR, res.VNFCInstance, nil
+}
+
+func (v *v2Handler) Start(vnfr *catalogue.VirtualNetworkFunctionRecord) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	return v.StartVNFCInstance(vnfr, nil)
+}
+
+func (v *v2Handler) StartVNFCInstance(vnfr *catalogue.VirtualNetworkFunctionRecord, vnfcInstance *catalogue.VNFCInstance) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	if s, ok := v.h.(Starter); ok {
+		return s.Start(v.ctx, &StartStopRequest{VNFR: vnfr, VNFCInstance: vnfcInstance})
+	}
+	return nil, unsupported("Start")
+}
+
+func (v *v2Handler) Stop(vnfr *catalogue.VirtualNetworkFunctionRecord) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	return v.StopVNFCInstance(vnfr, nil)
+}
+
+func (v *v2Handler) StopVNFCInstance(vnfr *catalogue.VirtualNetworkFunctionRecord, vnfcInstance *catalogue.VNFCInstance) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	if s, ok := v.h.(Stopper); ok {
+		return s.Stop(v.ctx, &StartStopRequest{VNFR: vnfr, VNFCInstance: vnfcInstance})
+	}
+	return nil, unsupported("Stop")
+}
+
+func (v *v2Handler) Terminate(vnfr *catalogue.VirtualNetworkFunctionRecord) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	return v.h.Terminate(v.ctx, vnfr)
+}
+
+func (v *v2Handler) UpdateSoftware(script *catalogue.Script, vnfr *catalogue.VirtualNetworkFunctionRecord) (*catalogue.VirtualNetworkFunctionRecord, error) {
+	if u, ok := v.h.(SoftwareUpdater); ok {
+		return u.UpdateSoftware(v.ctx, script, vnfr)
+	}
+	return nil, unsupported("UpdateSoftware")
+}
+
+func (v *v2Handler) UpgradeSoftware() error {
+	return unsupported("UpgradeSoftware")
+}
+
+func (v *v2Handler) UserData() string {
+	if u, ok := v.h.(UserDataProvider); ok {
+		return u.UserData(v.ctx)
+	}
+	return ""
+}
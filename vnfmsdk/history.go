@@ -0,0 +1,80 @@
+package vnfmsdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+)
+
+type stepsKey struct{}
+
+//The sub-steps added by the handler while handling a request
+type steps struct {
+	events []*catalogue.HistoryLifecycleEvent
+}
+
+func withSteps(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stepsKey{}, &steps{})
+}
+
+func stepsFrom(ctx context.Context) *steps {
+	s, _ := ctx.Value(stepsKey{}).(*steps)
+	return s
+}
+
+//Record a sub-step of the request handled in ctx, e.g. from a HandlerVnfmV2. The sub-steps are appended to the
+//lifecycle event history of the VNFR replied to the NFVO, before the event of the action, whether it completes or fails.
+//HandlerVnfmV1 implementations can add them directly to the VNFR they return with AddHistoryEvent.
+func AddStep(ctx context.Context, event catalogue.Event, format string, args ...interface{}) {
+	if s := stepsFrom(ctx); s != nil {
+		s.events = append(s.events, catalogue.NewHistoryLifecycleEvent(event, fmt.Sprintf(format, args...)))
+	}
+}
+
+//The lifecycle event recorded in the history for an action
+func actionEvent(action catalogue.Action) catalogue.Event {
+	switch action {
+	case catalogue.ActionInstantiate:
+		return catalogue.EventInstantiate
+	case catalogue.ActionModify, catalogue.ActionConfigure:
+		return catalogue.EventConfigure
+	case catalogue.ActionStart:
+		return catalogue.EventStart
+	case catalogue.ActionStop:
+		return catalogue.EventStop
+	case catalogue.ActionScaleOut:
+		return catalogue.EventScaleOut
+	case catalogue.ActionScaleIn:
+		return catalogue.EventScaleIn
+	case catalogue.ActionReleaseResources:
+		return catalogue.EventTerminate
+	case catalogue.ActionHeal:
+		return catalogue.EventHeal
+	case catalogue.ActionUpdate:
+		return catalogue.EventUpdate
+	case catalogue.ActionError:
+		return catalogue.EventError
+	default:
+		return catalogue.Event(action)
+	}
+}
+
+//Append the sub-steps and the event of the action to the lifecycle event history of vnfr, and a line to its audit log.
+//failure is the reason the action failed, empty if it completed.
+func (worker *worker) recordHistory(action catalogue.Action, vnfr *catalogue.VirtualNetworkFunctionRecord, failure string) {
+	if vnfr == nil {
+		return
+	}
+
+	if s := stepsFrom(worker.ctx); s != nil {
+		vnfr.LifecycleEventHistory = append(vnfr.LifecycleEventHistory, s.events...)
+	}
+
+	description := fmt.Sprintf("%s completed", action)
+	if failure != "" {
+		description = fmt.Sprintf("%s failed: %s", action, failure)
+	}
+	vnfr.AddHistoryEvent(actionEvent(action), description)
+	vnfr.AppendAuditLog(catalogue.DefaultAuditLogLines, "%s", description)
+}
@@ -0,0 +1,135 @@
+package vnfmsdk
+
+import (
+	"context"
+
+	"github.com/openbaton/go-openbaton/catalogue"
+	"github.com/openbaton/go-openbaton/sdk"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//Wraps a HandlerVnfm creating a span for every invocation, child of the span of the delivery
+type tracingHandler struct {
+	ctx context.Context
+	h   HandlerVnfm
+}
+
+func (t *tracingHandler) start(name string) trace.Span {
+	_, span := sdk.StartSpan(t.ctx, "HandlerVnfm."+name)
+	return span
+}
+
+func (t *tracingHandler) end(span trace.Span, err error) {
+	if err != nil {
+		sdk.RecordError(span, err)
+	}
+	span.End()
+}
+
+func (t *tracingHandler) ActionForResume(vnfr *catalogue.VirtualNetworkFunctionRecord, vnfcInstance *catalogue.VNFCInstance) catalogue.Action {
+	span := t.start("ActionForResume")
+	defer t.end(span, nil)
+	return t.h.ActionForResume(vnfr, vnfcInstance)
+}
+
+func (t *tracingHandler) CheckInstantiationFeasibility() (err error) {
+	span := t.start("CheckInstantiationFeasibility")
+	defer func() { t.end(span, err) }()
+	return t.h.CheckInstantiationFeasibility()
+}
+
+func (t *tracingHandler) Configure(vnfr *catalogue.VirtualNetworkFunctionRecord) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("Configure")
+	defer func() { t.end(span, err) }()
+	return t.h.Configure(vnfr)
+}
+
+func (t *tracingHandler) HandleError(vnfr *catalogue.VirtualNetworkFunctionRecord) (err error) {
+	span := t.start("HandleError")
+	defer func() { t.end(span, err) }()
+	return t.h.HandleError(vnfr)
+}
+
+func (t *tracingHandler) Heal(vnfr *catalogue.VirtualNetworkFunctionRecord, component *catalogue.VNFCInstance, cause string) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("Heal")
+	defer func() { t.end(span, err) }()
+	return t.h.Heal(vnfr, component, cause)
+}
+
+func (t *tracingHandler) Instantiate(vnfr *catalogue.VirtualNetworkFunctionRecord, scripts interface{}, vimInstances map[string][]interface{}) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("Instantiate")
+	defer func() { t.end(span, err) }()
+	return t.h.Instantiate(vnfr, scripts, vimInstances)
+}
+
+func (t *tracingHandler) Modify(vnfr *catalogue.VirtualNetworkFunctionRecord, dependency *catalogue.VNFRecordDependency) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("Modify")
+	defer func() { t.end(span, err) }()
+	return t.h.Modify(vnfr, dependency)
+}
+
+func (t *tracingHandler) Query() (err error) {
+	span := t.start("Query")
+	defer func() { t.end(span, err) }()
+	return t.h.Query()
+}
+
+func (t *tracingHandler) Resume(vnfr *catalogue.VirtualNetworkFunctionRecord, vnfcInstance *catalogue.VNFCInstance, dependency *catalogue.VNFRecordDependency) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("Resume")
+	defer func() { t.end(span, err) }()
+	return t.h.Resume(vnfr, vnfcInstance, dependency)
+}
+
+func (t *tracingHandler) Scale(chosenVimInstance interface{}, scaleInOrOut catalogue.Action, vnfr *catalogue.VirtualNetworkFunctionRecord, component catalogue.Component, scripts interface{}, dependency *catalogue.VNFRecordDependency) (res *catalogue.VirtualNetworkFunctionRecord, instance *catalogue.VNFCInstance, err error) {
+	span := t.start("Scale")
+	defer func() { t.end(span, err) }()
+	return t.h.Scale(chosenVimInstance, scaleInOrOut, vnfr, component, scripts, dependency)
+}
+
+func (t *tracingHandler) Start(vnfr *catalogue.VirtualNetworkFunctionRecord) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("Start")
+	defer func() { t.end(span, err) }()
+	return t.h.Start(vnfr)
+}
+
+func (t *tracingHandler) StartVNFCInstance(vnfr *catalogue.VirtualNetworkFunctionRecord, vnfcInstance *catalogue.VNFCInstance) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("StartVNFCInstance")
+	defer func() { t.end(span, err) }()
+	return t.h.StartVNFCInstance(vnfr, vnfcInstance)
+}
+
+func (t *tracingHandler) Stop(vnfr *catalogue.VirtualNetworkFunctionRecord) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("Stop")
+	defer func() { t.end(span, err) }()
+	return t.h.Stop(vnfr)
+}
+
+func (t *tracingHandler) StopVNFCInstance(vnfr *catalogue.VirtualNetworkFunctionRecord, vnfcInstance *catalogue.VNFCInstance) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("StopVNFCInstance")
+	defer func() { t.end(span, err) }()
+	return t.h.StopVNFCInstance(vnfr, vnfcInstance)
+}
+
+func (t *tracingHandler) Terminate(vnfr *catalogue.VirtualNetworkFunctionRecord) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("Terminate")
+	defer func() { t.end(span, err) }()
+	return t.h.Terminate(vnfr)
+}
+
+func (t *tracingHandler) UpdateSoftware(script *catalogue.Script, vnfr *catalogue.VirtualNetworkFunctionRecord) (res *catalogue.VirtualNetworkFunctionRecord, err error) {
+	span := t.start("UpdateSoftware")
+	defer func() { t.end(span, err) }()
+	return t.h.UpdateSoftware(script, vnfr)
+}
+
+func (t *tracingHandler) UpgradeSoftware() (err error) {
+	span := t.start("UpgradeSoftware")
+	defer func() { t.end(span, err) }()
+	return t.h.UpgradeSoftware()
+}
+
+func (t *tracingHandler) UserData() string {
+	span := t.start("UserData")
+	defer t.end(span, nil)
+	return t.h.UserData()
+}
@@ -2,13 +2,13 @@
 package vnfmsdk
 
 import (
-	"encoding/json"
-	"os"
-	"os/signal"
+	"context"
+	"sync"
 
-	"github.com/BurntSushi/toml"
 	"github.com/openbaton/go-openbaton/catalogue"
 	"github.com/openbaton/go-openbaton/sdk"
+	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // The VNFM config struct
@@ -24,11 +24,37 @@ type VnfmConfig struct {
 	BrokerIp    string `toml:"brokerIp"`
 	BrokerPort  int    `toml:"brokerPort"`
 	Timeout     int    `toml:"timeout"`
+	//Address of the http server exposing the Prometheus metrics (e.g. ":9090"), disabled if empty
+	MetricsAddress string `toml:"metricsAddress"`
+	//Address of the http server exposing the /healthz and /readyz probes, disabled if empty.
+	//Can be the same as MetricsAddress.
+	HealthAddress string `toml:"healthAddress"`
+	//Exporter of the traces: "none" (default) or "stdout"
+	TraceExporter string `toml:"traceExporter"`
+	//Level of the log records by module (the manager name, "handler", "rpc", "credentials"), overriding LogLevel
+	LogLevels map[string]string `toml:"logLevels"`
+	//Format of the log records: "text" (default) or "json"
+	LogFormat string `toml:"logFormat"`
+	//File caching the credentials returned by the registration, reused on restart if still valid. Disabled if empty.
+	CredentialsFile string `toml:"credentialsFile"`
+	//Seconds between the heartbeats published to heartbeatExchange with heartbeatRoutingKey, disabled if 0
+	HeartbeatInterval   int    `toml:"heartbeatInterval"`
+	HeartbeatExchange   string `toml:"heartbeatExchange"`
+	HeartbeatRoutingKey string `toml:"heartbeatRoutingKey"`
+	//Exchange receiving the undecodable requests, and the queue bound to it. Disabled if empty.
+	DeadLetterExchange string `toml:"deadLetterExchange"`
+	DeadLetterQueue    string `toml:"deadLetterQueue"`
+	//File the requests and replies are appended to, to replay them with Replay. Disabled if empty.
+	CaptureFile string `toml:"captureFile"`
+	//Max number of requests handled at the same time, unlimited if 0
+	MaxInFlight int `toml:"maxInFlight"`
+	//Whether the changes of status of the VNFRs returned by the handler are checked, see WithTransitionCheck
+	CheckTransitions bool `toml:"checkTransitions"`
 }
 
-// Start the VNFM with config file
-func Start(confPath string, h HandlerVnfm, name string) (error) {
-	cfg := VnfmConfig{
+//The default VNFM configuration
+func DefaultConfig() VnfmConfig {
+	return VnfmConfig{
 		Type:        "unknown",
 		Workers:     5,
 		Allocate:    false,
@@ -40,22 +66,72 @@ func Start(confPath string, h HandlerVnfm, name string) (error) {
 		BrokerPort:  5672,
 		Timeout:     2,
 	}
-	cfg.Endpoint = cfg.Type
+}
+
+//Load the VNFM configuration merging the defaults, the file at confPath (TOML, YAML or JSON),
+//the OPENBATON_* environment variables and the command line flags in args (e.g. os.Args[1:]), then validate it.
+//confPath can be empty and args nil.
+func LoadConfig(confPath string, args []string) (*VnfmConfig, error) {
+	cfg := DefaultConfig()
+	if err := sdk.LoadConfig(&cfg, confPath, args); err != nil {
+		return nil, err
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = cfg.Type
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+//Check the configuration, returning all the problems found in a *sdk.ConfigError, see sdk.Options.Validate
+func (cfg *VnfmConfig) Validate() error {
+	return sdk.NewOptions(WithConfig(*cfg)).Validate()
+}
+
+// Start the VNFM with config file, reloaded on SIGHUP or when modified
+func Start(confPath string, h HandlerVnfm, name string, opts ...sdk.Option) (error) {
+	cfg, err := LoadConfig(confPath, nil)
+	if err != nil {
+		return err
+	}
+	return Run(*cfg, h, name, append([]sdk.Option{sdk.WithConfigFile(confPath)}, opts...)...)
+}
 
-	reader, err := os.Open(confPath)
-	defer reader.Close()
+//Start the VNFM with a configuration, e.g. returned by LoadConfig
+func Run(cfg VnfmConfig, h HandlerVnfm, name string, opts ...sdk.Option) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	vnfm, err := New(h, append([]sdk.Option{WithConfig(cfg), sdk.WithName(name)}, opts...)...)
 	if err != nil {
 		return err
 	}
-	if _, err := toml.DecodeReader(reader, &cfg); err != nil {
+	jsonCfg, err := sdk.MaskedConfig(cfg)
+	if err != nil {
 		return err
 	}
+	vnfm.logger.Debugf("Config are %s", jsonCfg)
+	return vnfm.Serve()
+}
 
-	return startWithCfg(cfg, name, h)
+//Feed the requests captured in the file at path (see sdk.WithCapture) to h, a HandlerVnfmV1 or HandlerVnfmV2, in-process without broker,
+//to reproduce a failure locally. The requests needing the NFVO, e.g. the grant if !allocate, fail.
+func Replay(ctx context.Context, path string, h sdk.Handler, allocate bool) ([]sdk.ReplayResult, error) {
+	records, err := sdk.ReadCaptureFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.Replay(ctx, records, func(ctx context.Context, body []byte) ([]byte, error) {
+		return handleNfvMessage(ctx, body, h, allocate, false, nil, nil, nil)
+	}), nil
 }
 
 // Start the VNFM with specific config
-func StartWithConfig(typ, description, username, password, loglevel, brokerIp string, brokerPort, workers, timeout int, allocate bool, h HandlerVnfm, name string) (error) {
+//
+//Deprecated: use New, whose options cannot be swapped silently.
+func StartWithConfig(typ, description, username, password, loglevel, brokerIp string, brokerPort, workers, timeout int, allocate bool, h HandlerVnfm, name string, opts ...sdk.Option) (error) {
 	cfg := VnfmConfig{
 		Type:        typ,
 		Workers:     workers,
@@ -70,65 +146,201 @@ func StartWithConfig(typ, description, username, password, loglevel, brokerIp st
 	}
 	cfg.Endpoint = cfg.Type
 
-	return startWithCfg(cfg, name, h)
+	return Run(cfg, h, name, opts...)
 }
 
-func startWithCfg(cfg VnfmConfig, name string, h HandlerVnfm) error {
-	logger := sdk.GetLogger(cfg.Type, cfg.LogLevel)
-	logger.Infof("Starting VNFM of type %s", cfg.Type)
-	jsonCfg, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return err
+//Apply all the settings of a configuration
+func WithConfig(cfg VnfmConfig) sdk.Option {
+	return func(o *sdk.Options) {
+		o.Type = cfg.Type
+		o.Endpoint = cfg.Endpoint
+		o.Description = cfg.Description
+		o.Allocate = cfg.Allocate
+		o.CheckTransitions = cfg.CheckTransitions
+		o.Workers = cfg.Workers
+		o.Username = cfg.Username
+		o.Password = cfg.Password
+		o.BrokerIp = cfg.BrokerIp
+		o.BrokerPort = cfg.BrokerPort
+		o.Timeout = cfg.Timeout
+		o.MaxInFlight = cfg.MaxInFlight
+		o.MetricsAddress = cfg.MetricsAddress
+		o.HealthAddress = cfg.HealthAddress
+		o.TraceExporter = cfg.TraceExporter
+		o.CredentialsFile = cfg.CredentialsFile
+		o.HeartbeatInterval = cfg.HeartbeatInterval
+		o.HeartbeatExchange = cfg.HeartbeatExchange
+		o.HeartbeatRoutingKey = cfg.HeartbeatRoutingKey
+		o.DeadLetterExchange = cfg.DeadLetterExchange
+		o.DeadLetterQueue = cfg.DeadLetterQueue
+		o.CaptureFile = cfg.CaptureFile
+		o.LogConfig = sdk.LogConfig{Level: cfg.LogLevel, Levels: cfg.LogLevels, Format: cfg.LogFormat}
+	}
+}
+
+//Set the endpoint (queue) of the VNFM, the type by default
+func WithEndpoint(endpoint string) sdk.Option {
+	return func(o *sdk.Options) {
+		o.Endpoint = endpoint
+	}
+}
+
+//Set the description of the VNFM shown by the NFVO
+func WithDescription(description string) sdk.Option {
+	return func(o *sdk.Options) {
+		o.Description = description
+	}
+}
+
+//Set whether the VNFM allocates the resources
+func WithAllocate(allocate bool) sdk.Option {
+	return func(o *sdk.Options) {
+		o.Allocate = allocate
+	}
+}
+
+//Check that the VNFRs returned by the handler only make the transitions allowed by catalogue.VNFRStates
+//and catalogue.VNFCStates, replying with an error otherwise, and append them to their lifecycle event history
+func WithTransitionCheck(check bool) sdk.Option {
+	return func(o *sdk.Options) {
+		o.CheckTransitions = check
 	}
-	logger.Debugf("Config are %s", jsonCfg)
+}
+
+//A VNFM, registered to the NFVO by Serve
+type Vnfm struct {
+	//A HandlerVnfmV1 or a HandlerVnfmV2
+	handler        sdk.Handler
+	options        *sdk.Options
+	logger         sdk.Logger
+	endpoint       catalogue.Endpoint
+	tracerProvider trace.TracerProvider
+	stopTracing    func(context.Context) error
+	creds          *catalogue.ManagerCredentials
+	manager        *sdk.Manager
+	//Guards manager, set by Serve and read by the supervisor
+	mutex        sync.Mutex
+	shutdownOnce sync.Once
+}
 
-	endpoint := catalogue.Endpoint{
-		Type:         cfg.Type,
-		Endpoint:     cfg.Endpoint,
-		Active:       true,
-		Description:  cfg.Description,
-		Enabled:      true,
-		EndpointType: "RABBIT",
+//Create a VNFM handled by h, nothing is sent to the broker until Serve is called
+func New(h HandlerVnfm, opts ...sdk.Option) (*Vnfm, error) {
+	return newVnfm(h, opts...)
+}
+
+//Create a VNFM with a handler of the second version, implementing only the capabilities it supports
+func NewV2(h HandlerVnfmV2, opts ...sdk.Option) (*Vnfm, error) {
+	return newVnfm(h, opts...)
+}
+
+func newVnfm(h sdk.Handler, opts ...sdk.Option) (*Vnfm, error) {
+	options := sdk.NewOptions(opts...)
+	if options.Endpoint == "" {
+		options.Endpoint = options.Type
+	}
+	if options.Description == "" {
+		options.Description = "The Vnfm written in go"
+	}
+	if options.ErrorReply == nil {
+		options.ErrorReply = errorReply
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
 	}
-	rabbitCredentials, err := sdk.GetVnfmCreds(cfg.Username, cfg.Password, cfg.BrokerIp, cfg.BrokerPort, cfg.Timeout, &endpoint, "DEBUG")
+	rootLogger, err := options.LoggerOrNew(options.LogConfig)
+	if err != nil {
+		return nil, err
+	}
+	options.Logger = rootLogger
+	setCapabilities(options, h)
+	tracerProvider, stopTracing, err := sdk.NewTracerProvider(options.TraceExporter)
+	if err != nil {
+		return nil, err
+	}
+	return &Vnfm{
+		handler:        h,
+		options:        options,
+		logger:         rootLogger.Module(options.Type),
+		tracerProvider: tracerProvider,
+		stopTracing:    stopTracing,
+		endpoint: catalogue.Endpoint{
+			Type:         options.Type,
+			Endpoint:     options.Endpoint,
+			Active:       true,
+			Description:  options.Description,
+			Enabled:      true,
+			EndpointType: "RABBIT",
+			Metadata:     options.Metadata,
+		},
+	}, nil
+}
+
+//Register the VNFM and handle the requests of the NFVO, until Shutdown is called or SIGINT or SIGTERM is received
+func (vnfm *Vnfm) Serve() error {
+	return vnfm.ServeContext(context.Background())
+}
 
+//Register the VNFM and handle the requests of the NFVO, until Shutdown is called, ctx is cancelled,
+//SIGINT or SIGTERM is received or a fatal error occurs. The VNFM is unregistered in all the cases.
+func (vnfm *Vnfm) ServeContext(ctx context.Context) error {
+	vnfm.logger.Infof("Starting VNFM of type %s", vnfm.options.Type)
+	rabbitCredentials, err := sdk.RegisterVnfm(&vnfm.endpoint, vnfm.options)
 	if err != nil {
-		logger.Errorf("Error getting credentials: %v", err)
+		vnfm.logger.Errorf("Error getting credentials: %v", err)
 		return err
 	}
+	vnfm.creds = rabbitCredentials
 
-	manager, err := sdk.NewManager(
-		h,
-		rabbitCredentials.RabbitUsername,
-		rabbitCredentials.RabbitPassword,
-		cfg.BrokerIp,
-		cfg.BrokerPort,
-		sdk.OpenbatonExchangeName,
-		endpoint.Endpoint,
-		cfg.Workers,
-		cfg.Allocate,
-		name,
-		handleNfvMessage,
-		"DEBUG",
-		nil,
-		nil,
-	)
+	checkTransitions := vnfm.options.CheckTransitions
+	handle := func(ctx context.Context, body []byte, h sdk.Handler, allocate bool, connection *amqp.Connection, net catalogue.BaseNetworkInt, img catalogue.BaseImageInt) ([]byte, error) {
+		return handleNfvMessage(ctx, body, h, allocate, checkTransitions, connection, net, img)
+	}
+	manager, err := sdk.NewManagerWithOptions(vnfm.handler, rabbitCredentials, vnfm.endpoint.Endpoint, handle, vnfm.options)
 	if err != nil {
-		logger.Errorf("Error while creating vnfm: %v", err)
+		vnfm.logger.Errorf("Error while creating vnfm: %v", err)
 		return err
 	}
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		for range c {
-			logger.Infof("Received ctrl-c, unregistering")
-			manager.Unregister(cfg.Type, rabbitCredentials.RabbitUsername, rabbitCredentials.RabbitPassword, &endpoint)
-			go manager.Shutdown()
-			os.Exit(0)
-		}
-	}()
+	manager.SetTracerProvider(vnfm.tracerProvider)
+	manager.SetRegistered(true)
+	vnfm.mutex.Lock()
+	vnfm.manager = manager
+	vnfm.mutex.Unlock()
+	if path := vnfm.options.ConfigFile; path != "" {
+		manager.WatchConfig(path, func() ([]sdk.Option, error) {
+			cfg, err := LoadConfig(path, nil)
+			if err != nil {
+				return nil, err
+			}
+			return []sdk.Option{WithConfig(*cfg)}, nil
+		})
+	}
 
-	manager.Serve()
+	return manager.ServeContext(ctx, func() { vnfm.Shutdown() })
+}
 
+//Unregister the VNFM and stop serving
+func (vnfm *Vnfm) Shutdown() error {
+	manager := vnfm.Manager()
+	if manager == nil {
+		return nil
+	}
+	var err error
+	vnfm.shutdownOnce.Do(func() {
+		manager.Unregister(vnfm.options.Type, vnfm.creds.RabbitUsername, vnfm.creds.RabbitPassword, &vnfm.endpoint)
+		vnfm.stopTracing(context.Background())
+		err = manager.Shutdown()
+	})
 	return err
 }
+
+//The manager consuming the requests, nil until Serve is called
+func (vnfm *Vnfm) Manager() *sdk.Manager {
+	vnfm.mutex.Lock()
+	defer vnfm.mutex.Unlock()
+	return vnfm.manager
+}
+
+//The options the VNFM was created with, to be changed only before Serve is called
+func (vnfm *Vnfm) Options() *sdk.Options {
+	return vnfm.options
+}
@@ -21,6 +21,7 @@ import (
 )
 
 // The Handler interface defines an abstraction of the operations that a VNFM should provide.
+// A HandlerVnfm can also implement sdk.HealthChecker to take part in the readiness probe.
 type HandlerVnfm interface {
 	// ActionForResume uses the given VNFR and VNFCInstance to return a valid
 	// action for resume. NoSuchAction is returned in case no such Action exists.
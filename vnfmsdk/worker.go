@@ -1,10 +1,10 @@
 package vnfmsdk
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
-	"github.com/op/go-logging"
 	"github.com/openbaton/go-openbaton/catalogue"
 	"github.com/openbaton/go-openbaton/catalogue/messages"
 	"github.com/openbaton/go-openbaton/sdk"
@@ -13,10 +13,13 @@ import (
 
 //The worker struct allows the VNFM SDK to invoke implementation specific of VNFMs
 type worker struct {
-	l          *logging.Logger
-	handler    HandlerVnfm
-	Allocate   bool
-	Connection *amqp.Connection
+	ctx      context.Context
+	l        sdk.Logger
+	handler  HandlerVnfm
+	Allocate bool
+	//Whether the transitions of the VNFRs returned by the handler are checked and recorded
+	CheckTransitions bool
+	Connection       *amqp.Connection
 }
 
 type vnfmError struct {
@@ -72,9 +75,9 @@ func (worker *worker) handleHeal(healMessage *messages.OrHealVNFRequest) (messag
 
 func (worker *worker) handleInstantiate(instantiateMessage *messages.OrInstantiate) (messages.NFVMessage, *vnfmError) {
 
-	worker.l.Debug("received extensions: %v", instantiateMessage.Extension)
+	worker.l.Debugf("received extensions: %v", instantiateMessage.Extension)
 
-	worker.l.Debug("received keys: %v", instantiateMessage.Keys)
+	worker.l.Debugf("received keys: %v", instantiateMessage.Keys)
 
 	vimInstances := instantiateMessage.VIMInstances
 
@@ -183,7 +186,8 @@ func (worker *worker) handleInstantiate(instantiateMessage *messages.OrInstantia
 }
 
 func (worker *worker) executeRpc(queue string, message messages.NFVMessage) (messages.NFVMessage, error) {
-	body, err := sdk.Rpc(
+	body, err := sdk.RpcContext(
+		worker.ctx,
 		queue,
 		message,
 		worker.Connection,